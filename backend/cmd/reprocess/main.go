@@ -0,0 +1,90 @@
+// Command reprocess parses archived pages again, without any network access, so records can be
+// regenerated after a parser fix. It either runs a finished scrape job again over the pages it
+// archived, or rebuilds an ocean's market history from the CSVs archived since a day (UTC):
+//
+//	reprocess -job 42
+//	reprocess -market emerald -since 2026-10-01
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"cutlass_analytics/internal/config"
+	"cutlass_analytics/internal/database"
+	"cutlass_analytics/internal/poller"
+	"cutlass_analytics/internal/scraper"
+	"cutlass_analytics/internal/types"
+)
+
+func main() {
+	jobID := flag.Uint("job", 0, "ID of the scrape job to reprocess")
+	market := flag.String("market", "", "ocean whose market history to rebuild")
+	since := flag.String("since", "", "day (YYYY-MM-DD) to rebuild the market history from")
+	flag.Parse()
+
+	var sinceDate time.Time
+	switch {
+	case *jobID != 0 && *market == "":
+	case *jobID == 0 && *market != "":
+		if !types.Ocean(*market).IsValid() {
+			log.Fatalf("Unknown ocean %q", *market)
+		}
+		var err error
+		sinceDate, err = time.Parse("2006-01-02", *since)
+		if err != nil {
+			log.Fatalf("Invalid -since day %q: %v", *since, err)
+		}
+	default:
+		log.Fatal("Usage: reprocess -job <scrape job ID> | reprocess -market <ocean> -since <YYYY-MM-DD>")
+	}
+
+	cfg := config.Load()
+
+	db, err := database.Connect(cfg.DSN())
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer func() {
+		if err := database.Close(); err != nil {
+			log.Printf("Error closing database connection: %v", err)
+		}
+	}()
+
+	if err := database.AutoMigrate(db); err != nil {
+		log.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	if *market != "" {
+		ocean := types.Ocean(*market)
+		// Reprocessing reads the archive only, so the poller needs no fetcher
+		csvPoller := poller.NewCSVPoller(db, nil, []types.Ocean{ocean})
+		if err := csvPoller.Reprocess(ocean, sinceDate); err != nil {
+			log.Fatalf("Reprocessing failed: %v", err)
+		}
+		return
+	}
+
+	reprocessor, err := scraper.NewReprocessor(db, *jobID)
+	if err != nil {
+		log.Fatalf("Failed to start reprocessing: %v", err)
+	}
+
+	job := reprocessor.Job()
+	log.Printf("Reprocessing scrape job %d as job %d (%s, %s)", *job.ReprocessedJobID, job.ID, job.JobType, job.Ocean)
+	// Interrupting the command cancels the job instead of leaving it running
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := reprocessor.Run(ctx); err != nil {
+		log.Fatalf("Reprocessing failed: %v", err)
+	}
+
+	job = reprocessor.Job()
+	log.Printf("Job %d %s: %d items processed, %d failed", job.ID, job.Status, job.ItemsProcessed, job.ItemsFailed)
+}
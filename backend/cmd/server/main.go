@@ -12,6 +12,7 @@ import (
 	"cutlass_analytics/internal/api"
 	"cutlass_analytics/internal/config"
 	"cutlass_analytics/internal/database"
+	"cutlass_analytics/internal/fetcher"
 	"cutlass_analytics/internal/jobs"
 )
 
@@ -40,16 +41,18 @@ func main() {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
 
-	// Initialize and start scheduler (includes daily scraper and CSV poller)
-	scheduler := jobs.NewScheduler(db)
+	fetchOptions := fetcher.Options{Mode: cfg.FetchMode, Dir: cfg.FetchDir}
+	if err := fetchOptions.Validate(); err != nil {
+		log.Fatalf("Invalid fetch configuration: %v", err)
+	}
+
+	// Initialize and start scheduler (includes scheduled scrapes, interrupted job recovery and CSV poller)
+	scheduler := jobs.NewScheduler(db, fetchOptions)
 	if err := scheduler.Start(); err != nil {
 		log.Fatalf("Failed to start scheduler: %v", err)
 	}
 	log.Println("Scheduler started successfully")
 
-	// Run jobs once on server startup
-	scheduler.RunOnce()
-
 	defer func() {
 		log.Println("Stopping scheduler...")
 		scheduler.Stop()
@@ -60,7 +63,7 @@ func main() {
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
 	// Create HTTP server
-	router := api.NewRouter(db)
+	router := api.NewRouter(db, scheduler, cfg.AdminAPIKey)
 	srv := &http.Server{
 		Addr:    ":" + cfg.BackendPort,
 		Handler: router,
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"cutlass_analytics/internal/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requireAPIKey rejects requests that don't carry apiKey as a bearer token. With no key
+// configured every request is rejected, which keeps the guarded endpoints disabled.
+func requireAPIKey(apiKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || apiKey == "" || subtle.ConstantTimeCompare([]byte(token), []byte(apiKey)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "UNAUTHORIZED",
+					Message: "A valid API key is required",
+				},
+			})
+			return
+		}
+		c.Next()
+	}
+}
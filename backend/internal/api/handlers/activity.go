@@ -0,0 +1,602 @@
+package handlers
+
+import (
+	"cutlass_analytics/internal/dto"
+	"cutlass_analytics/internal/models"
+	"cutlass_analytics/internal/repositories"
+	"cutlass_analytics/internal/types"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// activityPeriods maps each activity summary period to its number of days
+var activityPeriods = map[string]int{
+	"7d":  7,
+	"30d": 30,
+	"90d": 90,
+}
+
+// activityTopCrewsLimit is how many of the most active crews a digest lists
+const activityTopCrewsLimit = 5
+
+func GetDailyActivityHandler(c *gin.Context, db *gorm.DB) {
+	var req dto.DailyActivityRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	day, err := req.ParsedDate()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid date, expected YYYY-MM-DD",
+			},
+		})
+		return
+	}
+	nextDay := day.AddDate(0, 0, 1)
+	ocean := types.Ocean(req.Ocean)
+
+	repo := repositories.NewActivityRepository(db)
+	totals, err := repo.GetDailyTotals(ocean, day, nextDay)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch daily activity",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	// The full list of changes is only loaded when asked for; otherwise the most active crews are enough
+	limit := activityTopCrewsLimit
+	if req.IncludeChanges {
+		limit = -1
+	}
+	pvpChanges, _, err := repo.GetCrewPVPChanges(ocean, day, nextDay, 0, 0, 0, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch crew changes",
+			},
+		})
+		return
+	}
+	changes, err := toDailyPVPChanges(db, pvpChanges)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch crews",
+			},
+		})
+		return
+	}
+
+	newCrews, err := repo.GetNewCrews(ocean, day, nextDay)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch new crews",
+			},
+		})
+		return
+	}
+	vanishedCrews, err := repo.GetVanishedCrews(ocean, day, nextDay)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch vanished crews",
+			},
+		})
+		return
+	}
+	newFlags, err := repo.GetNewFlags(ocean, day, nextDay)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch new flags",
+			},
+		})
+		return
+	}
+	vanishedFlags, err := repo.GetVanishedFlags(ocean, day, nextDay)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch vanished flags",
+			},
+		})
+		return
+	}
+	governanceChanges, err := repo.GetGovernanceChanges(ocean, day, nextDay)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch governance changes",
+			},
+		})
+		return
+	}
+	governance, err := toIslandGovernanceChanges(db, governanceChanges)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch islands",
+			},
+		})
+		return
+	}
+
+	response := dto.DailyActivityResponse{
+		Ocean:             req.Ocean,
+		Date:              day,
+		MostActiveCrews:   mostActiveCrews(changes),
+		NewCrews:          toCrewBriefs(newCrews),
+		VanishedCrews:     toCrewBriefs(vanishedCrews),
+		NewFlags:          toFlagBriefs(newFlags),
+		VanishedFlags:     toFlagBriefs(vanishedFlags),
+		GovernanceChanges: governance,
+	}
+	if len(totals) > 0 {
+		response.TotalWins = totals[0].TotalWins
+		response.TotalLosses = totals[0].TotalLosses
+		response.ActiveCrews = totals[0].ActiveCrews
+		response.TotalBattles = totals[0].TotalWins + totals[0].TotalLosses
+	}
+	if req.IncludeChanges {
+		response.Changes = changes
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func GetDailyChangesHandler(c *gin.Context, db *gorm.DB) {
+	var req dto.DailyChangesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+	req.SetDefaults()
+
+	day, err := req.ParsedDate()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid date, expected YYYY-MM-DD",
+			},
+		})
+		return
+	}
+
+	repo := repositories.NewActivityRepository(db)
+	pvpChanges, total, err := repo.GetCrewPVPChanges(types.Ocean(req.Ocean), day, day.AddDate(0, 0, 1), req.MinWins, req.MinLosses, req.Offset(), req.Limit())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch crew changes",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+	changes, err := toDailyPVPChanges(db, pvpChanges)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch crews",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.DailyChangesResponse{
+		Ocean:      req.Ocean,
+		Date:       day,
+		Changes:    changes,
+		Pagination: buildPagination(total, req.Page, req.PerPage),
+	})
+}
+
+func GetActivitySummaryHandler(c *gin.Context, db *gorm.DB) {
+	var req dto.ActivitySummaryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+	req.SetDefaults()
+
+	// The period covers whole UTC days, ending with today
+	endDate := utcDay(time.Now()).AddDate(0, 0, 1)
+	startDate := endDate.AddDate(0, 0, -activityPeriods[req.Period])
+	ocean := types.Ocean(req.Ocean)
+
+	repo := repositories.NewActivityRepository(db)
+	totals, err := repo.GetDailyTotals(ocean, startDate, endDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch daily activity",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+	lifecycle, err := repo.GetDailyLifecycleCounts(ocean, startDate, endDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch crew and flag changes",
+			},
+		})
+		return
+	}
+	pvpChanges, _, err := repo.GetCrewPVPChanges(ocean, startDate, endDate, 0, 0, 0, activityTopCrewsLimit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch crew changes",
+			},
+		})
+		return
+	}
+	changes, err := toDailyPVPChanges(db, pvpChanges)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch crews",
+			},
+		})
+		return
+	}
+
+	days := comparisonDays(startDate, endDate)
+	dayIndex := comparisonDayIndex(days)
+	daily := make([]dto.DailyActivitySummary, len(days))
+	for i, day := range days {
+		daily[i].Date = day
+	}
+	for _, total := range totals {
+		if i, ok := dayIndex[utcDay(total.Date)]; ok {
+			daily[i].TotalWins = total.TotalWins
+			daily[i].TotalLosses = total.TotalLosses
+			daily[i].TotalBattles = total.TotalWins + total.TotalLosses
+			daily[i].ActiveCrews = total.ActiveCrews
+		}
+	}
+	for _, counts := range lifecycle {
+		if i, ok := dayIndex[utcDay(counts.Date)]; ok {
+			daily[i].NewCrews = counts.NewCrews
+			daily[i].VanishedCrews = counts.VanishedCrews
+			daily[i].NewFlags = counts.NewFlags
+			daily[i].VanishedFlags = counts.VanishedFlags
+			daily[i].GovernanceChanges = counts.GovernanceChanges
+		}
+	}
+
+	response := dto.OceanActivityResponse{
+		Ocean:           req.Ocean,
+		Period:          req.Period,
+		StartDate:       startDate,
+		EndDate:         endDate,
+		DailyActivity:   daily,
+		MostActiveCrews: mostActiveCrews(changes),
+	}
+	mostActive, leastActive := 0, 0
+	for i, summary := range daily {
+		response.TotalBattles += summary.TotalBattles
+		response.TotalWins += summary.TotalWins
+		response.TotalLosses += summary.TotalLosses
+		response.NewCrews += summary.NewCrews
+		response.VanishedCrews += summary.VanishedCrews
+		response.NewFlags += summary.NewFlags
+		response.VanishedFlags += summary.VanishedFlags
+		response.GovernanceChanges += summary.GovernanceChanges
+
+		if summary.TotalBattles > daily[mostActive].TotalBattles {
+			mostActive = i
+		}
+		if summary.TotalBattles < daily[leastActive].TotalBattles {
+			leastActive = i
+		}
+	}
+	if len(daily) > 0 {
+		response.AvgDailyBattles = float64(response.TotalBattles) / float64(len(daily))
+		response.MostActiveDay = daily[mostActive].Date.Format("2006-01-02")
+		response.LeastActiveDay = daily[leastActive].Date.Format("2006-01-02")
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func GetRankChangesHandler(c *gin.Context, db *gorm.DB) {
+	var req dto.RankChangesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+	req.SetDefaults()
+
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	startDate, endDate := dateRangeBounds(req.DateRangeParams)
+	direction := rankChangeDirection(req.PromotionsOnly, req.DemotionsOnly)
+
+	repo := repositories.NewActivityRepository(db)
+	changes, total, err := repo.GetRankChanges(types.Ocean(req.Ocean), startDate, endDate, direction, req.Offset(), req.Limit())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch rank changes",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	crewIDs := make([]uint, 0, len(changes))
+	seen := make(map[uint]bool, len(changes))
+	for _, change := range changes {
+		if !seen[change.CrewID] {
+			seen[change.CrewID] = true
+			crewIDs = append(crewIDs, change.CrewID)
+		}
+	}
+
+	crews, err := repositories.NewCrewRepository(db).FindByIDs(crewIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch crews",
+			},
+		})
+		return
+	}
+
+	crewsByID := make(map[uint]*models.Crew, len(crews))
+	for i := range crews {
+		crewsByID[crews[i].ID] = &crews[i]
+	}
+
+	entries := make([]dto.CrewRankChangeResponse, len(changes))
+	for i, change := range changes {
+		entry := dto.CrewRankChangeResponse{
+			Crew:        dto.CrewBrief{ID: change.CrewID},
+			Date:        change.ScrapedAt,
+			FromRank:    string(change.FromRank),
+			ToRank:      string(change.ToRank),
+			IsPromotion: change.IsPromotion(),
+		}
+		if crew, ok := crewsByID[change.CrewID]; ok {
+			entry.Crew = toCrewBrief(crew)
+			if crew.Flag != nil {
+				entry.FlagName = crew.Flag.Name
+			}
+		}
+		entries[i] = entry
+	}
+
+	c.JSON(http.StatusOK, dto.RecentRankChangesResponse{
+		Ocean:      req.Ocean,
+		Period:     rangePeriod(startDate, endDate),
+		Changes:    entries,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Pagination: buildPagination(total, req.Page, req.PerPage),
+	})
+}
+
+// Helper functions
+
+// toDailyPVPChanges loads the crews of the changes and converts them to responses
+func toDailyPVPChanges(db *gorm.DB, changes []repositories.CrewPVPChange) ([]dto.DailyPVPChangeResponse, error) {
+	crewIDs := make([]uint, len(changes))
+	for i, change := range changes {
+		crewIDs[i] = change.CrewID
+	}
+	crews, err := repositories.NewCrewRepository(db).FindByIDs(crewIDs)
+	if err != nil {
+		return nil, err
+	}
+	crewNames := make(map[uint]string, len(crews))
+	for _, crew := range crews {
+		crewNames[crew.ID] = crew.Name
+	}
+
+	responses := make([]dto.DailyPVPChangeResponse, len(changes))
+	for i, change := range changes {
+		responses[i] = dto.DailyPVPChangeResponse{
+			Date:         change.ScrapedAt,
+			CrewID:       change.CrewID,
+			CrewName:     crewNames[change.CrewID],
+			WinsGained:   change.WinsGained,
+			LossesGained: change.LossesGained,
+			RankBefore:   string(change.RankBefore),
+			RankAfter:    string(change.RankAfter),
+			RankChanged:  change.RankChanged(),
+		}
+	}
+	return responses, nil
+}
+
+// mostActiveCrews returns the first crews of changes sorted by battles that fought at all
+func mostActiveCrews(changes []dto.DailyPVPChangeResponse) []dto.DailyPVPChangeResponse {
+	active := make([]dto.DailyPVPChangeResponse, 0, activityTopCrewsLimit)
+	for _, change := range changes {
+		if len(active) == activityTopCrewsLimit || change.WinsGained+change.LossesGained == 0 {
+			break
+		}
+		active = append(active, change)
+	}
+	return active
+}
+
+// toIslandGovernanceChanges loads the islands and flags of the changes and converts them to responses
+func toIslandGovernanceChanges(db *gorm.DB, changes []repositories.GovernanceChange) ([]dto.IslandGovernanceChangeResponse, error) {
+	islandIDs := make([]uint, 0, len(changes))
+	flagIDs := make([]uint, 0, 2*len(changes))
+	for _, change := range changes {
+		islandIDs = append(islandIDs, change.IslandID)
+		if change.FlagID != nil {
+			flagIDs = append(flagIDs, *change.FlagID)
+		}
+		if change.PreviousFlagID != nil {
+			flagIDs = append(flagIDs, *change.PreviousFlagID)
+		}
+	}
+
+	islands, err := repositories.NewIslandRepository(db).FindByIDs(islandIDs)
+	if err != nil {
+		return nil, err
+	}
+	flags, err := repositories.NewFlagRepository(db).FindByIDs(flagIDs)
+	if err != nil {
+		return nil, err
+	}
+	islandsByID := make(map[uint]*models.Island, len(islands))
+	for i := range islands {
+		islandsByID[islands[i].ID] = &islands[i]
+	}
+	flagsByID := make(map[uint]*models.Flag, len(flags))
+	for i := range flags {
+		flagsByID[flags[i].ID] = &flags[i]
+	}
+	flagBrief := func(flagID *uint) *dto.FlagBrief {
+		if flagID == nil {
+			return nil
+		}
+		if flag, ok := flagsByID[*flagID]; ok {
+			brief := toFlagBrief(flag)
+			return &brief
+		}
+		return &dto.FlagBrief{ID: *flagID}
+	}
+
+	responses := make([]dto.IslandGovernanceChangeResponse, len(changes))
+	for i, change := range changes {
+		response := dto.IslandGovernanceChangeResponse{
+			Island:       dto.IslandBrief{ID: change.IslandID},
+			FromFlag:     flagBrief(change.PreviousFlagID),
+			ToFlag:       flagBrief(change.FlagID),
+			FromGovernor: change.PreviousGovernorName,
+			ToGovernor:   change.GovernorName,
+			ChangedAt:    change.StartedAt,
+		}
+		if island, ok := islandsByID[change.IslandID]; ok {
+			response.Island = toIslandBrief(island)
+		}
+		responses[i] = response
+	}
+	return responses, nil
+}
+
+func toCrewBriefs(crews []models.Crew) []dto.CrewBrief {
+	briefs := make([]dto.CrewBrief, len(crews))
+	for i := range crews {
+		briefs[i] = toCrewBrief(&crews[i])
+	}
+	return briefs
+}
+
+func toFlagBriefs(flags []models.Flag) []dto.FlagBrief {
+	briefs := make([]dto.FlagBrief, len(flags))
+	for i := range flags {
+		briefs[i] = toFlagBrief(&flags[i])
+	}
+	return briefs
+}
+
+func rankChangeDirection(promotionsOnly, demotionsOnly bool) string {
+	switch {
+	case promotionsOnly:
+		return repositories.RankChangesPromotions
+	case demotionsOnly:
+		return repositories.RankChangesDemotions
+	default:
+		return repositories.RankChangesAll
+	}
+}
+
+// rangePeriod formats [startDate, endDate) as the first and last day it covers
+func rangePeriod(startDate, endDate time.Time) string {
+	return startDate.Format("2006-01-02") + "/" + endDate.Add(-time.Nanosecond).Format("2006-01-02")
+}
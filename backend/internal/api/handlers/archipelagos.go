@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"cutlass_analytics/internal/dto"
+	"cutlass_analytics/internal/models"
+	"cutlass_analytics/internal/repositories"
+	"cutlass_analytics/internal/types"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func ListArchipelagosHandler(c *gin.Context, db *gorm.DB) {
+	var req dto.ArchipelagoListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	repo := repositories.NewArchipelagoRepository(db)
+	archipelagos, err := repo.List(types.Ocean(req.Ocean))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch archipelagos",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	archipelagoIDs := make([]uint, len(archipelagos))
+	for i, archipelago := range archipelagos {
+		archipelagoIDs[i] = archipelago.ID
+	}
+	islands, err := repo.GetIslands(archipelagoIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch islands",
+			},
+		})
+		return
+	}
+	populations, err := repositories.NewIslandRepository(db).GetLatestPopulations(islandIDs(islands))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch island populations",
+			},
+		})
+		return
+	}
+
+	islandsByArchipelago := make(map[uint][]models.Island, len(archipelagos))
+	for _, island := range islands {
+		islandsByArchipelago[*island.ArchipelagoID] = append(islandsByArchipelago[*island.ArchipelagoID], island)
+	}
+
+	responses := make([]dto.ArchipelagoResponse, len(archipelagos))
+	for i := range archipelagos {
+		members := islandsByArchipelago[archipelagos[i].ID]
+		response := toArchipelagoResponse(&archipelagos[i], members, populations)
+		if req.IncludeIslands {
+			response.Islands = make([]dto.IslandBrief, len(members))
+			for j := range members {
+				response.Islands[j] = toIslandBrief(&members[j])
+			}
+		}
+		responses[i] = response
+	}
+
+	c.JSON(http.StatusOK, dto.ArchipelagoListResponse{
+		Archipelagos: responses,
+	})
+}
+
+func GetArchipelagoHandler(c *gin.Context, db *gorm.DB) {
+	var param dto.ArchipelagoIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid archipelago ID",
+			},
+		})
+		return
+	}
+
+	repo := repositories.NewArchipelagoRepository(db)
+	archipelago, err := repo.FindByID(param.ID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "NOT_FOUND",
+					Message: "Archipelago not found",
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch archipelago",
+			},
+		})
+		return
+	}
+
+	islands, err := repo.GetIslands([]uint{archipelago.ID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch islands",
+			},
+		})
+		return
+	}
+	populations, err := repositories.NewIslandRepository(db).GetLatestPopulations(islandIDs(islands))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch island populations",
+			},
+		})
+		return
+	}
+
+	response := dto.ArchipelagoDetailResponse{
+		ArchipelagoResponse: toArchipelagoResponse(archipelago, islands, populations),
+		Islands:             make([]dto.IslandResponse, len(islands)),
+	}
+	for i := range islands {
+		island := toIslandResponse(&islands[i])
+		island.Population = populations[islands[i].ID]
+		response.Islands[i] = island
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Helper functions
+
+// toArchipelagoResponse rolls the member islands of an archipelago up into its population
+// and the number of islands each flag governs, most islands first
+func toArchipelagoResponse(archipelago *models.Archipelago, islands []models.Island, populations map[uint]int) dto.ArchipelagoResponse {
+	response := dto.ArchipelagoResponse{
+		ID:          archipelago.ID,
+		Name:        archipelago.Name,
+		DisplayName: archipelago.DisplayName,
+		Ocean:       string(archipelago.Ocean),
+		Color:       archipelago.Color,
+		IslandCount: len(islands),
+		Governors:   []dto.ArchipelagoGovernorResponse{},
+	}
+
+	governorIndex := make(map[uint]int)
+	for i := range islands {
+		island := &islands[i]
+		response.TotalPopulation += populations[island.ID]
+		if island.IsColonized {
+			response.ColonizedIslands++
+		}
+		if island.GovernorFlagID == nil {
+			continue
+		}
+
+		response.GovernedIslands++
+		j, ok := governorIndex[*island.GovernorFlagID]
+		if !ok {
+			j = len(response.Governors)
+			governorIndex[*island.GovernorFlagID] = j
+			flag := dto.FlagBrief{ID: *island.GovernorFlagID}
+			if island.GovernorFlag != nil {
+				flag = toFlagBrief(island.GovernorFlag)
+			}
+			response.Governors = append(response.Governors, dto.ArchipelagoGovernorResponse{Flag: flag})
+		}
+		response.Governors[j].IslandCount++
+	}
+
+	for i := range response.Governors {
+		response.Governors[i].Share = float64(response.Governors[i].IslandCount) / float64(len(islands)) * 100
+	}
+	sort.SliceStable(response.Governors, func(i, j int) bool {
+		if response.Governors[i].IslandCount != response.Governors[j].IslandCount {
+			return response.Governors[i].IslandCount > response.Governors[j].IslandCount
+		}
+		return response.Governors[i].Flag.Name < response.Governors[j].Flag.Name
+	})
+	return response
+}
+
+func islandIDs(islands []models.Island) []uint {
+	ids := make([]uint, len(islands))
+	for i, island := range islands {
+		ids[i] = island.ID
+	}
+	return ids
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"cutlass_analytics/internal/dto"
+	"cutlass_analytics/internal/models"
+	"cutlass_analytics/internal/repositories"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// commoditySearchLimit caps the number of commodity search results
+const commoditySearchLimit = 20
+
+func ListCommoditiesHandler(c *gin.Context, db *gorm.DB) {
+	var req dto.CommodityListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	repo := repositories.NewCommodityRepository(db)
+	commodities, err := repo.List(req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch commodities",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	responses := make([]dto.CommodityResponse, len(commodities))
+	for i, commodity := range commodities {
+		responses[i] = toCommodityResponse(&commodity)
+	}
+
+	response := dto.CommodityListResponse{
+		Commodities: responses,
+	}
+
+	// Group by category if requested
+	if req.GroupByCategory {
+		byCategory := make(map[string][]dto.CommodityResponse)
+		for _, commodity := range responses {
+			byCategory[commodity.Category] = append(byCategory[commodity.Category], commodity)
+		}
+		response.ByCategory = byCategory
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func GetCommodityHandler(c *gin.Context, db *gorm.DB) {
+	var param dto.CommodityIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid commodity ID",
+			},
+		})
+		return
+	}
+
+	repo := repositories.NewCommodityRepository(db)
+	commodity, err := repo.FindByID(param.ID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "NOT_FOUND",
+					Message: "Commodity not found",
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch commodity",
+			},
+		})
+		return
+	}
+
+	islands, err := repo.GetSpawnIslands(commodity.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch spawn islands",
+			},
+		})
+		return
+	}
+
+	taxRateRepo := repositories.NewTaxRateRepository(db)
+	rates, err := taxRateRepo.CompareAcrossOceans(commodity.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch tax rates",
+			},
+		})
+		return
+	}
+
+	response := dto.CommodityDetailResponse{
+		CommodityResponse: toCommodityResponse(commodity),
+	}
+	for i := range islands {
+		response.SpawnIslands = append(response.SpawnIslands, toIslandBrief(&islands[i]))
+	}
+	for _, rate := range rates {
+		response.TaxRates = append(response.TaxRates, dto.CommodityTaxRateResponse{
+			CommodityID:   rate.CommodityID,
+			CommodityName: commodity.Name,
+			Ocean:         string(rate.Ocean),
+			TaxValue:      rate.TaxValue,
+			ScrapedAt:     rate.ScrapedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func SearchCommoditiesHandler(c *gin.Context, db *gorm.DB) {
+	var req dto.CommoditySearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	repo := repositories.NewCommodityRepository(db)
+	commodities, err := repo.Search(req.Query, commoditySearchLimit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to search commodities",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	responses := make([]dto.CommodityResponse, len(commodities))
+	for i, commodity := range commodities {
+		responses[i] = toCommodityResponse(&commodity)
+	}
+
+	c.JSON(http.StatusOK, dto.CommodityListResponse{
+		Commodities: responses,
+	})
+}
+
+// Helper functions
+
+func toCommodityResponse(commodity *models.Commodity) dto.CommodityResponse {
+	return dto.CommodityResponse{
+		ID:          commodity.ID,
+		Name:        commodity.Name,
+		DisplayName: commodity.DisplayName,
+		Category:    string(commodity.Category),
+		IsSpawnable: commodity.IsSpawnable,
+		IsRare:      commodity.IsRare,
+		Description: commodity.Description,
+		IconPath:    commodity.IconPath,
+	}
+}
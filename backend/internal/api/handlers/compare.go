@@ -0,0 +1,417 @@
+package handlers
+
+import (
+	"cutlass_analytics/internal/dto"
+	"cutlass_analytics/internal/models"
+	"cutlass_analytics/internal/repositories"
+	"cutlass_analytics/internal/types"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func CompareCrewsHandler(c *gin.Context, db *gorm.DB) {
+	var req dto.MultiCrewCompareRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+	req.SetDefaults()
+
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	repo := repositories.NewCrewRepository(db)
+	crews, err := repo.FindByIDs(req.CrewIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch crews",
+			},
+		})
+		return
+	}
+	if len(crews) != len(req.CrewIDs) {
+		c.JSON(http.StatusNotFound, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "NOT_FOUND",
+				Message: "One or more crews not found",
+			},
+		})
+		return
+	}
+
+	battleRecords, err := repo.GetLatestBattleRecords(req.CrewIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch battle records",
+			},
+		})
+		return
+	}
+	fameRecords, err := repo.GetLatestFameRecords(req.CrewIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch fame records",
+			},
+		})
+		return
+	}
+
+	startDate, endDate := dateRangeBounds(req.DateRangeParams)
+	dailyBattles, err := repo.GetDailyBattleRecords(req.CrewIDs, startDate, endDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch battle history",
+			},
+		})
+		return
+	}
+	dailyFame, err := repo.GetDailyFameRecords(req.CrewIDs, startDate, endDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch fame history",
+			},
+		})
+		return
+	}
+
+	crewData := make([]dto.CrewComparisonData, len(crews))
+	for i := range crews {
+		crewData[i] = toCrewComparisonData(&crews[i], battleRecords, fameRecords)
+	}
+	sortCrewComparison(crewData, req.SortBy)
+
+	// Align the daily records on a shared date axis, in the order the crews were requested
+	dates := comparisonDays(startDate, endDate)
+	seriesByCrew := make(map[uint]*dto.CrewComparisonSeries, len(req.CrewIDs))
+	series := make([]dto.CrewComparisonSeries, len(req.CrewIDs))
+	for i, crewID := range req.CrewIDs {
+		series[i] = dto.CrewComparisonSeries{
+			CrewID:   crewID,
+			Wins:     make([]*int, len(dates)),
+			Losses:   make([]*int, len(dates)),
+			WinRate:  make([]*float64, len(dates)),
+			FameRank: make([]*int, len(dates)),
+			CrewRank: make([]*string, len(dates)),
+		}
+		seriesByCrew[crewID] = &series[i]
+	}
+
+	dayIndex := comparisonDayIndex(dates)
+	for _, record := range dailyBattles {
+		day, ok := dayIndex[utcDay(record.ScrapedAt)]
+		if !ok {
+			continue
+		}
+		s := seriesByCrew[record.CrewID]
+		wins, losses, winRate, crewRank := record.TotalPVPWins, record.TotalPVPLosses, record.WinRate(), string(record.CrewRank)
+		s.Wins[day] = &wins
+		s.Losses[day] = &losses
+		s.WinRate[day] = &winRate
+		s.CrewRank[day] = &crewRank
+	}
+	for _, record := range dailyFame {
+		day, ok := dayIndex[utcDay(record.ScrapedAt)]
+		if !ok {
+			continue
+		}
+		seriesByCrew[record.CrewID].FameRank[day] = record.FameRank
+	}
+
+	c.JSON(http.StatusOK, dto.MultiCrewComparisonResponse{
+		Crews:      crewData,
+		SortedBy:   req.SortBy,
+		ComparedAt: time.Now(),
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Dates:      dates,
+		Series:     series,
+	})
+}
+
+func CompareFlagsHandler(c *gin.Context, db *gorm.DB) {
+	var req dto.MultiFlagCompareRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+	req.SetDefaults()
+
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	repo := repositories.NewFlagRepository(db)
+	flags, err := repo.FindByIDs(req.FlagIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch flags",
+			},
+		})
+		return
+	}
+	if len(flags) != len(req.FlagIDs) {
+		c.JSON(http.StatusNotFound, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "NOT_FOUND",
+				Message: "One or more flags not found",
+			},
+		})
+		return
+	}
+
+	fameRecords, err := repo.GetLatestFameRecords(req.FlagIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch fame records",
+			},
+		})
+		return
+	}
+
+	flagData := make([]dto.FlagComparisonData, len(flags))
+	for i := range flags {
+		totals, err := repo.GetCurrentBattleTotals(flags[i].ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "DATABASE_ERROR",
+					Message: "Failed to fetch battle stats",
+				},
+			})
+			return
+		}
+		flagData[i] = toFlagComparisonData(&flags[i], totals, fameRecords)
+	}
+	sortFlagComparison(flagData, req.SortBy)
+
+	// Align the daily totals on a shared date axis, in the order the flags were requested
+	startDate, endDate := dateRangeBounds(req.DateRangeParams)
+	dates := comparisonDays(startDate, endDate)
+	dayIndex := comparisonDayIndex(dates)
+	series := make([]dto.FlagComparisonSeries, len(req.FlagIDs))
+	for i, flagID := range req.FlagIDs {
+		totals, err := repo.GetDailyBattleTotals(flagID, startDate, endDate)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "DATABASE_ERROR",
+					Message: "Failed to fetch battle history",
+				},
+			})
+			return
+		}
+		fameHistory, err := repo.GetFameRecords(flagID, startDate, endDate)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "DATABASE_ERROR",
+					Message: "Failed to fetch fame history",
+				},
+			})
+			return
+		}
+
+		s := dto.FlagComparisonSeries{
+			FlagID:    flagID,
+			Wins:      make([]*int, len(dates)),
+			Losses:    make([]*int, len(dates)),
+			WinRate:   make([]*float64, len(dates)),
+			FameRank:  make([]*int, len(dates)),
+			CrewCount: make([]*int, len(dates)),
+		}
+		for _, total := range totals {
+			day, ok := dayIndex[utcDay(total.Date)]
+			if !ok {
+				continue
+			}
+			wins, losses, winRate, crewCount := total.TotalPVPWins, total.TotalPVPLosses, total.WinRate(), total.CrewCount
+			s.Wins[day] = &wins
+			s.Losses[day] = &losses
+			s.WinRate[day] = &winRate
+			s.CrewCount[day] = &crewCount
+		}
+		// Records are ordered by scrape time, so the last one of each day wins
+		for _, record := range fameHistory {
+			if day, ok := dayIndex[utcDay(record.ScrapedAt)]; ok {
+				s.FameRank[day] = record.FameRank
+			}
+		}
+		series[i] = s
+	}
+
+	c.JSON(http.StatusOK, dto.MultiFlagComparisonResponse{
+		Flags:      flagData,
+		SortedBy:   req.SortBy,
+		ComparedAt: time.Now(),
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Dates:      dates,
+		Series:     series,
+	})
+}
+
+// Helper functions
+
+// dateRangeBounds returns the start of the first requested UTC day and the end of the last.
+// An explicit end date includes the whole day; the default range ends now.
+func dateRangeBounds(params dto.DateRangeParams) (time.Time, time.Time) {
+	startDate, _ := params.ParsedStartDate()
+	endDate, _ := params.ParsedEndDate()
+	if params.EndDate != "" {
+		endDate = endDate.AddDate(0, 0, 1)
+	}
+	return utcDay(startDate), endDate
+}
+
+// comparisonDays returns every UTC day in [startDate, endDate)
+func comparisonDays(startDate, endDate time.Time) []time.Time {
+	var days []time.Time
+	for day := utcDay(startDate); day.Before(endDate); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	return days
+}
+
+func comparisonDayIndex(days []time.Time) map[time.Time]int {
+	index := make(map[time.Time]int, len(days))
+	for i, day := range days {
+		index[day] = i
+	}
+	return index
+}
+
+func utcDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+func toCrewComparisonData(crew *models.Crew, battleRecords map[uint]models.CrewBattleRecord, fameRecords map[uint]models.CrewFameRecord) dto.CrewComparisonData {
+	data := dto.CrewComparisonData{
+		Crew: toCrewBrief(crew),
+	}
+	if crew.Flag != nil {
+		data.FlagName = crew.Flag.Name
+	}
+	if record, ok := battleRecords[crew.ID]; ok {
+		data.CrewRank = string(record.CrewRank)
+		data.TotalPVPWins = record.TotalPVPWins
+		data.TotalPVPLosses = record.TotalPVPLosses
+		data.WinRate = record.WinRate()
+		data.TotalBattles = record.TotalBattles()
+	}
+	if record, ok := fameRecords[crew.ID]; ok {
+		data.FameLevel = string(record.FameLevel)
+		data.FameRank = record.FameRank
+	}
+	return data
+}
+
+func toFlagComparisonData(flag *models.Flag, totals *repositories.FlagBattleTotals, fameRecords map[uint]models.FlagFameRecord) dto.FlagComparisonData {
+	data := dto.FlagComparisonData{
+		Flag:           toFlagBrief(flag),
+		CrewCount:      totals.CrewCount,
+		TotalPVPWins:   totals.TotalPVPWins,
+		TotalPVPLosses: totals.TotalPVPLosses,
+		WinRate:        totals.WinRate(),
+		TotalBattles:   totals.TotalBattles(),
+	}
+	if record, ok := fameRecords[flag.ID]; ok {
+		data.FameLevel = string(record.FameLevel)
+		data.FameRank = record.FameRank
+	}
+	return data
+}
+
+func sortCrewComparison(crews []dto.CrewComparisonData, sortBy string) {
+	sort.SliceStable(crews, func(i, j int) bool {
+		a, b := crews[i], crews[j]
+		switch sortBy {
+		case "win_rate":
+			return a.WinRate > b.WinRate
+		case "battles":
+			return a.TotalBattles > b.TotalBattles
+		case "rank":
+			return types.CrewRank(a.CrewRank).Order() > types.CrewRank(b.CrewRank).Order()
+		default:
+			return a.TotalPVPWins > b.TotalPVPWins
+		}
+	})
+}
+
+func sortFlagComparison(flags []dto.FlagComparisonData, sortBy string) {
+	sort.SliceStable(flags, func(i, j int) bool {
+		a, b := flags[i], flags[j]
+		switch sortBy {
+		case "win_rate":
+			return a.WinRate > b.WinRate
+		case "crews":
+			return a.CrewCount > b.CrewCount
+		case "fame":
+			// Unranked flags go last
+			if a.FameRank == nil || b.FameRank == nil {
+				return a.FameRank != nil
+			}
+			return *a.FameRank < *b.FameRank
+		default:
+			return a.TotalPVPWins > b.TotalPVPWins
+		}
+	})
+}
@@ -348,6 +348,378 @@ func GetCrewStatsHandler(c *gin.Context, db *gorm.DB) {
 	c.JSON(http.StatusOK, response)
 }
 
+func GetCrewFlagHistoryHandler(c *gin.Context, db *gorm.DB) {
+	var param dto.CrewIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid crew ID",
+			},
+		})
+		return
+	}
+
+	repo := repositories.NewCrewRepository(db)
+	crew, err := repo.FindByID(param.ID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "NOT_FOUND",
+					Message: "Crew not found",
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch crew",
+			},
+		})
+		return
+	}
+
+	history, err := repo.GetFlagHistory(crew.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch flag history",
+			},
+		})
+		return
+	}
+
+	memberships := make([]dto.FlagMembershipLogResponse, len(history))
+	for i, membership := range history {
+		entry := dto.FlagMembershipLogResponse{
+			JoinedAt:  membership.JoinedAt,
+			LeftAt:    membership.LeftAt,
+			Days:      membership.DurationDays(),
+			IsCurrent: membership.IsActive(),
+		}
+		if membership.Flag != nil {
+			flag := toFlagBrief(membership.Flag)
+			entry.Flag = &flag
+		}
+		memberships[i] = entry
+	}
+
+	response := dto.CrewFlagHistoryResponse{
+		Crew:    toCrewBrief(crew),
+		History: memberships,
+	}
+	if crew.Flag != nil {
+		flag := toFlagBrief(crew.Flag)
+		response.CurrentFlag = &flag
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func GetCrewRankChangesHandler(c *gin.Context, db *gorm.DB) {
+	var param dto.CrewIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid crew ID",
+			},
+		})
+		return
+	}
+
+	var req dto.CrewRankChangesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	crew, err := repositories.NewCrewRepository(db).FindByID(param.ID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "NOT_FOUND",
+					Message: "Crew not found",
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch crew",
+			},
+		})
+		return
+	}
+
+	startDate, endDate := dateRangeBounds(req.DateRangeParams)
+	direction := rankChangeDirection(req.PromotionsOnly, req.DemotionsOnly)
+
+	changes, err := repositories.NewActivityRepository(db).GetCrewRankChanges(crew.ID, startDate, endDate, direction)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch rank changes",
+			},
+		})
+		return
+	}
+
+	events := make([]dto.RankChangeEvent, len(changes))
+	for i, change := range changes {
+		events[i] = dto.RankChangeEvent{
+			Date:        change.ScrapedAt,
+			FromRank:    string(change.FromRank),
+			ToRank:      string(change.ToRank),
+			IsPromotion: change.IsPromotion(),
+		}
+	}
+
+	c.JSON(http.StatusOK, dto.RankChangeResponse{
+		Crew:         toCrewBrief(crew),
+		Period:       rangePeriod(startDate, endDate),
+		Changes:      events,
+		TotalChanges: len(events),
+	})
+}
+
+func GetCrewNamesHandler(c *gin.Context, db *gorm.DB) {
+	var param dto.CrewIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid crew ID",
+			},
+		})
+		return
+	}
+
+	repo := repositories.NewCrewRepository(db)
+	crew, err := repo.FindByID(param.ID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "NOT_FOUND",
+					Message: "Crew not found",
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch crew",
+			},
+		})
+		return
+	}
+
+	history, err := repo.GetNameHistory(crew.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch name history",
+			},
+		})
+		return
+	}
+
+	response := dto.CrewNameHistoryResponse{
+		Crew:    toCrewBrief(crew),
+		History: make([]dto.NameHistoryEntryResponse, len(history)),
+	}
+	if len(history) > 1 {
+		response.RenameCount = len(history) - 1
+	}
+	for i := range history {
+		response.History[i] = dto.NameHistoryEntryResponse{
+			Name:      history[i].Name,
+			StartedAt: history[i].StartedAt,
+			EndedAt:   history[i].EndedAt,
+			Days:      history[i].DurationDays(),
+			IsCurrent: history[i].IsCurrent(),
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func GetCrewEventsHandler(c *gin.Context, db *gorm.DB) {
+	var param dto.CrewIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid crew ID",
+			},
+		})
+		return
+	}
+
+	repo := repositories.NewCrewRepository(db)
+	crew, err := repo.FindByID(param.ID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "NOT_FOUND",
+					Message: "Crew not found",
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch crew",
+			},
+		})
+		return
+	}
+
+	events, err := repo.GetLifecycleEvents(crew.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch lifecycle events",
+			},
+		})
+		return
+	}
+
+	response := dto.CrewEventsResponse{
+		Crew:          toCrewBrief(crew),
+		IsActive:      crew.IsActive,
+		MissedScrapes: crew.MissedScrapes,
+		FirstSeenAt:   crew.FirstSeenAt,
+		LastSeenAt:    crew.LastSeenAt,
+		Events:        make([]dto.LifecycleEventResponse, len(events)),
+	}
+	for i := range events {
+		response.Events[i] = dto.LifecycleEventResponse{
+			Type:          string(events[i].EventType),
+			OccurredAt:    events[i].OccurredAt,
+			MissedScrapes: events[i].MissedScrapes,
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func SearchCrewsHandler(c *gin.Context, db *gorm.DB) {
+	var req dto.CrewSearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+	req.SetDefaults()
+
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	filter := repositories.SearchFilter{
+		Ocean:    types.Ocean(req.Ocean),
+		Types:    []string{repositories.SearchTypeCrew},
+		IsActive: req.IsActive,
+	}
+	hits, total, err := repositories.NewSearchRepository(db).Search(req.Query, filter, req.Offset(), req.Limit())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to search crews",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	results, err := crewSearchResults(db, hits)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch crews",
+			},
+		})
+		return
+	}
+
+	response := dto.CrewSearchResponse{
+		Query:      req.Query,
+		Results:    []dto.CrewSearchResultResponse{},
+		TotalCount: int(total),
+	}
+	for _, hit := range hits {
+		if result, ok := results[hit.EntityID]; ok {
+			response.Results = append(response.Results, result)
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // Helper functions
 
 func toCrewResponse(crew *models.Crew) dto.CrewResponse {
@@ -376,3 +748,12 @@ func toCrewResponse(crew *models.Crew) dto.CrewResponse {
 
 	return response
 }
+
+func toCrewBrief(crew *models.Crew) dto.CrewBrief {
+	return dto.CrewBrief{
+		ID:         crew.ID,
+		GameCrewID: crew.GameCrewID,
+		Name:       crew.Name,
+		Ocean:      string(crew.Ocean),
+	}
+}
@@ -6,11 +6,19 @@ import (
 	"cutlass_analytics/internal/repositories"
 	"cutlass_analytics/internal/types"
 	"net/http"
+	"sort"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
+// Events of a flag's territory timeline
+const (
+	territoryGained = "gained"
+	territoryLost   = "lost"
+)
+
 func ListFlagsHandler(c *gin.Context, db *gorm.DB) {
 	var req dto.FlagListRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
@@ -277,17 +285,1014 @@ func GetFlagFameHandler(c *gin.Context, db *gorm.DB) {
 	c.JSON(http.StatusOK, response)
 }
 
-// Helper functions
+func GetFlagStatsHandler(c *gin.Context, db *gorm.DB) {
+	var param dto.FlagIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid flag ID",
+			},
+		})
+		return
+	}
 
-func toFlagResponse(flag *models.Flag) dto.FlagResponse {
-	return dto.FlagResponse{
-		ID:          flag.ID,
-		GameFlagID:  flag.GameFlagID,
-		Name:        flag.Name,
-		Ocean:       string(flag.Ocean),
-		IsActive:    flag.IsActive,
-		FirstSeenAt: flag.FirstSeenAt,
-		LastSeenAt:  flag.LastSeenAt,
-		URL:         flag.GetYowebURL(),
+	repo := repositories.NewFlagRepository(db)
+	flag, err := repo.FindByID(param.ID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "NOT_FOUND",
+					Message: "Flag not found",
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch flag",
+			},
+		})
+		return
+	}
+
+	totals, err := repo.GetCurrentBattleTotals(flag.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch battle stats",
+			},
+		})
+		return
+	}
+
+	response := dto.FlagPVPStatsResponse{
+		FlagID:         flag.ID,
+		GameFlagID:     flag.GameFlagID,
+		Name:           flag.Name,
+		Ocean:          string(flag.Ocean),
+		CrewCount:      totals.CrewCount,
+		TotalPVPWins:   totals.TotalPVPWins,
+		TotalPVPLosses: totals.TotalPVPLosses,
+		WinRate:        totals.WinRate(),
+		TotalBattles:   totals.TotalBattles(),
+		LastUpdated:    totals.ScrapedAt,
+	}
+	if fameRecord, err := repo.GetLatestFameRecord(flag.ID); err == nil {
+		response.FameLevel = string(fameRecord.FameLevel)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func GetFlagHistoryHandler(c *gin.Context, db *gorm.DB) {
+	var param dto.FlagIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid flag ID",
+			},
+		})
+		return
+	}
+
+	var req dto.FlagHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+			},
+		})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	startDate, _ := req.ParsedStartDate()
+	endDate, _ := req.ParsedEndDate()
+	// An explicit end date includes the whole day
+	rangeEnd := endDate
+	if req.EndDate != "" {
+		rangeEnd = endDate.AddDate(0, 0, 1)
+	}
+
+	repo := repositories.NewFlagRepository(db)
+	flag, err := repo.FindByID(param.ID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "NOT_FOUND",
+					Message: "Flag not found",
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch flag",
+			},
+		})
+		return
+	}
+
+	totals, err := repo.GetDailyBattleTotals(flag.ID, startDate, rangeEnd)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch battle history",
+			},
+		})
+		return
+	}
+
+	fameRecords, err := repo.GetFameRecords(flag.ID, startDate, rangeEnd)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch fame history",
+			},
+		})
+		return
+	}
+
+	joined, left, err := repo.CountMembershipChanges(flag.ID, startDate, rangeEnd)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch membership changes",
+			},
+		})
+		return
+	}
+
+	// Records are ordered by scrape time, so the last one of each day wins
+	fameByDay := make(map[time.Time]models.FlagFameRecord, len(fameRecords))
+	for _, record := range fameRecords {
+		fameByDay[utcDay(record.ScrapedAt)] = record
+	}
+
+	dataPoints := make([]dto.FlagHistoryPointResponse, len(totals))
+	for i, total := range totals {
+		point := dto.FlagHistoryPointResponse{
+			Date:        total.Date,
+			CrewCount:   total.CrewCount,
+			TotalWins:   total.TotalPVPWins,
+			TotalLosses: total.TotalPVPLosses,
+			DailyWins:   total.DailyPVPWins,
+			DailyLosses: total.DailyPVPLosses,
+			WinRate:     total.WinRate(),
+		}
+		if fame, ok := fameByDay[utcDay(total.Date)]; ok {
+			point.FameLevel = string(fame.FameLevel)
+			point.FameRank = fame.FameRank
+		}
+		dataPoints[i] = point
+	}
+
+	response := dto.FlagHistoricalStatsResponse{
+		Flag:          toFlagBrief(flag),
+		StartDate:     startDate,
+		EndDate:       endDate,
+		DataPoints:    dataPoints,
+		CrewsJoined:   int(joined),
+		CrewsLeft:     int(left),
+		NetCrewChange: int(joined - left),
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func GetFlagMembershipHandler(c *gin.Context, db *gorm.DB) {
+	var param dto.FlagIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid flag ID",
+			},
+		})
+		return
+	}
+
+	var req dto.FlagMembershipHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+			},
+		})
+		return
+	}
+	req.SetDefaults()
+
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	startDate, _ := req.ParsedStartDate()
+	endDate, _ := req.ParsedEndDate()
+	// An explicit end date includes the whole day
+	rangeEnd := endDate
+	if req.EndDate != "" {
+		rangeEnd = endDate.AddDate(0, 0, 1)
+	}
+
+	repo := repositories.NewFlagRepository(db)
+	flag, err := repo.FindByID(param.ID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "NOT_FOUND",
+					Message: "Flag not found",
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch flag",
+			},
+		})
+		return
+	}
+
+	changes, total, err := repo.GetMembershipChanges(flag.ID, startDate, rangeEnd, *req.IncludeJoins, *req.IncludeLeaves, req.Offset(), req.Limit())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch membership changes",
+			},
+		})
+		return
+	}
+
+	startingCrews, err := repo.CountMembersAt(flag.ID, startDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to count flag members",
+			},
+		})
+		return
+	}
+
+	joined, left, err := repo.CountMembershipChanges(flag.ID, startDate, rangeEnd)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch membership changes",
+			},
+		})
+		return
+	}
+
+	crews, err := repo.GetCrews(flag.ID, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch flag crews",
+			},
+		})
+		return
+	}
+
+	// Load the crews and other flags involved in the changes
+	var crewIDs, flagIDs []uint
+	for _, change := range changes {
+		crewIDs = append(crewIDs, change.CrewID)
+		if change.OtherFlagID != nil {
+			flagIDs = append(flagIDs, *change.OtherFlagID)
+		}
+	}
+	changedCrews, err := repositories.NewCrewRepository(db).FindByIDs(crewIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch crews",
+			},
+		})
+		return
+	}
+	otherFlags, err := repo.FindByIDs(flagIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch flags",
+			},
+		})
+		return
+	}
+
+	crewsByID := make(map[uint]*models.Crew, len(changedCrews))
+	for i := range changedCrews {
+		crewsByID[changedCrews[i].ID] = &changedCrews[i]
+	}
+	flagsByID := make(map[uint]*models.Flag, len(otherFlags))
+	for i := range otherFlags {
+		flagsByID[otherFlags[i].ID] = &otherFlags[i]
+	}
+
+	response := dto.FlagMembershipResponse{
+		Flag:          toFlagBrief(flag),
+		CurrentCrews:  make([]dto.CrewBrief, len(crews)),
+		TotalCrews:    len(crews),
+		StartDate:     startDate,
+		EndDate:       endDate,
+		StartingCrews: int(startingCrews),
+		CrewsJoined:   int(joined),
+		CrewsLeft:     int(left),
+		NetCrewChange: int(joined - left),
+		Pagination:    buildPagination(total, req.Page, req.PerPage),
+	}
+	for i := range crews {
+		response.CurrentCrews[i] = toCrewBrief(&crews[i])
+	}
+	// Churn is the share of crews that sailed under the flag during the range and left it
+	if members := startingCrews + joined; members > 0 {
+		response.ChurnRate = float64(left) / float64(members) * 100
+	}
+
+	for _, change := range changes {
+		membership := models.CrewFlagHistory{JoinedAt: change.JoinedAt, LeftAt: change.LeftAt}
+		entry := dto.CrewFlagChangeResponse{
+			Crew:      dto.CrewBrief{ID: change.CrewID},
+			Timestamp: change.OccurredAt,
+			Days:      membership.DurationDays(),
+		}
+		if crew, ok := crewsByID[change.CrewID]; ok {
+			entry.Crew = toCrewBrief(crew)
+		}
+
+		var otherFlag *dto.FlagBrief
+		if change.OtherFlagID != nil {
+			if flag, ok := flagsByID[*change.OtherFlagID]; ok {
+				brief := toFlagBrief(flag)
+				otherFlag = &brief
+			}
+		}
+
+		if change.Event == repositories.MembershipJoin {
+			entry.FromFlag = otherFlag
+			response.RecentJoins = append(response.RecentJoins, entry)
+		} else {
+			entry.ToFlag = otherFlag
+			response.RecentLeaves = append(response.RecentLeaves, entry)
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func GetFlagTerritoryHandler(c *gin.Context, db *gorm.DB) {
+	var param dto.FlagIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid flag ID",
+			},
+		})
+		return
+	}
+
+	var req dto.FlagTerritoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	startDate, endDate := dateRangeBounds(req.DateRangeParams)
+
+	repo := repositories.NewFlagRepository(db)
+	flag, err := repo.FindByID(param.ID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "NOT_FOUND",
+					Message: "Flag not found",
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch flag",
+			},
+		})
+		return
+	}
+
+	governed, err := repo.GetGovernedIslands(flag.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch governed islands",
+			},
+		})
+		return
+	}
+
+	history, err := repo.GetGovernanceHistory(flag.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch governance history",
+			},
+		})
+		return
+	}
+	tenures := models.FlagTenures(history, flag.ID)
+
+	// Load the islands and other flags involved in the tenures
+	var tenureIslandIDs, flagIDs []uint
+	currentTenures := make(map[uint]*models.GovernanceTenure)
+	for i := range tenures {
+		tenure := &tenures[i]
+		tenureIslandIDs = append(tenureIslandIDs, tenure.IslandID)
+		if tenure.PreviousFlagID != nil {
+			flagIDs = append(flagIDs, *tenure.PreviousFlagID)
+		}
+		if tenure.NextFlagID != nil {
+			flagIDs = append(flagIDs, *tenure.NextFlagID)
+		}
+		if tenure.IsCurrent() {
+			currentTenures[tenure.IslandID] = tenure
+		}
+	}
+	islandRepo := repositories.NewIslandRepository(db)
+	tenureIslands, err := islandRepo.FindByIDs(tenureIslandIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch islands",
+			},
+		})
+		return
+	}
+	otherFlags, err := repo.FindByIDs(flagIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch flags",
+			},
+		})
+		return
+	}
+	populations, err := islandRepo.GetLatestPopulations(islandIDs(governed))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch island populations",
+			},
+		})
+		return
+	}
+
+	islandsByID := make(map[uint]*models.Island, len(tenureIslands))
+	for i := range tenureIslands {
+		islandsByID[tenureIslands[i].ID] = &tenureIslands[i]
+	}
+	flagsByID := make(map[uint]*models.Flag, len(otherFlags))
+	for i := range otherFlags {
+		flagsByID[otherFlags[i].ID] = &otherFlags[i]
+	}
+	flagBrief := func(id *uint) *dto.FlagBrief {
+		if id == nil {
+			return nil
+		}
+		flag, ok := flagsByID[*id]
+		if !ok {
+			return nil
+		}
+		brief := toFlagBrief(flag)
+		return &brief
+	}
+
+	response := dto.FlagTerritoryResponse{
+		Flag:        toFlagBrief(flag),
+		Islands:     make([]dto.TerritoryIslandResponse, len(governed)),
+		IslandCount: len(governed),
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Timeline:    []dto.TerritoryChangeResponse{},
+	}
+	for i := range governed {
+		island := &governed[i]
+		entry := dto.TerritoryIslandResponse{
+			Island:       toIslandBrief(island),
+			GovernorName: island.GovernorName,
+			Population:   populations[island.ID],
+		}
+		if island.Archipelago != nil {
+			entry.Archipelago = &dto.ArchipelagoBrief{
+				ID:    island.Archipelago.ID,
+				Name:  island.Archipelago.Name,
+				Color: island.Archipelago.Color,
+			}
+		}
+		if tenure, ok := currentTenures[island.ID]; ok {
+			entry.GovernedSince = &tenure.StartedAt
+			entry.Days = tenure.DurationDays()
+		}
+		response.TotalPopulation += entry.Population
+		response.Islands[i] = entry
+	}
+
+	inRange := func(t time.Time) bool {
+		return !t.Before(startDate) && t.Before(endDate)
+	}
+	for i := range tenures {
+		tenure := &tenures[i]
+		island := dto.IslandBrief{ID: tenure.IslandID}
+		if found, ok := islandsByID[tenure.IslandID]; ok {
+			island = toIslandBrief(found)
+		}
+
+		// A tenure that starts with the island's first record predates tracking, not a gain
+		if !tenure.FirstSeen && inRange(tenure.StartedAt) {
+			response.IslandsGained++
+			response.Timeline = append(response.Timeline, dto.TerritoryChangeResponse{
+				Island:    island,
+				Event:     territoryGained,
+				Timestamp: tenure.StartedAt,
+				Days:      tenure.DurationDays(),
+				FromFlag:  flagBrief(tenure.PreviousFlagID),
+			})
+		}
+		if tenure.EndedAt != nil && inRange(*tenure.EndedAt) {
+			response.IslandsLost++
+			response.Timeline = append(response.Timeline, dto.TerritoryChangeResponse{
+				Island:    island,
+				Event:     territoryLost,
+				Timestamp: *tenure.EndedAt,
+				Days:      tenure.DurationDays(),
+				ToFlag:    flagBrief(tenure.NextFlagID),
+			})
+		}
+	}
+	response.NetIslandChange = response.IslandsGained - response.IslandsLost
+	sort.SliceStable(response.Timeline, func(i, j int) bool {
+		return response.Timeline[i].Timestamp.Before(response.Timeline[j].Timestamp)
+	})
+
+	c.JSON(http.StatusOK, response)
+}
+
+func GetFlagNamesHandler(c *gin.Context, db *gorm.DB) {
+	var param dto.FlagIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid flag ID",
+			},
+		})
+		return
+	}
+
+	repo := repositories.NewFlagRepository(db)
+	flag, err := repo.FindByID(param.ID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "NOT_FOUND",
+					Message: "Flag not found",
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch flag",
+			},
+		})
+		return
+	}
+
+	history, err := repo.GetNameHistory(flag.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch name history",
+			},
+		})
+		return
+	}
+
+	response := dto.FlagNameHistoryResponse{
+		Flag:    toFlagBrief(flag),
+		History: make([]dto.NameHistoryEntryResponse, len(history)),
+	}
+	if len(history) > 1 {
+		response.RenameCount = len(history) - 1
+	}
+	for i := range history {
+		response.History[i] = dto.NameHistoryEntryResponse{
+			Name:      history[i].Name,
+			StartedAt: history[i].StartedAt,
+			EndedAt:   history[i].EndedAt,
+			Days:      history[i].DurationDays(),
+			IsCurrent: history[i].IsCurrent(),
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func GetFlagEventsHandler(c *gin.Context, db *gorm.DB) {
+	var param dto.FlagIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid flag ID",
+			},
+		})
+		return
+	}
+
+	repo := repositories.NewFlagRepository(db)
+	flag, err := repo.FindByID(param.ID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "NOT_FOUND",
+					Message: "Flag not found",
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch flag",
+			},
+		})
+		return
+	}
+
+	events, err := repo.GetLifecycleEvents(flag.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch lifecycle events",
+			},
+		})
+		return
+	}
+
+	response := dto.FlagEventsResponse{
+		Flag:          toFlagBrief(flag),
+		IsActive:      flag.IsActive,
+		MissedScrapes: flag.MissedScrapes,
+		FirstSeenAt:   flag.FirstSeenAt,
+		LastSeenAt:    flag.LastSeenAt,
+		Events:        make([]dto.LifecycleEventResponse, len(events)),
+	}
+	for i := range events {
+		response.Events[i] = dto.LifecycleEventResponse{
+			Type:          string(events[i].EventType),
+			OccurredAt:    events[i].OccurredAt,
+			MissedScrapes: events[i].MissedScrapes,
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func GetFlagReputationHandler(c *gin.Context, db *gorm.DB) {
+	var param dto.FlagIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid flag ID",
+			},
+		})
+		return
+	}
+
+	var req dto.FlagReputationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	startDate, endDate := dateRangeBounds(req.DateRangeParams)
+
+	repo := repositories.NewFlagRepository(db)
+	flag, err := repo.FindByID(param.ID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "NOT_FOUND",
+					Message: "Flag not found",
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch flag",
+			},
+		})
+		return
+	}
+
+	latest, err := repo.GetLatestReputationRecords(flag.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch reputation",
+			},
+		})
+		return
+	}
+	records, err := repo.GetReputationRecords(flag.ID, startDate, endDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch reputation history",
+			},
+		})
+		return
+	}
+
+	recordsByType := make(map[types.ReputationType][]models.FlagReputationRecord)
+	for _, record := range records {
+		recordsByType[record.ReputationType] = append(recordsByType[record.ReputationType], record)
+	}
+
+	response := dto.FlagReputationHistoryResponse{
+		Flag:        toFlagBrief(flag),
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Reputations: []dto.FlagReputationTrackResponse{},
+	}
+	for _, reputationType := range []types.ReputationType{
+		types.ReputationConqueror, types.ReputationExplorer, types.ReputationPatron, types.ReputationMagnate,
+	} {
+		if req.ReputationType != "" && req.ReputationType != string(reputationType) {
+			continue
+		}
+		current, ok := latest[reputationType]
+		if !ok {
+			continue
+		}
+		response.Reputations = append(response.Reputations, toFlagReputationTrack(current, recordsByType[reputationType]))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func SearchFlagsHandler(c *gin.Context, db *gorm.DB) {
+	var req dto.FlagSearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+	req.SetDefaults()
+
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	filter := repositories.SearchFilter{
+		Ocean:    types.Ocean(req.Ocean),
+		Types:    []string{repositories.SearchTypeFlag},
+		IsActive: req.IsActive,
+	}
+	hits, total, err := repositories.NewSearchRepository(db).Search(req.Query, filter, req.Offset(), req.Limit())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to search flags",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	results, err := flagSearchResults(db, hits)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch flags",
+			},
+		})
+		return
+	}
+
+	response := dto.FlagSearchResponse{
+		Query:      req.Query,
+		Results:    []dto.FlagSearchResultResponse{},
+		TotalCount: int(total),
+	}
+	for _, hit := range hits {
+		if result, ok := results[hit.EntityID]; ok {
+			response.Results = append(response.Results, result)
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Helper functions
+
+func toFlagResponse(flag *models.Flag) dto.FlagResponse {
+	return dto.FlagResponse{
+		ID:          flag.ID,
+		GameFlagID:  flag.GameFlagID,
+		Name:        flag.Name,
+		Ocean:       string(flag.Ocean),
+		IsActive:    flag.IsActive,
+		FirstSeenAt: flag.FirstSeenAt,
+		LastSeenAt:  flag.LastSeenAt,
+		URL:         flag.GetYowebURL(),
+	}
+}
+
+func toFlagBrief(flag *models.Flag) dto.FlagBrief {
+	return dto.FlagBrief{
+		ID:         flag.ID,
+		GameFlagID: flag.GameFlagID,
+		Name:       flag.Name,
+		Ocean:      string(flag.Ocean),
+	}
+}
+
+// toFlagReputationTrack summarizes one reputation of a flag from its latest record and its
+// records in the requested range, oldest first. The history keeps only the level changes.
+func toFlagReputationTrack(current models.FlagReputationRecord, records []models.FlagReputationRecord) dto.FlagReputationTrackResponse {
+	track := dto.FlagReputationTrackResponse{
+		ReputationType: string(current.ReputationType),
+		CurrentLevel:   string(current.ReputationLevel),
+		LastScrapedAt:  current.ScrapedAt,
+		History:        []dto.FlagReputationLevelResponse{},
+	}
+	if len(records) == 0 {
+		return track
+	}
+
+	first, last := records[0], records[len(records)-1]
+	track.StartLevel = string(first.ReputationLevel)
+	track.EndLevel = string(last.ReputationLevel)
+	track.LevelChange = last.ReputationLevel.Order() - first.ReputationLevel.Order()
+	for i, record := range records {
+		if i > 0 && record.ReputationLevel == records[i-1].ReputationLevel {
+			continue
+		}
+		track.History = append(track.History, dto.FlagReputationLevelResponse{
+			ScrapedAt:       record.ScrapedAt,
+			ReputationLevel: string(record.ReputationLevel),
+		})
 	}
+	return track
 }
@@ -377,6 +377,76 @@ func GetIslandCommoditiesHandler(c *gin.Context, db *gorm.DB) {
 	c.JSON(http.StatusOK, response)
 }
 
+func SearchIslandsHandler(c *gin.Context, db *gorm.DB) {
+	var req dto.IslandSearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+	req.SetDefaults()
+
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	filter := repositories.SearchFilter{
+		Ocean:       types.Ocean(req.Ocean),
+		Types:       []string{repositories.SearchTypeIsland},
+		IsColonized: req.IsColonized,
+	}
+	hits, total, err := repositories.NewSearchRepository(db).Search(req.Query, filter, req.Offset(), req.Limit())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to search islands",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	results, err := islandSearchResults(db, hits)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch islands",
+			},
+		})
+		return
+	}
+
+	response := dto.IslandSearchResponse{
+		Query:      req.Query,
+		Results:    []dto.IslandSearchResultResponse{},
+		TotalCount: int(total),
+	}
+	for _, hit := range hits {
+		if result, ok := results[hit.EntityID]; ok {
+			response.Results = append(response.Results, result)
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // Helper functions
 
 func toIslandResponse(island *models.Island) dto.IslandResponse {
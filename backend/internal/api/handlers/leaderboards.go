@@ -0,0 +1,717 @@
+package handlers
+
+import (
+	"cutlass_analytics/internal/dto"
+	"cutlass_analytics/internal/models"
+	"cutlass_analytics/internal/repositories"
+	"cutlass_analytics/internal/types"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// crewLeaderboards describes each crew leaderboard type
+var crewLeaderboards = map[string]dto.LeaderboardTypeInfo{
+	"wins":     {Type: "wins", Title: "Most PvP Wins", Description: "Crews ranked by total PvP wins"},
+	"win_rate": {Type: "win_rate", Title: "Best Win Rate", Description: "Crews ranked by PvP win rate, with at least %d battles"},
+	"battles":  {Type: "battles", Title: "Most PvP Battles", Description: "Crews ranked by total PvP battles fought"},
+	"rank":     {Type: "rank", Title: "Highest Crew Rank", Description: "Crews ranked by crew rank, then by total PvP wins"},
+}
+
+// leaderboardMoversLimit caps the climbers and fallers listed with fame and reputation leaderboards
+const leaderboardMoversLimit = 5
+
+func GetCrewLeaderboardHandler(c *gin.Context, db *gorm.DB) {
+	var req dto.CrewLeaderboardRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+	req.SetDefaults()
+
+	repo := repositories.NewLeaderboardRepository(db)
+	standings, total, err := repo.GetCrewStandings(types.Ocean(req.Ocean), req.Type, req.MinBattles, req.Offset(), req.Limit())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch leaderboard",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	crewsByID, err := loadStandingCrews(db, standings)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch crews",
+			},
+		})
+		return
+	}
+
+	entries := toCrewLeaderboardEntries(standings, crewsByID, req.Ocean)
+	var updatedAt time.Time
+	for _, standing := range standings {
+		if standing.ScrapedAt.After(updatedAt) {
+			updatedAt = standing.ScrapedAt
+		}
+	}
+
+	info := crewLeaderboards[req.Type]
+	if req.Type == "win_rate" {
+		info.Description = fmt.Sprintf(info.Description, req.MinBattles)
+	}
+
+	c.JSON(http.StatusOK, dto.CrewLeaderboardResponse{
+		Ocean:       req.Ocean,
+		Type:        req.Type,
+		Title:       info.Title,
+		Description: info.Description,
+		UpdatedAt:   updatedAt,
+		Entries:     entries,
+		Pagination:  buildPagination(total, req.Page, req.PerPage),
+	})
+}
+
+func GetDailyCrewLeaderboardHandler(c *gin.Context, db *gorm.DB) {
+	var req dto.DailyLeaderboardRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+	req.SetDefaults()
+
+	repo := repositories.NewLeaderboardRepository(db)
+
+	// Default to the most recent day with battle records
+	var date time.Time
+	if req.Date != "" {
+		parsed, err := time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "INVALID_REQUEST",
+					Message: "Invalid date format",
+					Details: "Use YYYY-MM-DD format",
+				},
+			})
+			return
+		}
+		date = parsed
+	} else {
+		latest, err := repo.GetLatestBattleDate(types.Ocean(req.Ocean))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "DATABASE_ERROR",
+					Message: "Failed to fetch latest battle date",
+				},
+			})
+			return
+		}
+		date = time.Now().UTC()
+		if latest != nil {
+			date = latest.UTC()
+		}
+	}
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	standings, total, err := repo.GetDailyCrewStandings(types.Ocean(req.Ocean), date, req.Type, *req.MinBattles, req.Offset(), req.Limit())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch daily leaderboard",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	crewsByID, err := loadStandingCrews(db, standings)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch crews",
+			},
+		})
+		return
+	}
+
+	entries := make([]dto.DailyCrewLeaderboardEntryResponse, len(standings))
+	for i, standing := range standings {
+		record := models.CrewBattleRecord{
+			DailyPVPWins:   standing.DailyPVPWins,
+			DailyPVPLosses: standing.DailyPVPLosses,
+		}
+		entry := dto.DailyCrewLeaderboardEntryResponse{
+			Rank:         standing.Rank,
+			CrewID:       standing.CrewID,
+			DailyWins:    standing.DailyPVPWins,
+			DailyLosses:  standing.DailyPVPLosses,
+			DailyBattles: record.DailyTotalBattles(),
+			DailyWinRate: record.DailyWinRate(),
+			PreviousRank: standing.PreviousRank,
+			RankChange:   standing.RankChange(),
+		}
+		if crew, ok := crewsByID[standing.CrewID]; ok {
+			entry.Name = crew.Name
+			if crew.Flag != nil {
+				entry.FlagName = crew.Flag.Name
+			}
+		}
+		entries[i] = entry
+	}
+
+	c.JSON(http.StatusOK, dto.DailyLeaderboardResponse{
+		Ocean:      req.Ocean,
+		Date:       date,
+		Type:       req.Type,
+		Entries:    entries,
+		Pagination: buildPagination(total, req.Page, req.PerPage),
+	})
+}
+
+func GetFameLeaderboardHandler(c *gin.Context, db *gorm.DB) {
+	var req dto.FameLeaderboardRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+	req.SetDefaults()
+
+	ocean := types.Ocean(req.Ocean)
+	repo := repositories.NewLeaderboardRepository(db)
+	standings, total, err := repo.GetFameStandings(req.EntityType, ocean, req.Days, req.Offset(), req.Limit())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch fame leaderboard",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	climbers, fallers, err := repo.GetFameMovers(req.EntityType, ocean, req.Days, leaderboardMoversLimit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch fame movement",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	updatedAt := latestRankedScrape(standings)
+	pagination := buildPagination(total, req.Page, req.PerPage)
+
+	if req.EntityType == "flag" {
+		flagsByID, crewCounts, err := loadRankedFlags(db, rankedEntityIDs(standings, climbers, fallers))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "DATABASE_ERROR",
+					Message: "Failed to fetch flags",
+				},
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.FlagFameLeaderboardResponse{
+			Ocean:      req.Ocean,
+			Days:       req.Days,
+			UpdatedAt:  updatedAt,
+			Entries:    toFlagFameEntries(standings, flagsByID, crewCounts, req.Ocean),
+			Climbers:   toFlagFameEntries(climbers, flagsByID, crewCounts, req.Ocean),
+			Fallers:    toFlagFameEntries(fallers, flagsByID, crewCounts, req.Ocean),
+			Pagination: pagination,
+		})
+		return
+	}
+
+	crewsByID, recordsByCrew, err := loadRankedCrews(db, rankedEntityIDs(standings, climbers, fallers))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch crews",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CrewFameLeaderboardResponse{
+		Ocean:      req.Ocean,
+		Days:       req.Days,
+		UpdatedAt:  updatedAt,
+		Entries:    toCrewFameEntries(standings, crewsByID, recordsByCrew, req.Ocean),
+		Climbers:   toCrewFameEntries(climbers, crewsByID, recordsByCrew, req.Ocean),
+		Fallers:    toCrewFameEntries(fallers, crewsByID, recordsByCrew, req.Ocean),
+		Pagination: pagination,
+	})
+}
+
+func GetReputationLeaderboardHandler(c *gin.Context, db *gorm.DB) {
+	var req dto.ReputationLeaderboardRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+	req.SetDefaults()
+
+	// Crew info pages show no reputations, so only flags can be ranked
+	if req.EntityType != "flag" {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Reputation leaderboards are only available for flags",
+			},
+		})
+		return
+	}
+
+	ocean := types.Ocean(req.Ocean)
+	reputationType := types.ReputationType(req.ReputationType)
+	repo := repositories.NewLeaderboardRepository(db)
+	standings, total, err := repo.GetFlagReputationStandings(reputationType, ocean, req.Days, req.Offset(), req.Limit())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch reputation leaderboard",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	climbers, fallers, err := repo.GetFlagReputationMovers(reputationType, ocean, req.Days, leaderboardMoversLimit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch reputation movement",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	var ids []uint
+	for _, list := range [][]repositories.LevelStanding{standings, climbers, fallers} {
+		for _, standing := range list {
+			ids = append(ids, standing.EntityID)
+		}
+	}
+	flagRepo := repositories.NewFlagRepository(db)
+	flags, err := flagRepo.FindByIDs(ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch flags",
+			},
+		})
+		return
+	}
+	flagsByID := make(map[uint]models.Flag, len(flags))
+	for _, flag := range flags {
+		flagsByID[flag.ID] = flag
+	}
+
+	var updatedAt time.Time
+	for _, standing := range standings {
+		if standing.ScrapedAt.After(updatedAt) {
+			updatedAt = standing.ScrapedAt
+		}
+	}
+
+	c.JSON(http.StatusOK, dto.ReputationLeaderboardResponse{
+		Ocean:          req.Ocean,
+		ReputationType: req.ReputationType,
+		EntityType:     req.EntityType,
+		Days:           req.Days,
+		UpdatedAt:      updatedAt,
+		Entries:        toFlagReputationEntries(standings, flagsByID, req.Ocean),
+		Climbers:       toFlagReputationEntries(climbers, flagsByID, req.Ocean),
+		Fallers:        toFlagReputationEntries(fallers, flagsByID, req.Ocean),
+		Pagination:     buildPagination(total, req.Page, req.PerPage),
+	})
+}
+
+func GetLeaderboardSummaryHandler(c *gin.Context, db *gorm.DB) {
+	var req dto.LeaderboardSummaryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+	req.SetDefaults()
+
+	ocean := types.Ocean(req.Ocean)
+	repo := repositories.NewLeaderboardRepository(db)
+
+	databaseError := func(message string, err error) {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: message,
+				Details: err.Error(),
+			},
+		})
+	}
+
+	// Crew boards use the same minimum battle floor as /leaderboards/crews
+	crewBoards := make(map[string][]repositories.CrewStanding, 3)
+	var crewStandings []repositories.CrewStanding
+	for _, boardType := range []string{"wins", "win_rate", "rank"} {
+		boardReq := dto.CrewLeaderboardRequest{Type: boardType}
+		boardReq.SetDefaults()
+		standings, _, err := repo.GetCrewStandings(ocean, boardType, boardReq.MinBattles, 0, req.Limit)
+		if err != nil {
+			databaseError("Failed to fetch crew leaderboards", err)
+			return
+		}
+		crewBoards[boardType] = standings
+		crewStandings = append(crewStandings, standings...)
+	}
+
+	flagBoards := make(map[string][]repositories.FlagStanding, 2)
+	var flagIDs []uint
+	for _, boardType := range []string{"wins", "win_rate"} {
+		boardReq := dto.FlagLeaderboardRequest{Type: boardType}
+		boardReq.SetDefaults()
+		standings, err := repo.GetFlagStandings(ocean, boardType, boardReq.MinBattles, req.Limit)
+		if err != nil {
+			databaseError("Failed to fetch flag leaderboards", err)
+			return
+		}
+		flagBoards[boardType] = standings
+		for _, standing := range standings {
+			flagIDs = append(flagIDs, standing.FlagID)
+		}
+	}
+
+	climbers, fallers, err := repo.GetFameMovers("crew", ocean, req.Days, req.Limit)
+	if err != nil {
+		databaseError("Failed to fetch fame movement", err)
+		return
+	}
+
+	crewsByID, err := loadStandingCrews(db, crewStandings)
+	if err != nil {
+		databaseError("Failed to fetch crews", err)
+		return
+	}
+
+	flagRepo := repositories.NewFlagRepository(db)
+	flags, err := flagRepo.FindByIDs(flagIDs)
+	if err != nil {
+		databaseError("Failed to fetch flags", err)
+		return
+	}
+	flagsByID := make(map[uint]models.Flag, len(flags))
+	for _, flag := range flags {
+		flagsByID[flag.ID] = flag
+	}
+	fameByFlag, err := flagRepo.GetLatestFameRecords(flagIDs)
+	if err != nil {
+		databaseError("Failed to fetch flag fame", err)
+		return
+	}
+
+	moverCrewsByID, moverRecords, err := loadRankedCrews(db, rankedEntityIDs(climbers, fallers))
+	if err != nil {
+		databaseError("Failed to fetch crews", err)
+		return
+	}
+
+	var updatedAt time.Time
+	for _, standing := range crewStandings {
+		if standing.ScrapedAt.After(updatedAt) {
+			updatedAt = standing.ScrapedAt
+		}
+	}
+
+	c.JSON(http.StatusOK, dto.LeaderboardSummaryResponse{
+		Ocean:             req.Ocean,
+		UpdatedAt:         updatedAt,
+		TopCrewsByWins:    toCrewLeaderboardEntries(crewBoards["wins"], crewsByID, req.Ocean),
+		TopCrewsByWinRate: toCrewLeaderboardEntries(crewBoards["win_rate"], crewsByID, req.Ocean),
+		TopCrewsByRank:    toCrewLeaderboardEntries(crewBoards["rank"], crewsByID, req.Ocean),
+		TopFlagsByWins:    toFlagLeaderboardEntries(flagBoards["wins"], flagsByID, fameByFlag, req.Ocean),
+		TopFlagsByWinRate: toFlagLeaderboardEntries(flagBoards["win_rate"], flagsByID, fameByFlag, req.Ocean),
+		FameClimbers:      toCrewFameEntries(climbers, moverCrewsByID, moverRecords, req.Ocean),
+		FameFallers:       toCrewFameEntries(fallers, moverCrewsByID, moverRecords, req.Ocean),
+	})
+}
+
+// Helper functions
+
+func toCrewLeaderboardEntries(standings []repositories.CrewStanding, crewsByID map[uint]models.Crew, ocean string) []dto.CrewLeaderboardEntryResponse {
+	entries := make([]dto.CrewLeaderboardEntryResponse, len(standings))
+	for i, standing := range standings {
+		record := models.CrewBattleRecord{
+			TotalPVPWins:   standing.TotalPVPWins,
+			TotalPVPLosses: standing.TotalPVPLosses,
+		}
+		entry := dto.CrewLeaderboardEntryResponse{
+			Rank:           standing.Rank,
+			CrewID:         standing.CrewID,
+			Ocean:          ocean,
+			CrewRank:       string(standing.CrewRank),
+			TotalPVPWins:   standing.TotalPVPWins,
+			TotalPVPLosses: standing.TotalPVPLosses,
+			WinRate:        record.WinRate(),
+			TotalBattles:   record.TotalBattles(),
+			PreviousRank:   standing.PreviousRank,
+			RankChange:     standing.RankChange(),
+		}
+		if crew, ok := crewsByID[standing.CrewID]; ok {
+			entry.GameCrewID = crew.GameCrewID
+			entry.Name = crew.Name
+			if crew.Flag != nil {
+				entry.FlagID = &crew.Flag.ID
+				entry.FlagName = crew.Flag.Name
+			}
+		}
+		entries[i] = entry
+	}
+	return entries
+}
+
+// loadStandingCrews loads the crews on a leaderboard page, keyed by ID
+func loadStandingCrews(db *gorm.DB, standings []repositories.CrewStanding) (map[uint]models.Crew, error) {
+	ids := make([]uint, len(standings))
+	for i, standing := range standings {
+		ids[i] = standing.CrewID
+	}
+
+	crewRepo := repositories.NewCrewRepository(db)
+	crews, err := crewRepo.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	crewsByID := make(map[uint]models.Crew, len(crews))
+	for _, crew := range crews {
+		crewsByID[crew.ID] = crew
+	}
+	return crewsByID, nil
+}
+
+func toFlagLeaderboardEntries(standings []repositories.FlagStanding, flagsByID map[uint]models.Flag, fameByFlag map[uint]models.FlagFameRecord, ocean string) []dto.FlagLeaderboardEntryResponse {
+	entries := make([]dto.FlagLeaderboardEntryResponse, len(standings))
+	for i, standing := range standings {
+		record := models.CrewBattleRecord{
+			TotalPVPWins:   standing.TotalPVPWins,
+			TotalPVPLosses: standing.TotalPVPLosses,
+		}
+		entry := dto.FlagLeaderboardEntryResponse{
+			Rank:           standing.Rank,
+			FlagID:         standing.FlagID,
+			Ocean:          ocean,
+			CrewCount:      standing.CrewCount,
+			TotalPVPWins:   standing.TotalPVPWins,
+			TotalPVPLosses: standing.TotalPVPLosses,
+			WinRate:        record.WinRate(),
+			TotalBattles:   record.TotalBattles(),
+		}
+		if flag, ok := flagsByID[standing.FlagID]; ok {
+			entry.GameFlagID = flag.GameFlagID
+			entry.Name = flag.Name
+		}
+		if fame, ok := fameByFlag[standing.FlagID]; ok {
+			entry.FameLevel = string(fame.FameLevel)
+		}
+		entries[i] = entry
+	}
+	return entries
+}
+
+func toCrewFameEntries(standings []repositories.RankedStanding, crewsByID map[uint]models.Crew, recordsByCrew map[uint]models.CrewBattleRecord, ocean string) []dto.CrewFameLeaderboardEntryResponse {
+	entries := make([]dto.CrewFameLeaderboardEntryResponse, len(standings))
+	for i, standing := range standings {
+		entry := dto.CrewFameLeaderboardEntryResponse{
+			Rank:         rankOrZero(standing.Rank),
+			CrewID:       standing.EntityID,
+			Ocean:        ocean,
+			FameLevel:    standing.Level,
+			PreviousRank: standing.PreviousRank,
+			RankChange:   standing.RankChange(),
+		}
+		if crew, ok := crewsByID[standing.EntityID]; ok {
+			entry.Name = crew.Name
+			if crew.Flag != nil {
+				entry.FlagName = crew.Flag.Name
+			}
+		}
+		if record, ok := recordsByCrew[standing.EntityID]; ok {
+			entry.CrewRank = string(record.CrewRank)
+		}
+		entries[i] = entry
+	}
+	return entries
+}
+
+func toFlagFameEntries(standings []repositories.RankedStanding, flagsByID map[uint]models.Flag, crewCounts map[uint]int, ocean string) []dto.FlagFameLeaderboardEntryResponse {
+	entries := make([]dto.FlagFameLeaderboardEntryResponse, len(standings))
+	for i, standing := range standings {
+		entries[i] = dto.FlagFameLeaderboardEntryResponse{
+			Rank:         rankOrZero(standing.Rank),
+			FlagID:       standing.EntityID,
+			Name:         flagsByID[standing.EntityID].Name,
+			Ocean:        ocean,
+			FameLevel:    standing.Level,
+			CrewCount:    crewCounts[standing.EntityID],
+			PreviousRank: standing.PreviousRank,
+			RankChange:   standing.RankChange(),
+		}
+	}
+	return entries
+}
+
+func toFlagReputationEntries(standings []repositories.LevelStanding, flagsByID map[uint]models.Flag, ocean string) []dto.ReputationLeaderboardEntryResponse {
+	entries := make([]dto.ReputationLeaderboardEntryResponse, len(standings))
+	for i, standing := range standings {
+		entries[i] = dto.ReputationLeaderboardEntryResponse{
+			Rank:            standing.Rank,
+			EntityID:        standing.EntityID,
+			EntityType:      "flag",
+			Name:            flagsByID[standing.EntityID].Name,
+			Ocean:           ocean,
+			ReputationLevel: standing.Level,
+			PreviousLevel:   standing.PreviousLevel,
+			LevelChange:     standing.LevelChange,
+		}
+	}
+	return entries
+}
+
+// rankedEntityIDs collects the entity IDs across lists of standings
+func rankedEntityIDs(lists ...[]repositories.RankedStanding) []uint {
+	var ids []uint
+	for _, standings := range lists {
+		for _, standing := range standings {
+			ids = append(ids, standing.EntityID)
+		}
+	}
+	return ids
+}
+
+// loadRankedCrews loads the crews with the given IDs along with their latest battle records
+func loadRankedCrews(db *gorm.DB, ids []uint) (map[uint]models.Crew, map[uint]models.CrewBattleRecord, error) {
+	crewRepo := repositories.NewCrewRepository(db)
+	crews, err := crewRepo.FindByIDs(ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	crewsByID := make(map[uint]models.Crew, len(crews))
+	for _, crew := range crews {
+		crewsByID[crew.ID] = crew
+	}
+
+	recordsByCrew, err := crewRepo.GetLatestBattleRecords(ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	return crewsByID, recordsByCrew, nil
+}
+
+// loadRankedFlags loads the flags with the given IDs along with their active crew counts
+func loadRankedFlags(db *gorm.DB, ids []uint) (map[uint]models.Flag, map[uint]int, error) {
+	flagRepo := repositories.NewFlagRepository(db)
+	flags, err := flagRepo.FindByIDs(ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	flagsByID := make(map[uint]models.Flag, len(flags))
+	for _, flag := range flags {
+		flagsByID[flag.ID] = flag
+	}
+
+	crewCounts, err := flagRepo.GetActiveCrewCounts(ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	return flagsByID, crewCounts, nil
+}
+
+// latestRankedScrape returns the most recent scrape time among standings
+func latestRankedScrape(standings []repositories.RankedStanding) time.Time {
+	var latest time.Time
+	for _, standing := range standings {
+		if standing.ScrapedAt.After(latest) {
+			latest = standing.ScrapedAt
+		}
+	}
+	return latest
+}
+
+func rankOrZero(rank *int) int {
+	if rank == nil {
+		return 0
+	}
+	return *rank
+}
@@ -0,0 +1,464 @@
+package handlers
+
+import (
+	"cutlass_analytics/internal/dto"
+	"cutlass_analytics/internal/models"
+	"cutlass_analytics/internal/repositories"
+	"cutlass_analytics/internal/types"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func GetMarketPricesHandler(c *gin.Context, db *gorm.DB) {
+	var req dto.MarketPricesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+	req.SetDefaults()
+
+	// Market orders are keyed by name, so resolve requested IDs first
+	var islandName string
+	if req.IslandID != nil {
+		islandRepo := repositories.NewIslandRepository(db)
+		island, err := islandRepo.FindByID(*req.IslandID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				c.JSON(http.StatusNotFound, dto.APIResponse{
+					Success: false,
+					Error: &dto.APIError{
+						Code:    "NOT_FOUND",
+						Message: "Island not found",
+					},
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "DATABASE_ERROR",
+					Message: "Failed to fetch island",
+				},
+			})
+			return
+		}
+		if island.Ocean != types.Ocean(req.Ocean) {
+			c.JSON(http.StatusBadRequest, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "INVALID_REQUEST",
+					Message: "Island is not in the requested ocean",
+				},
+			})
+			return
+		}
+		islandName = island.Name
+	}
+
+	var commodityName string
+	if req.CommodityID != nil {
+		commodityRepo := repositories.NewCommodityRepository(db)
+		commodity, err := commodityRepo.FindByID(*req.CommodityID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				c.JSON(http.StatusNotFound, dto.APIResponse{
+					Success: false,
+					Error: &dto.APIError{
+						Code:    "NOT_FOUND",
+						Message: "Commodity not found",
+					},
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "DATABASE_ERROR",
+					Message: "Failed to fetch commodity",
+				},
+			})
+			return
+		}
+		commodityName = commodity.Name
+	}
+
+	repo := repositories.NewMarketPriceRepository(db)
+	snapshot, err := repo.GetLatestSnapshot(types.Ocean(req.Ocean))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch market snapshot",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	prices, err := repo.GetCurrentPrices(types.Ocean(req.Ocean), islandName, commodityName)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch market prices",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	prices = filterMarketPrices(prices, req.HasBuyOffer, req.HasSellOffer, req.Category)
+	sortMarketPrices(prices, req.SortBy)
+
+	// Paginate the summarized book in memory
+	total := len(prices)
+	start := req.Offset()
+	if start > total {
+		start = total
+	}
+	end := start + req.Limit()
+	if end > total {
+		end = total
+	}
+
+	responses := make([]dto.MarketPriceResponse, 0, end-start)
+	for i := start; i < end; i++ {
+		responses = append(responses, toMarketPriceResponse(&prices[i]))
+	}
+
+	response := dto.MarketPriceListResponse{
+		Ocean:      req.Ocean,
+		Prices:     responses,
+		Pagination: buildPagination(int64(total), req.Page, req.PerPage),
+	}
+	if snapshot != nil {
+		response.ScrapedAt = snapshot.ImportedAt
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func GetIslandMarketHandler(c *gin.Context, db *gorm.DB) {
+	var param dto.IslandIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid island ID",
+			},
+		})
+		return
+	}
+
+	var req dto.IslandMarketPricesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	islandRepo := repositories.NewIslandRepository(db)
+	island, err := islandRepo.FindByID(param.ID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "NOT_FOUND",
+					Message: "Island not found",
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch island",
+			},
+		})
+		return
+	}
+
+	repo := repositories.NewMarketPriceRepository(db)
+	snapshot, err := repo.GetLatestSnapshot(island.Ocean)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch market snapshot",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	prices, err := repo.GetCurrentPrices(island.Ocean, island.Name, "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch market prices",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	prices = filterMarketPrices(prices, req.HasBuyOffer, req.HasSellOffer, req.Category)
+	sortMarketPrices(prices, "commodity")
+
+	responses := make([]dto.MarketPriceResponse, len(prices))
+	for i := range prices {
+		responses[i] = toMarketPriceResponse(&prices[i])
+	}
+
+	response := dto.IslandMarketPricesResponse{
+		Island: toIslandBrief(island),
+		Prices: responses,
+	}
+	if snapshot != nil {
+		response.ScrapedAt = snapshot.ImportedAt
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func GetCommodityMarketHandler(c *gin.Context, db *gorm.DB) {
+	var param dto.CommodityIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid commodity ID",
+			},
+		})
+		return
+	}
+
+	var req dto.CommodityMarketPricesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+	req.SetDefaults()
+
+	commodityRepo := repositories.NewCommodityRepository(db)
+	commodity, err := commodityRepo.FindByID(param.ID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "NOT_FOUND",
+					Message: "Commodity not found",
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch commodity",
+			},
+		})
+		return
+	}
+
+	repo := repositories.NewMarketPriceRepository(db)
+	snapshot, err := repo.GetLatestSnapshot(types.Ocean(req.Ocean))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch market snapshot",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	prices, err := repo.GetCurrentPrices(types.Ocean(req.Ocean), "", commodity.Name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch market prices",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	// In stock means the island has something for sale
+	prices = filterMarketPrices(prices, nil, req.HasStock, "")
+	sortMarketPrices(prices, req.SortBy)
+
+	response := dto.CommodityMarketPricesResponse{
+		Commodity: toCommodityBrief(commodity),
+		Ocean:     req.Ocean,
+		Prices:    make([]dto.MarketPriceResponse, len(prices)),
+	}
+	if snapshot != nil {
+		response.ScrapedAt = snapshot.ImportedAt
+	}
+
+	for i := range prices {
+		price := &prices[i]
+		response.Prices[i] = toMarketPriceResponse(price)
+
+		if price.HasSellOffer() && (response.LowestSellPrice == nil || *price.SellPrice < response.LowestSellPrice.Price) {
+			response.LowestSellPrice = &dto.MarketPriceSummaryEntry{
+				Island:   toIslandBrief(&price.Island),
+				Price:    *price.SellPrice,
+				Quantity: *price.SellQuantity,
+			}
+		}
+		if price.HasBuyOffer() && (response.HighestBuyPrice == nil || *price.BuyPrice > response.HighestBuyPrice.Price) {
+			response.HighestBuyPrice = &dto.MarketPriceSummaryEntry{
+				Island:   toIslandBrief(&price.Island),
+				Price:    *price.BuyPrice,
+				Quantity: *price.BuyQuantity,
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Helper functions
+
+// filterMarketPrices keeps prices matching the offer and category filters; nil filters match everything.
+// Commodities without a catalog row have no category and never match a category filter.
+func filterMarketPrices(prices []models.MarketPrice, hasBuyOffer, hasSellOffer *bool, category string) []models.MarketPrice {
+	filtered := prices[:0]
+	for _, price := range prices {
+		if hasBuyOffer != nil && price.HasBuyOffer() != *hasBuyOffer {
+			continue
+		}
+		if hasSellOffer != nil && price.HasSellOffer() != *hasSellOffer {
+			continue
+		}
+		if category != "" && price.Commodity.Category != types.CommodityCategory(category) {
+			continue
+		}
+		filtered = append(filtered, price)
+	}
+	return filtered
+}
+
+// sortMarketPrices orders prices for display. Bids sort highest first and asks and spreads
+// lowest first; prices without the sorted value go last. Ties fall back to commodity then island name.
+func sortMarketPrices(prices []models.MarketPrice, sortBy string) {
+	byName := func(a, b *models.MarketPrice) bool {
+		if a.Commodity.Name != b.Commodity.Name {
+			return a.Commodity.Name < b.Commodity.Name
+		}
+		return a.Island.Name < b.Island.Name
+	}
+
+	// compareOptional reports whether a sorts before b, with nil values last
+	compareOptional := func(a, b *int, less func(x, y int) bool) (bool, bool) {
+		switch {
+		case a == nil && b == nil:
+			return false, false
+		case a == nil:
+			return false, true
+		case b == nil:
+			return true, true
+		case *a == *b:
+			return false, false
+		}
+		return less(*a, *b), true
+	}
+	ascending := func(x, y int) bool { return x < y }
+	descending := func(x, y int) bool { return x > y }
+
+	sort.SliceStable(prices, func(i, j int) bool {
+		a, b := &prices[i], &prices[j]
+
+		var before, decided bool
+		switch sortBy {
+		case "buy_price":
+			before, decided = compareOptional(a.BuyPrice, b.BuyPrice, descending)
+		case "sell_price":
+			before, decided = compareOptional(a.SellPrice, b.SellPrice, ascending)
+		case "spread":
+			before, decided = compareOptional(a.Spread(), b.Spread(), ascending)
+		case "island":
+			if a.Island.Name != b.Island.Name {
+				return a.Island.Name < b.Island.Name
+			}
+		}
+		if decided {
+			return before
+		}
+		return byName(a, b)
+	})
+}
+
+func toMarketPriceResponse(price *models.MarketPrice) dto.MarketPriceResponse {
+	return dto.MarketPriceResponse{
+		IslandID:      price.IslandID,
+		IslandName:    price.Island.Name,
+		CommodityID:   price.CommodityID,
+		CommodityName: price.Commodity.Name,
+		ScrapedAt:     price.ScrapedAt,
+		BuyPrice:      price.BuyPrice,
+		BuyQuantity:   price.BuyQuantity,
+		SellPrice:     price.SellPrice,
+		SellQuantity:  price.SellQuantity,
+		Spread:        price.Spread(),
+	}
+}
+
+func toIslandBrief(island *models.Island) dto.IslandBrief {
+	return dto.IslandBrief{
+		ID:           island.ID,
+		GameIslandID: island.GameIslandID,
+		Name:         island.Name,
+		Ocean:        string(island.Ocean),
+		IsColonized:  island.IsColonized,
+	}
+}
+
+func toCommodityBrief(commodity *models.Commodity) dto.CommodityBrief {
+	return dto.CommodityBrief{
+		ID:          commodity.ID,
+		Name:        commodity.Name,
+		DisplayName: commodity.DisplayName,
+		Category:    string(commodity.Category),
+	}
+}
@@ -0,0 +1,476 @@
+package handlers
+
+import (
+	"cutlass_analytics/internal/dto"
+	"cutlass_analytics/internal/models"
+	"cutlass_analytics/internal/repositories"
+	"cutlass_analytics/internal/types"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// oceanRecentPeriod is the window recent PvP activity is summed over
+const oceanRecentPeriod = 7 * 24 * time.Hour
+
+const (
+	// populationSwingZScore is how many standard deviations an island's 7 day growth must be
+	// from the ocean mean to count as an unusual swing
+	populationSwingZScore = 2.0
+	// populationSwingMinIslands is how many islands need a 7 day growth before swings are flagged
+	populationSwingMinIslands = 5
+)
+
+func ListOceansHandler(c *gin.Context, db *gorm.DB) {
+	lastScraped, err := repositories.NewOceanRepository(db).GetLastScrapedTimes()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch oceans",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	oceans := types.AllOceans()
+	responses := make([]dto.OceanResponse, len(oceans))
+	for i, ocean := range oceans {
+		response := dto.OceanResponse{
+			Name:        ocean.String(),
+			DisplayName: ocean.DisplayName(),
+			BaseURL:     ocean.BaseURL(),
+		}
+		if scrapedAt, ok := lastScraped[ocean]; ok {
+			response.IsActive = true
+			response.LastScrapedAt = &scrapedAt
+		}
+		responses[i] = response
+	}
+
+	c.JSON(http.StatusOK, dto.OceanListResponse{
+		Oceans: responses,
+	})
+}
+
+func GetOceanStatsHandler(c *gin.Context, db *gorm.DB) {
+	var param dto.OceanPathParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid ocean",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	ocean := types.Ocean(param.Ocean)
+	if !ocean.IsValid() {
+		c.JSON(http.StatusNotFound, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "NOT_FOUND",
+				Message: "Ocean is not tracked",
+			},
+		})
+		return
+	}
+
+	stats, err := repositories.NewOceanRepository(db).GetStats(ocean, time.Now().Add(-oceanRecentPeriod))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch ocean stats",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, toOceanStatsResponse(ocean, stats))
+}
+
+func CompareOceansHandler(c *gin.Context, db *gorm.DB) {
+	repo := repositories.NewOceanRepository(db)
+	recentSince := time.Now().Add(-oceanRecentPeriod)
+
+	response := dto.OceanComparisonResponse{}
+	for _, ocean := range types.AllOceans() {
+		stats, err := repo.GetStats(ocean, recentSince)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "DATABASE_ERROR",
+					Message: "Failed to fetch ocean stats",
+					Details: err.Error(),
+				},
+			})
+			return
+		}
+		if stats.LastScrapedAt != nil && stats.LastScrapedAt.After(response.UpdatedAt) {
+			response.UpdatedAt = *stats.LastScrapedAt
+		}
+		response.Oceans = append(response.Oceans, toOceanStatsResponse(ocean, stats))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func GetOceanPopulationHandler(c *gin.Context, db *gorm.DB) {
+	var param dto.OceanPathParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid ocean",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	var req dto.OceanPopulationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+	req.SetDefaults()
+
+	ocean := types.Ocean(param.Ocean)
+	if !ocean.IsValid() {
+		c.JSON(http.StatusNotFound, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "NOT_FOUND",
+				Message: "Ocean is not tracked",
+			},
+		})
+		return
+	}
+
+	now := time.Now()
+	weekAgo := now.AddDate(0, 0, -7)
+	monthAgo := now.AddDate(0, 0, -30)
+
+	repo := repositories.NewIslandRepository(db)
+	history, err := repo.GetOceanPopulationHistory(ocean, monthAgo)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch population history",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	// Split the history into each island's records, oldest first
+	var ids []uint
+	histories := make(map[uint][]models.IslandPopulation)
+	for _, record := range history {
+		if _, ok := histories[record.IslandID]; !ok {
+			ids = append(ids, record.IslandID)
+		}
+		histories[record.IslandID] = append(histories[record.IslandID], record)
+	}
+
+	islands, err := repo.FindByIDs(ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch islands",
+			},
+		})
+		return
+	}
+	islandsByID := make(map[uint]*models.Island, len(islands))
+	for i := range islands {
+		islandsByID[islands[i].ID] = &islands[i]
+	}
+
+	response := dto.OceanPopulationResponse{
+		Ocean:         param.Ocean,
+		IslandCount:   len(ids),
+		UnusualSwings: []dto.IslandPopulationSwing{},
+	}
+	entries := make([]dto.IslandPopulationRankEntry, len(ids))
+	weekStart, monthStart := 0, 0
+	weeklyGrowth := make(map[uint]float64)
+	for i, id := range ids {
+		records := histories[id]
+		latest := records[len(records)-1]
+
+		entry := dto.IslandPopulationRankEntry{
+			Island:     dto.IslandBrief{ID: id},
+			Population: latest.Population,
+		}
+		if island, ok := islandsByID[id]; ok {
+			entry.Island = toIslandBrief(island)
+		}
+		if base := populationAt(records, weekAgo); base != nil {
+			growth := populationGrowth(base.Population, latest.Population)
+			entry.Growth7d = &growth
+			weekStart += base.Population
+			response.Growth7d.Change += growth.Change
+			if growth.Percentage != nil {
+				weeklyGrowth[id] = *growth.Percentage
+			}
+		}
+		if base := populationAt(records, monthAgo); base != nil {
+			growth := populationGrowth(base.Population, latest.Population)
+			entry.Growth30d = &growth
+			monthStart += base.Population
+			response.Growth30d.Change += growth.Change
+		}
+
+		var window []models.IslandPopulation
+		for _, record := range records {
+			if !record.ScrapedAt.Before(monthAgo) {
+				window = append(window, record)
+			}
+		}
+		entry.MinPopulation, entry.MaxPopulation, entry.AvgPopulation = calculatePopulationStats(window)
+
+		response.TotalPopulation += latest.Population
+		if latest.ScrapedAt.After(response.ScrapedAt) {
+			response.ScrapedAt = latest.ScrapedAt
+		}
+		entries[i] = entry
+	}
+	response.Growth7d.Percentage = populationGrowth(weekStart, weekStart+response.Growth7d.Change).Percentage
+	response.Growth30d.Percentage = populationGrowth(monthStart, monthStart+response.Growth30d.Change).Percentage
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Population != entries[j].Population {
+			return entries[i].Population > entries[j].Population
+		}
+		return entries[i].Island.Name < entries[j].Island.Name
+	})
+
+	swings := populationSwings(weeklyGrowth)
+	for i := range entries {
+		entry := &entries[i]
+		entry.Rank = i + 1
+		if i > 0 && entry.Population == entries[i-1].Population {
+			entry.Rank = entries[i-1].Rank
+		}
+		if response.TotalPopulation > 0 {
+			entry.Percentage = float64(entry.Population) / float64(response.TotalPopulation) * 100
+		}
+		if zScore, ok := swings[entry.Island.ID]; ok {
+			entry.UnusualSwing = true
+			response.UnusualSwings = append(response.UnusualSwings, dto.IslandPopulationSwing{
+				Island:     entry.Island,
+				Population: entry.Population,
+				Growth7d:   *entry.Growth7d,
+				ZScore:     zScore,
+			})
+		}
+	}
+	sort.SliceStable(response.UnusualSwings, func(i, j int) bool {
+		return math.Abs(response.UnusualSwings[i].ZScore) > math.Abs(response.UnusualSwings[j].ZScore)
+	})
+
+	if len(entries) > req.TopN {
+		entries = entries[:req.TopN]
+	}
+	response.TopIslands = entries
+
+	c.JSON(http.StatusOK, response)
+}
+
+func GetOceanTerritoryHandler(c *gin.Context, db *gorm.DB) {
+	var param dto.OceanPathParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid ocean",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	ocean := types.Ocean(param.Ocean)
+	if !ocean.IsValid() {
+		c.JSON(http.StatusNotFound, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "NOT_FOUND",
+				Message: "Ocean is not tracked",
+			},
+		})
+		return
+	}
+
+	repo := repositories.NewIslandRepository(db)
+	islands, err := repo.FindByOcean(ocean)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch islands",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+	populations, err := repo.GetLatestPopulations(islandIDs(islands))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch island populations",
+			},
+		})
+		return
+	}
+
+	response := dto.OceanTerritoryResponse{
+		Ocean:        param.Ocean,
+		TotalIslands: len(islands),
+		Flags:        []dto.FlagTerritorySummary{},
+	}
+	flagIndex := make(map[uint]int)
+	for i := range islands {
+		island := &islands[i]
+		if island.GovernorFlagID == nil {
+			response.UngovernedIslands++
+			continue
+		}
+
+		response.GovernedIslands++
+		j, ok := flagIndex[*island.GovernorFlagID]
+		if !ok {
+			j = len(response.Flags)
+			flagIndex[*island.GovernorFlagID] = j
+			flag := dto.FlagBrief{ID: *island.GovernorFlagID}
+			if island.GovernorFlag != nil {
+				flag = toFlagBrief(island.GovernorFlag)
+			}
+			response.Flags = append(response.Flags, dto.FlagTerritorySummary{Flag: flag})
+		}
+		summary := &response.Flags[j]
+		summary.IslandCount++
+		summary.TotalPopulation += populations[island.ID]
+		summary.Islands = append(summary.Islands, toIslandBrief(island))
+	}
+
+	for i := range response.Flags {
+		response.Flags[i].Share = float64(response.Flags[i].IslandCount) / float64(len(islands)) * 100
+	}
+	sort.SliceStable(response.Flags, func(i, j int) bool {
+		if response.Flags[i].IslandCount != response.Flags[j].IslandCount {
+			return response.Flags[i].IslandCount > response.Flags[j].IslandCount
+		}
+		return response.Flags[i].TotalPopulation > response.Flags[j].TotalPopulation
+	})
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Helper functions
+
+// populationAt returns the last of an island's records, oldest first, scraped at or before t
+func populationAt(records []models.IslandPopulation, t time.Time) *models.IslandPopulation {
+	var found *models.IslandPopulation
+	for i := range records {
+		if records[i].ScrapedAt.After(t) {
+			break
+		}
+		found = &records[i]
+	}
+	return found
+}
+
+func populationGrowth(from, to int) dto.PopulationGrowth {
+	growth := dto.PopulationGrowth{Change: to - from}
+	if from > 0 {
+		percentage := float64(to-from) / float64(from) * 100
+		growth.Percentage = &percentage
+	}
+	return growth
+}
+
+// populationSwings returns the z-score of every island whose growth percentage is at least
+// populationSwingZScore standard deviations from the mean, keyed by island ID
+func populationSwings(growth map[uint]float64) map[uint]float64 {
+	swings := make(map[uint]float64)
+	if len(growth) < populationSwingMinIslands {
+		return swings
+	}
+
+	var sum float64
+	for _, percentage := range growth {
+		sum += percentage
+	}
+	mean := sum / float64(len(growth))
+
+	var variance float64
+	for _, percentage := range growth {
+		variance += (percentage - mean) * (percentage - mean)
+	}
+	stddev := math.Sqrt(variance / float64(len(growth)))
+	if stddev == 0 {
+		return swings
+	}
+
+	for id, percentage := range growth {
+		if zScore := (percentage - mean) / stddev; math.Abs(zScore) >= populationSwingZScore {
+			swings[id] = zScore
+		}
+	}
+	return swings
+}
+
+func toOceanStatsResponse(ocean types.Ocean, stats *repositories.OceanStats) dto.OceanStatsResponse {
+	response := dto.OceanStatsResponse{
+		Ocean:            ocean.String(),
+		DisplayName:      ocean.DisplayName(),
+		TotalCrews:       stats.TotalCrews,
+		ActiveCrews:      stats.ActiveCrews,
+		TotalFlags:       stats.TotalFlags,
+		ActiveFlags:      stats.ActiveFlags,
+		TotalPVPBattles:  stats.TotalPVPBattles(),
+		TotalPVPWins:     stats.TotalPVPWins,
+		TotalPVPLosses:   stats.TotalPVPLosses,
+		RecentPVPBattles: stats.RecentPVPBattles(),
+		TotalIslands:     stats.TotalIslands,
+		ColonizedIslands: stats.ColonizedIslands,
+		TotalPopulation:  stats.TotalPopulation,
+		AvgTaxRate:       stats.AvgTaxRate,
+		TaxedCommodities: stats.TaxedCommodities,
+	}
+	if stats.LastScrapedAt != nil {
+		response.LastScrapedAt = *stats.LastScrapedAt
+	}
+	return response
+}
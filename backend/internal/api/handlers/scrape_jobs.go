@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"cutlass_analytics/internal/dto"
+	"cutlass_analytics/internal/jobs"
 	"cutlass_analytics/internal/models"
 	"cutlass_analytics/internal/repositories"
 	"cutlass_analytics/internal/types"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -155,6 +157,110 @@ func GetScrapeStatusHandler(c *gin.Context, db *gorm.DB) {
 	c.JSON(http.StatusOK, response)
 }
 
+func TriggerScrapeJobHandler(c *gin.Context, scheduler *jobs.Scheduler) {
+	var req dto.TriggerScrapeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request body",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+	req.SetDefaults()
+
+	job, err := scheduler.StartJob(types.Ocean(req.Ocean), models.ScrapeJobType(req.JobType))
+	if err != nil {
+		if errors.Is(err, jobs.ErrJobAlreadyRunning) {
+			c.JSON(http.StatusConflict, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "JOB_ALREADY_RUNNING",
+					Message: "A scrape job of this type is already running for the ocean",
+					Details: err.Error(),
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "SCRAPE_ERROR",
+				Message: "Failed to start scrape job",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.ScrapeTriggerResponse{
+		Success: true,
+		Message: "Scrape job started",
+		JobID:   job.ID,
+		Job:     toScrapeJobResponse(job),
+	})
+}
+
+func CancelScrapeJobHandler(c *gin.Context, db *gorm.DB, scheduler *jobs.Scheduler) {
+	var param dto.ScrapeJobIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid scrape job ID",
+			},
+		})
+		return
+	}
+
+	repo := repositories.NewScrapeJobRepository(db)
+	job, err := repo.FindByID(param.ID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "NOT_FOUND",
+					Message: "Scrape job not found",
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch scrape job",
+			},
+		})
+		return
+	}
+
+	// Jobs marked running that this server isn't running, such as reprocessing jobs, can't be
+	// cancelled from here
+	if !job.IsRunning() || scheduler.CancelJob(job.ID) != nil {
+		c.JSON(http.StatusConflict, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "JOB_NOT_RUNNING",
+				Message: "Scrape job is not running on this server",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.ScrapeCancelResponse{
+		Success: true,
+		Message: "Scrape job cancellation requested",
+		JobID:   job.ID,
+		Job:     toScrapeJobResponse(job),
+	})
+}
+
 // Helper functions
 
 func toScrapeJobResponse(job *models.ScrapeJob) dto.ScrapeJobResponse {
@@ -179,5 +285,10 @@ func toScrapeJobResponse(job *models.ScrapeJob) dto.ScrapeJobResponse {
 		ItemsFailed:    job.ItemsFailed,
 		SuccessRate:    job.SuccessRate(),
 		ErrorMessage:   job.ErrorMessage,
+
+		ReprocessedJobID: job.ReprocessedJobID,
+
+		Stage:       job.Stage,
+		ResumeCount: job.ResumeCount,
 	}
 }
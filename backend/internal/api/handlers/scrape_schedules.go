@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"cutlass_analytics/internal/dto"
+	"cutlass_analytics/internal/jobs"
+	"cutlass_analytics/internal/models"
+	"cutlass_analytics/internal/repositories"
+	"cutlass_analytics/internal/types"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func ListScrapeSchedulesHandler(c *gin.Context, db *gorm.DB, scheduler *jobs.Scheduler) {
+	repo := repositories.NewScrapeScheduleRepository(db)
+	schedules, err := repo.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch scrape schedules",
+			},
+		})
+		return
+	}
+
+	responses := make([]dto.ScrapeScheduleResponse, len(schedules))
+	for i := range schedules {
+		response, err := toScrapeScheduleResponse(db, scheduler, &schedules[i])
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "DATABASE_ERROR",
+					Message: "Failed to fetch last scrape job",
+				},
+			})
+			return
+		}
+		responses[i] = response
+	}
+
+	c.JSON(http.StatusOK, dto.ScrapeScheduleListResponse{
+		Schedules: responses,
+	})
+}
+
+func UpdateScrapeScheduleHandler(c *gin.Context, db *gorm.DB, scheduler *jobs.Scheduler) {
+	var req dto.UpdateScrapeScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request body",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+	req.SetDefaults()
+
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	schedule := models.ScrapeSchedule{
+		Ocean:     types.Ocean(req.Ocean),
+		JobType:   models.ScrapeJobType(req.JobType),
+		Frequency: models.ScrapeFrequency(req.Frequency),
+		Time:      req.Time,
+		IsEnabled: req.IsEnabled,
+	}
+
+	repo := repositories.NewScrapeScheduleRepository(db)
+	if err := repo.Save(&schedule); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to save scrape schedule",
+			},
+		})
+		return
+	}
+
+	// Apply the change now rather than at the next periodic sync
+	if err := scheduler.SyncSchedules(); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "SCHEDULER_ERROR",
+				Message: "Scrape schedule saved but not applied",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := toScrapeScheduleResponse(db, scheduler, &schedule)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch last scrape job",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Helper functions
+
+func toScrapeScheduleResponse(db *gorm.DB, scheduler *jobs.Scheduler, schedule *models.ScrapeSchedule) (dto.ScrapeScheduleResponse, error) {
+	response := dto.ScrapeScheduleResponse{
+		Ocean:     string(schedule.Ocean),
+		JobType:   string(schedule.JobType),
+		Frequency: string(schedule.Frequency),
+		Time:      schedule.Time,
+		NextRun:   scheduler.NextRun(schedule.Ocean, schedule.JobType),
+		IsEnabled: schedule.IsEnabled,
+	}
+
+	last, err := repositories.NewScrapeJobRepository(db).GetLatest(schedule.Ocean, schedule.JobType)
+	if err != nil {
+		return response, err
+	}
+	if last != nil {
+		response.LastRun = &last.StartedAt
+		response.LastStatus = string(last.Status)
+	}
+	return response, nil
+}
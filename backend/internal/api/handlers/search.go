@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"cutlass_analytics/internal/dto"
+	"cutlass_analytics/internal/repositories"
+	"cutlass_analytics/internal/types"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func SearchHandler(c *gin.Context, db *gorm.DB) {
+	var req dto.SearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+	req.SetDefaults()
+
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	filter := repositories.SearchFilter{
+		Ocean: types.Ocean(req.Ocean),
+		Types: req.Types,
+	}
+	hits, total, err := repositories.NewSearchRepository(db).Search(req.Query, filter, req.Offset(), req.Limit())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to search",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	crews, err := crewSearchResults(db, hits)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch crews",
+			},
+		})
+		return
+	}
+	flags, err := flagSearchResults(db, hits)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch flags",
+			},
+		})
+		return
+	}
+	islands, err := islandSearchResults(db, hits)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch islands",
+			},
+		})
+		return
+	}
+
+	response := dto.SearchResponse{
+		Query:      req.Query,
+		Results:    []dto.SearchResultResponse{},
+		TotalCount: int(total),
+	}
+	// Entities deleted since the search ran are skipped
+	for _, hit := range hits {
+		result := dto.SearchResultResponse{Type: hit.EntityType}
+		switch hit.EntityType {
+		case repositories.SearchTypeCrew:
+			crew, ok := crews[hit.EntityID]
+			if !ok {
+				continue
+			}
+			result.Crew = &crew
+		case repositories.SearchTypeFlag:
+			flag, ok := flags[hit.EntityID]
+			if !ok {
+				continue
+			}
+			result.Flag = &flag
+		case repositories.SearchTypeIsland:
+			island, ok := islands[hit.EntityID]
+			if !ok {
+				continue
+			}
+			result.Island = &island
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Helper functions
+
+// crewSearchResults loads the crews among the hits along with their rank and fame, keyed by crew ID
+func crewSearchResults(db *gorm.DB, hits []repositories.SearchHit) (map[uint]dto.CrewSearchResultResponse, error) {
+	hitsByID := searchHitsOfType(hits, repositories.SearchTypeCrew)
+	ids := make([]uint, 0, len(hitsByID))
+	for id := range hitsByID {
+		ids = append(ids, id)
+	}
+
+	repo := repositories.NewCrewRepository(db)
+	crews, err := repo.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	battleRecords, err := repo.GetLatestBattleRecords(ids)
+	if err != nil {
+		return nil, err
+	}
+	fameRecords, err := repo.GetLatestFameRecords(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[uint]dto.CrewSearchResultResponse, len(crews))
+	for i := range crews {
+		crew := &crews[i]
+		hit := hitsByID[crew.ID]
+		result := dto.CrewSearchResultResponse{
+			CrewBrief:   toCrewBrief(crew),
+			IsActive:    crew.IsActive,
+			MatchedName: matchedName(hit),
+			Score:       hit.Score,
+		}
+		if crew.Flag != nil {
+			result.FlagName = crew.Flag.Name
+		}
+		if record, ok := battleRecords[crew.ID]; ok {
+			result.CrewRank = string(record.CrewRank)
+		}
+		if record, ok := fameRecords[crew.ID]; ok {
+			result.FameLevel = string(record.FameLevel)
+		}
+		results[crew.ID] = result
+	}
+	return results, nil
+}
+
+// flagSearchResults loads the flags among the hits along with their crew count and fame, keyed by flag ID
+func flagSearchResults(db *gorm.DB, hits []repositories.SearchHit) (map[uint]dto.FlagSearchResultResponse, error) {
+	hitsByID := searchHitsOfType(hits, repositories.SearchTypeFlag)
+	ids := make([]uint, 0, len(hitsByID))
+	for id := range hitsByID {
+		ids = append(ids, id)
+	}
+
+	repo := repositories.NewFlagRepository(db)
+	flags, err := repo.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	crewCounts, err := repo.GetActiveCrewCounts(ids)
+	if err != nil {
+		return nil, err
+	}
+	fameRecords, err := repo.GetLatestFameRecords(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[uint]dto.FlagSearchResultResponse, len(flags))
+	for i := range flags {
+		flag := &flags[i]
+		hit := hitsByID[flag.ID]
+		result := dto.FlagSearchResultResponse{
+			FlagBrief:   toFlagBrief(flag),
+			CrewCount:   crewCounts[flag.ID],
+			IsActive:    flag.IsActive,
+			MatchedName: matchedName(hit),
+			Score:       hit.Score,
+		}
+		if record, ok := fameRecords[flag.ID]; ok {
+			result.FameLevel = string(record.FameLevel)
+		}
+		results[flag.ID] = result
+	}
+	return results, nil
+}
+
+// islandSearchResults loads the islands among the hits along with their population and
+// commodities, keyed by island ID
+func islandSearchResults(db *gorm.DB, hits []repositories.SearchHit) (map[uint]dto.IslandSearchResultResponse, error) {
+	hitsByID := searchHitsOfType(hits, repositories.SearchTypeIsland)
+	ids := make([]uint, 0, len(hitsByID))
+	for id := range hitsByID {
+		ids = append(ids, id)
+	}
+
+	repo := repositories.NewIslandRepository(db)
+	islands, err := repo.FindByIDsWithCommodities(ids)
+	if err != nil {
+		return nil, err
+	}
+	populations, err := repo.GetLatestPopulations(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[uint]dto.IslandSearchResultResponse, len(islands))
+	for i := range islands {
+		island := &islands[i]
+		hit := hitsByID[island.ID]
+		result := dto.IslandSearchResultResponse{
+			IslandBrief: toIslandBrief(island),
+			Size:        string(island.Size),
+			Population:  populations[island.ID],
+			MatchedName: matchedName(hit),
+			Score:       hit.Score,
+		}
+		if island.Archipelago != nil {
+			result.ArchipelagoName = island.Archipelago.Name
+		}
+		for _, commodity := range island.Commodities {
+			result.Commodities = append(result.Commodities, commodity.Commodity.Name)
+		}
+		results[island.ID] = result
+	}
+	return results, nil
+}
+
+func searchHitsOfType(hits []repositories.SearchHit, entityType string) map[uint]repositories.SearchHit {
+	hitsByID := make(map[uint]repositories.SearchHit)
+	for _, hit := range hits {
+		if hit.EntityType == entityType {
+			hitsByID[hit.EntityID] = hit
+		}
+	}
+	return hitsByID
+}
+
+// matchedName returns the name a hit matched if it was one of the entity's former names
+func matchedName(hit repositories.SearchHit) string {
+	if hit.Historical {
+		return hit.MatchedName
+	}
+	return ""
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"cutlass_analytics/internal/dto"
+	"cutlass_analytics/internal/models"
+	"cutlass_analytics/internal/repositories"
+	"cutlass_analytics/internal/types"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func GetTradeRoutesHandler(c *gin.Context, db *gorm.DB) {
+	var req dto.TradeRouteRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+	req.SetDefaults()
+
+	// Market orders are keyed by commodity name, so resolve the requested ID first
+	var commodityName string
+	if req.CommodityID != nil {
+		commodityRepo := repositories.NewCommodityRepository(db)
+		commodity, err := commodityRepo.FindByID(*req.CommodityID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				c.JSON(http.StatusNotFound, dto.APIResponse{
+					Success: false,
+					Error: &dto.APIError{
+						Code:    "NOT_FOUND",
+						Message: "Commodity not found",
+					},
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{
+				Success: false,
+				Error: &dto.APIError{
+					Code:    "DATABASE_ERROR",
+					Message: "Failed to fetch commodity",
+				},
+			})
+			return
+		}
+		commodityName = commodity.Name
+	}
+
+	repo := repositories.NewMarketOrderRepository(db)
+	routes, total, err := repo.FindTradeRoutes(req, commodityName)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch trade routes",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	// Look up island and commodity rows so responses carry IDs where we have them
+	islandNames := make([]string, 0, len(routes)*2)
+	commodityNames := make([]string, 0, len(routes))
+	for _, route := range routes {
+		islandNames = append(islandNames, route.BuyIslandName, route.SellIslandName)
+		commodityNames = append(commodityNames, route.CommodityName)
+	}
+
+	islandRepo := repositories.NewIslandRepository(db)
+	islands, err := islandRepo.FindByNames(types.Ocean(req.Ocean), islandNames)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch islands",
+			},
+		})
+		return
+	}
+	islandsByName := make(map[string]models.Island, len(islands))
+	for _, island := range islands {
+		islandsByName[island.Name] = island
+	}
+
+	commodityRepo := repositories.NewCommodityRepository(db)
+	commodities, err := commodityRepo.FindByNames(commodityNames)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch commodities",
+			},
+		})
+		return
+	}
+	commoditiesByName := make(map[string]models.Commodity, len(commodities))
+	for _, commodity := range commodities {
+		commoditiesByName[commodity.Name] = commodity
+	}
+
+	responses := make([]dto.TradeRouteResponse, len(routes))
+	var scrapedAt time.Time
+	for i, route := range routes {
+		responses[i] = dto.TradeRouteResponse{
+			Commodity:    toCommodityBriefByName(route.CommodityName, commoditiesByName),
+			BuyIsland:    toIslandBriefByName(route.BuyIslandName, req.Ocean, islandsByName),
+			SellIsland:   toIslandBriefByName(route.SellIslandName, req.Ocean, islandsByName),
+			BuyPrice:     route.BuyPrice,
+			SellPrice:    route.SellPrice,
+			Profit:       route.Profit,
+			BuyQuantity:  route.BuyQuantity,
+			SellQuantity: route.SellQuantity,
+			MaxQuantity:  route.MaxQuantity,
+			MaxProfit:    route.MaxProfit,
+			ScrapedAt:    route.ScrapedAt,
+		}
+		if route.ScrapedAt.After(scrapedAt) {
+			scrapedAt = route.ScrapedAt
+		}
+	}
+
+	c.JSON(http.StatusOK, dto.TradeRouteListResponse{
+		Ocean:       req.Ocean,
+		Routes:      responses,
+		ScrapedAt:   scrapedAt,
+		TotalRoutes: int(total),
+	})
+}
+
+// Helper functions
+
+// toIslandBriefByName builds an IslandBrief for a market order island name.
+// Islands that have not been scraped yet are returned with only their name filled in.
+func toIslandBriefByName(name string, ocean string, islandsByName map[string]models.Island) dto.IslandBrief {
+	if island, ok := islandsByName[name]; ok {
+		return toIslandBrief(&island)
+	}
+	return dto.IslandBrief{
+		Name:  name,
+		Ocean: ocean,
+	}
+}
+
+// toCommodityBriefByName builds a CommodityBrief for a market order commodity name.
+func toCommodityBriefByName(name string, commoditiesByName map[string]models.Commodity) dto.CommodityBrief {
+	if commodity, ok := commoditiesByName[name]; ok {
+		return toCommodityBrief(&commodity)
+	}
+	return dto.CommodityBrief{
+		Name:        name,
+		DisplayName: name,
+	}
+}
@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"cutlass_analytics/internal/dto"
+	"cutlass_analytics/internal/models"
+	"cutlass_analytics/internal/repositories"
+	"cutlass_analytics/internal/types"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// trendingPeriods maps each trending period to the length of its window
+var trendingPeriods = map[string]time.Duration{
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+}
+
+func GetTrendingCrewsHandler(c *gin.Context, db *gorm.DB) {
+	var req dto.TrendingCrewsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+	req.SetDefaults()
+
+	ocean := types.Ocean(req.Ocean)
+	endDate := time.Now()
+	startDate := endDate.Add(-trendingPeriods[req.Period])
+
+	repo := repositories.NewTrendingRepository(db)
+	scores, total, err := repo.GetTrendingCrews(ocean, startDate, req.Metric, req.Offset(), req.Limit())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch trending crews",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	crewIDs := make([]uint, len(scores))
+	for i, score := range scores {
+		crewIDs[i] = score.EntityID
+	}
+
+	snapshots, err := repo.GetCrewSnapshots(ocean, startDate, crewIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch crew snapshots",
+			},
+		})
+		return
+	}
+
+	crewRepo := repositories.NewCrewRepository(db)
+	crews, err := crewRepo.FindByIDs(crewIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch crews",
+			},
+		})
+		return
+	}
+	battleRecords, err := crewRepo.GetLatestBattleRecords(crewIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch battle records",
+			},
+		})
+		return
+	}
+
+	crewsByID := make(map[uint]*models.Crew, len(crews))
+	for i := range crews {
+		crewsByID[crews[i].ID] = &crews[i]
+	}
+	sparklines := trendSparklines(snapshots, req.Metric)
+
+	entries := make([]dto.TrendingCrewResponse, len(scores))
+	for i, score := range scores {
+		entry := dto.TrendingCrewResponse{
+			Rank:          score.Rank,
+			Crew:          dto.CrewBrief{ID: score.EntityID},
+			WinsGained:    score.WinsGained,
+			LossesGained:  score.LossesGained,
+			BattlesGained: score.WinsGained + score.LossesGained,
+			WinRate:       winRate(score.TotalPVPWins, score.TotalPVPLosses),
+			WinRateChange: score.WinRateChange,
+			Change:        score.Score,
+			Sparkline:     sparklines[score.EntityID],
+		}
+		if crew, ok := crewsByID[score.EntityID]; ok {
+			entry.Crew = toCrewBrief(crew)
+			if crew.Flag != nil {
+				entry.FlagName = crew.Flag.Name
+			}
+		}
+		if record, ok := battleRecords[score.EntityID]; ok {
+			entry.CrewRank = string(record.CrewRank)
+		}
+		entries[i] = entry
+	}
+
+	c.JSON(http.StatusOK, dto.TrendingCrewsResponse{
+		Ocean:      req.Ocean,
+		Period:     req.Period,
+		Metric:     req.Metric,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Crews:      entries,
+		Pagination: buildPagination(total, req.Page, req.PerPage),
+	})
+}
+
+func GetTrendingFlagsHandler(c *gin.Context, db *gorm.DB) {
+	var req dto.TrendingFlagsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "INVALID_REQUEST",
+				Message: "Invalid request parameters",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+	req.SetDefaults()
+
+	ocean := types.Ocean(req.Ocean)
+	endDate := time.Now()
+	startDate := endDate.Add(-trendingPeriods[req.Period])
+
+	repo := repositories.NewTrendingRepository(db)
+	scores, total, err := repo.GetTrendingFlags(ocean, startDate, req.Metric, req.Offset(), req.Limit())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch trending flags",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	flagIDs := make([]uint, len(scores))
+	for i, score := range scores {
+		flagIDs[i] = score.EntityID
+	}
+
+	snapshots, err := repo.GetFlagSnapshots(ocean, startDate, flagIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch flag snapshots",
+			},
+		})
+		return
+	}
+
+	flags, err := repositories.NewFlagRepository(db).FindByIDs(flagIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Error: &dto.APIError{
+				Code:    "DATABASE_ERROR",
+				Message: "Failed to fetch flags",
+			},
+		})
+		return
+	}
+
+	flagsByID := make(map[uint]*models.Flag, len(flags))
+	for i := range flags {
+		flagsByID[flags[i].ID] = &flags[i]
+	}
+	sparklines := trendSparklines(snapshots, req.Metric)
+
+	entries := make([]dto.TrendingFlagResponse, len(scores))
+	for i, score := range scores {
+		entry := dto.TrendingFlagResponse{
+			Rank:            score.Rank,
+			Flag:            dto.FlagBrief{ID: score.EntityID},
+			CrewCount:       score.CrewCount,
+			CrewCountChange: score.CrewCountChange,
+			WinsGained:      score.WinsGained,
+			LossesGained:    score.LossesGained,
+			BattlesGained:   score.WinsGained + score.LossesGained,
+			WinRate:         winRate(score.TotalPVPWins, score.TotalPVPLosses),
+			WinRateChange:   score.WinRateChange,
+			Change:          score.Score,
+			Sparkline:       sparklines[score.EntityID],
+		}
+		if flag, ok := flagsByID[score.EntityID]; ok {
+			entry.Flag = toFlagBrief(flag)
+		}
+		entries[i] = entry
+	}
+
+	c.JSON(http.StatusOK, dto.TrendingFlagsResponse{
+		Ocean:      req.Ocean,
+		Period:     req.Period,
+		Metric:     req.Metric,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Flags:      entries,
+		Pagination: buildPagination(total, req.Page, req.PerPage),
+	})
+}
+
+// Helper functions
+
+// trendSparklines groups snapshots by entity into one point per scrape. For wins and battles
+// each point is the amount gained since the previous scrape; for win_rate and crew_count it
+// is the value at that scrape.
+func trendSparklines(snapshots []repositories.TrendSnapshot, metric string) map[uint][]dto.TrendPoint {
+	sparklines := make(map[uint][]dto.TrendPoint)
+	for _, snapshot := range snapshots {
+		var value float64
+		switch metric {
+		case "battles":
+			value = float64(snapshot.WinsGained + snapshot.LossesGained)
+		case "win_rate":
+			value = snapshot.WinRate()
+		case "crew_count":
+			value = float64(snapshot.CrewCount)
+		default:
+			value = float64(snapshot.WinsGained)
+		}
+		sparklines[snapshot.EntityID] = append(sparklines[snapshot.EntityID], dto.TrendPoint{
+			Date:  snapshot.ScrapedAt,
+			Value: value,
+		})
+	}
+	return sparklines
+}
+
+func winRate(wins, losses int) float64 {
+	total := wins + losses
+	if total == 0 {
+		return 0
+	}
+	return float64(wins) / float64(total) * 100
+}
@@ -7,20 +7,23 @@ import (
 	"cutlass_analytics/docs"
 	"cutlass_analytics/internal/api/handlers"
 	"cutlass_analytics/internal/dto"
+	"cutlass_analytics/internal/jobs"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-func NewRouter(db *gorm.DB) *gin.Engine {
+// NewRouter creates the API router. The endpoints that start and cancel scrape jobs and edit
+// scrape schedules act on scheduler and require apiKey as a bearer token.
+func NewRouter(db *gorm.DB, scheduler *jobs.Scheduler, apiKey string) *gin.Engine {
     r := gin.Default()
 
     // CORS
     r.Use(cors.New(cors.Config{
         AllowOrigins:     []string{"*"},
-        AllowMethods:     []string{"GET", "OPTIONS"},
-        AllowHeaders:     []string{"Origin", "Content-Type"},
+        AllowMethods:     []string{"GET", "POST", "PUT", "OPTIONS"},
+        AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
     }))
 
     r.GET("/api/health", func(c *gin.Context) {
@@ -33,39 +36,106 @@ func NewRouter(db *gorm.DB) *gin.Engine {
 
     // API routes
     api := r.Group("/api")
+    auth := requireAPIKey(apiKey)
     {
+        // Search
+        api.GET("/search", func(c *gin.Context) { handlers.SearchHandler(c, db) })
+
+        // Oceans
+        api.GET("/oceans", func(c *gin.Context) { handlers.ListOceansHandler(c, db) })
+        api.GET("/oceans/compare", func(c *gin.Context) { handlers.CompareOceansHandler(c, db) })
+        api.GET("/oceans/:ocean/stats", func(c *gin.Context) { handlers.GetOceanStatsHandler(c, db) })
+        api.GET("/oceans/:ocean/population", func(c *gin.Context) { handlers.GetOceanPopulationHandler(c, db) })
+        api.GET("/oceans/:ocean/territory", func(c *gin.Context) { handlers.GetOceanTerritoryHandler(c, db) })
+
         // Islands
         api.GET("/islands", func(c *gin.Context) { handlers.ListIslandsHandler(c, db) })
+        api.GET("/islands/search", func(c *gin.Context) { handlers.SearchIslandsHandler(c, db) })
         api.GET("/islands/:id", func(c *gin.Context) { handlers.GetIslandHandler(c, db) })
         api.GET("/islands/game/:game_island_id", func(c *gin.Context) { handlers.GetIslandByGameIDHandler(c, db) })
         api.GET("/islands/:id/population", func(c *gin.Context) { handlers.GetIslandPopulationHandler(c, db) })
         api.GET("/islands/:id/governance", func(c *gin.Context) { handlers.GetIslandGovernanceHandler(c, db) })
         api.GET("/islands/:id/commodities", func(c *gin.Context) { handlers.GetIslandCommoditiesHandler(c, db) })
+        api.GET("/islands/:id/market", func(c *gin.Context) { handlers.GetIslandMarketHandler(c, db) })
+
+        // Archipelagos
+        api.GET("/archipelagos", func(c *gin.Context) { handlers.ListArchipelagosHandler(c, db) })
+        api.GET("/archipelagos/:id", func(c *gin.Context) { handlers.GetArchipelagoHandler(c, db) })
 
         // Crews
         api.GET("/crews", func(c *gin.Context) { handlers.ListCrewsHandler(c, db) })
+        api.GET("/crews/search", func(c *gin.Context) { handlers.SearchCrewsHandler(c, db) })
         api.GET("/crews/:id", func(c *gin.Context) { handlers.GetCrewHandler(c, db) })
         api.GET("/crews/game/:game_crew_id", func(c *gin.Context) { handlers.GetCrewByGameIDHandler(c, db) })
         api.GET("/crews/:id/battles", func(c *gin.Context) { handlers.GetCrewBattlesHandler(c, db) })
         api.GET("/crews/:id/fame", func(c *gin.Context) { handlers.GetCrewFameHandler(c, db) })
         api.GET("/crews/:id/stats", func(c *gin.Context) { handlers.GetCrewStatsHandler(c, db) })
+        api.GET("/crews/:id/flag-history", func(c *gin.Context) { handlers.GetCrewFlagHistoryHandler(c, db) })
+        api.GET("/crews/:id/rank-changes", func(c *gin.Context) { handlers.GetCrewRankChangesHandler(c, db) })
+        api.GET("/crews/:id/names", func(c *gin.Context) { handlers.GetCrewNamesHandler(c, db) })
+        api.GET("/crews/:id/events", func(c *gin.Context) { handlers.GetCrewEventsHandler(c, db) })
 
         // Flags
         api.GET("/flags", func(c *gin.Context) { handlers.ListFlagsHandler(c, db) })
+        api.GET("/flags/search", func(c *gin.Context) { handlers.SearchFlagsHandler(c, db) })
         api.GET("/flags/:id", func(c *gin.Context) { handlers.GetFlagHandler(c, db) })
         api.GET("/flags/game/:game_flag_id", func(c *gin.Context) { handlers.GetFlagByGameIDHandler(c, db) })
         api.GET("/flags/:id/crews", func(c *gin.Context) { handlers.GetFlagCrewsHandler(c, db) })
         api.GET("/flags/:id/fame", func(c *gin.Context) { handlers.GetFlagFameHandler(c, db) })
+        api.GET("/flags/:id/stats", func(c *gin.Context) { handlers.GetFlagStatsHandler(c, db) })
+        api.GET("/flags/:id/history", func(c *gin.Context) { handlers.GetFlagHistoryHandler(c, db) })
+        api.GET("/flags/:id/membership", func(c *gin.Context) { handlers.GetFlagMembershipHandler(c, db) })
+        api.GET("/flags/:id/territory", func(c *gin.Context) { handlers.GetFlagTerritoryHandler(c, db) })
+        api.GET("/flags/:id/names", func(c *gin.Context) { handlers.GetFlagNamesHandler(c, db) })
+        api.GET("/flags/:id/events", func(c *gin.Context) { handlers.GetFlagEventsHandler(c, db) })
+        api.GET("/flags/:id/reputation", func(c *gin.Context) { handlers.GetFlagReputationHandler(c, db) })
+
+        // Compare
+        api.GET("/compare/crews", func(c *gin.Context) { handlers.CompareCrewsHandler(c, db) })
+        api.GET("/compare/flags", func(c *gin.Context) { handlers.CompareFlagsHandler(c, db) })
+
+        // Trending
+        api.GET("/trending/crews", func(c *gin.Context) { handlers.GetTrendingCrewsHandler(c, db) })
+        api.GET("/trending/flags", func(c *gin.Context) { handlers.GetTrendingFlagsHandler(c, db) })
+
+        // Activity
+        api.GET("/activity/daily", func(c *gin.Context) { handlers.GetDailyActivityHandler(c, db) })
+        api.GET("/activity/daily/changes", func(c *gin.Context) { handlers.GetDailyChangesHandler(c, db) })
+        api.GET("/activity/summary", func(c *gin.Context) { handlers.GetActivitySummaryHandler(c, db) })
+        api.GET("/activity/rank-changes", func(c *gin.Context) { handlers.GetRankChangesHandler(c, db) })
+
+        // Leaderboards
+        api.GET("/leaderboards/crews", func(c *gin.Context) { handlers.GetCrewLeaderboardHandler(c, db) })
+        api.GET("/leaderboards/crews/daily", func(c *gin.Context) { handlers.GetDailyCrewLeaderboardHandler(c, db) })
+        api.GET("/leaderboards/fame", func(c *gin.Context) { handlers.GetFameLeaderboardHandler(c, db) })
+        api.GET("/leaderboards/reputation", func(c *gin.Context) { handlers.GetReputationLeaderboardHandler(c, db) })
+        api.GET("/leaderboards/summary", func(c *gin.Context) { handlers.GetLeaderboardSummaryHandler(c, db) })
 
         // Scrape Jobs
         api.GET("/scrape-jobs", func(c *gin.Context) { handlers.ListScrapeJobsHandler(c, db) })
         api.GET("/scrape-jobs/:id", func(c *gin.Context) { handlers.GetScrapeJobHandler(c, db) })
         api.GET("/scrape-jobs/status", func(c *gin.Context) { handlers.GetScrapeStatusHandler(c, db) })
+        api.POST("/scrape-jobs", auth, func(c *gin.Context) { handlers.TriggerScrapeJobHandler(c, scheduler) })
+        api.POST("/scrape-jobs/:id/cancel", auth, func(c *gin.Context) { handlers.CancelScrapeJobHandler(c, db, scheduler) })
+
+        // Scrape Schedules
+        api.GET("/scrape-schedules", func(c *gin.Context) { handlers.ListScrapeSchedulesHandler(c, db, scheduler) })
+        api.PUT("/scrape-schedules", auth, func(c *gin.Context) { handlers.UpdateScrapeScheduleHandler(c, db, scheduler) })
 
         // Tax Rates
         api.GET("/tax-rates", func(c *gin.Context) { handlers.GetTaxRatesHandler(c, db) })
         api.GET("/tax-rates/:commodity_id/history", func(c *gin.Context) { handlers.GetTaxRateHistoryHandler(c, db) })
         api.GET("/tax-rates/compare", func(c *gin.Context) { handlers.CompareTaxRatesHandler(c, db) })
+
+        // Commodities
+        api.GET("/commodities", func(c *gin.Context) { handlers.ListCommoditiesHandler(c, db) })
+        api.GET("/commodities/search", func(c *gin.Context) { handlers.SearchCommoditiesHandler(c, db) })
+        api.GET("/commodities/:id", func(c *gin.Context) { handlers.GetCommodityHandler(c, db) })
+        api.GET("/commodities/:id/market", func(c *gin.Context) { handlers.GetCommodityMarketHandler(c, db) })
+
+        // Market
+        api.GET("/trade-routes", func(c *gin.Context) { handlers.GetTradeRoutesHandler(c, db) })
+        api.GET("/market/prices", func(c *gin.Context) { handlers.GetMarketPricesHandler(c, db) })
     }
 
     return r
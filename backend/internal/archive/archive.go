@@ -0,0 +1,71 @@
+package archive
+
+import (
+	"cutlass_analytics/internal/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Retention is how long archived pages are kept before being deleted
+const Retention = 30 * 24 * time.Hour
+
+// ErrNotArchived is returned by Load when a scrape job has no archived copy of a page
+var ErrNotArchived = errors.New("page not archived")
+
+// Store compresses and archives a page body fetched at fetchedAt. jobID is the scrape job that
+// fetched the page, nil for market CSVs fetched by the CSV poller.
+func Store(db *gorm.DB, jobID *uint, url string, body []byte, fetchedAt time.Time) (*models.ArchivedPage, error) {
+	page, err := models.NewArchivedPage(jobID, url, body, fetchedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Create(page).Error; err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// Load returns the page the scrape job jobID archived for url. A job that fetched the same page
+// more than once gets its first copy.
+func Load(db *gorm.DB, jobID uint, url string) (*models.ArchivedPage, error) {
+	var page models.ArchivedPage
+	err := db.Where("scrape_job_id = ? AND url = ?", jobID, url).
+		Order("fetched_at ASC, id ASC").
+		First(&page).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, ErrNotArchived
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// LoadSince returns the copies of url archived at or after since, oldest first
+func LoadSince(db *gorm.DB, url string, since time.Time) ([]models.ArchivedPage, error) {
+	var pages []models.ArchivedPage
+	err := db.Where("url = ? AND fetched_at >= ?", url, since).
+		Order("fetched_at ASC, id ASC").
+		Find(&pages).Error
+	if err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+// HasPages reports whether the scrape job jobID has any archived pages left
+func HasPages(db *gorm.DB, jobID uint) (bool, error) {
+	var count int64
+	if err := db.Model(&models.ArchivedPage{}).Where("scrape_job_id = ?", jobID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Prune permanently deletes the pages fetched before cutoff and returns how many were deleted
+func Prune(db *gorm.DB, cutoff time.Time) (int64, error) {
+	result := db.Unscoped().Where("fetched_at < ?", cutoff).Delete(&models.ArchivedPage{})
+	return result.RowsAffected, result.Error
+}
@@ -12,6 +12,13 @@ type Config struct {
 	DBPassword  string
 	DBName      string
 	BackendPort string
+
+	// FetchMode is live, record or replay; record and replay keep pages under FetchDir
+	FetchMode string
+	FetchDir  string
+
+	// AdminAPIKey guards the endpoints that control scrape jobs and schedules; they are disabled when empty
+	AdminAPIKey string
 }
 
 func Load() *Config {
@@ -22,6 +29,9 @@ func Load() *Config {
 		DBPassword:  getEnv("DB_PASSWORD", "postgres"),
 		DBName:      getEnv("DB_NAME", "cutlass_analytics"),
 		BackendPort: getEnv("BACKEND_PORT", "8080"),
+		FetchMode:   getEnv("FETCH_MODE", "live"),
+		FetchDir:    getEnv("FETCH_DIR", ""),
+		AdminAPIKey: getEnv("ADMIN_API_KEY", ""),
 	}
 }
 
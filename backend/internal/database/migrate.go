@@ -2,6 +2,8 @@ package database
 
 import (
 	"cutlass_analytics/internal/models"
+	"cutlass_analytics/internal/types"
+	"fmt"
 	"log"
 
 	"gorm.io/gorm"
@@ -17,14 +19,23 @@ func AutoMigrate(db *gorm.DB) error {
 		&models.CrewFameRecord{},
 		&models.CrewReputationRecord{},
 		&models.FlagFameRecord{},
+		&models.FlagReputationRecord{},
 		&models.CrewFlagHistory{},
+		&models.CrewNameHistory{},
+		&models.FlagNameHistory{},
+		&models.CrewLifecycleEvent{},
+		&models.FlagLifecycleEvent{},
 		&models.ScrapeJob{},
+		&models.ScrapeJobCheckpoint{},
+		&models.ScrapeSchedule{},
+		&models.ArchivedPage{},
 		&models.Island{},
 		&models.Archipelago{},
 		&models.Commodity{},
 		&models.CommodityTaxRate{},
 		&models.MarketPrice{},
 		&models.MarketOrder{},
+		&models.MarketSnapshot{},
 		&models.IslandGovernanceHistory{},
 		&models.IslandPopulation{},
 		&models.IslandCommodity{},
@@ -33,11 +44,64 @@ func AutoMigrate(db *gorm.DB) error {
     if err != nil {
         return err
     }
+
+	if err := BackfillCommodityCategories(db); err != nil {
+		return err
+	}
+
+	if err := CreateSearchIndexes(db); err != nil {
+		return err
+	}
 	
 	log.Println("Migrations completed successfully")
     return nil
 }
 
+// BackfillCommodityCategories reclassifies commodities that were created with the
+// default basic category before the classification table existed
+func BackfillCommodityCategories(db *gorm.DB) error {
+	var commodities []models.Commodity
+	if err := db.Where("category = ?", types.CommodityCategoryBasic).Find(&commodities).Error; err != nil {
+		return err
+	}
+
+	updated := 0
+	for _, commodity := range commodities {
+		category := types.ClassifyCommodity(commodity.Name)
+		if category == commodity.Category {
+			continue
+		}
+		if err := db.Model(&commodity).Update("category", category).Error; err != nil {
+			return err
+		}
+		updated++
+	}
+
+	if updated > 0 {
+		log.Printf("Reclassified %d commodities", updated)
+	}
+	return nil
+}
+
+// CreateSearchIndexes enables pg_trgm and indexes the lowercased current and former names that
+// search matches against, so fuzzy and substring matches don't scan the tables
+func CreateSearchIndexes(db *gorm.DB) error {
+	if err := db.Exec(`CREATE EXTENSION IF NOT EXISTS pg_trgm`).Error; err != nil {
+		return err
+	}
+
+	for _, table := range []string{"crews", "flags", "islands", "crew_name_history", "flag_name_history"} {
+		if err := db.Exec(fmt.Sprintf(`
+			CREATE INDEX IF NOT EXISTS idx_%[1]s_name_trgm 
+			ON %[1]s USING gin (lower(name) gin_trgm_ops)
+		`, table)).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func CreateIndexes(db *gorm.DB) error {
 	// Index for finding latest battle record per crew
 	if err := db.Exec(`
@@ -103,13 +167,29 @@ func CreateIndexes(db *gorm.DB) error {
 		return err
 	}
 
+	// Index for the current market order book per ocean
+	if err := db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_market_orders_current 
+		ON market_orders (ocean, island_name, commodity_name) WHERE retired_at IS NULL
+	`).Error; err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func DropAllTables(db *gorm.DB) error {
 	return db.Migrator().DropTable(
+		&models.ArchivedPage{},
+		&models.ScrapeSchedule{},
+		&models.ScrapeJobCheckpoint{},
 		&models.ScrapeJob{},
 		&models.CrewFlagHistory{},
+		&models.CrewNameHistory{},
+		&models.FlagNameHistory{},
+		&models.CrewLifecycleEvent{},
+		&models.FlagLifecycleEvent{},
+		&models.FlagReputationRecord{},
 		&models.FlagFameRecord{},
 		&models.CrewReputationRecord{},
 		&models.CrewFameRecord{},
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type DailyActivityRequest struct {
 	OceanParam
 	
@@ -8,6 +10,11 @@ type DailyActivityRequest struct {
 	IncludeChanges bool `form:"include_changes" binding:"omitempty"`
 }
 
+// ParsedDate returns the requested UTC day, today if none was given
+func (r *DailyActivityRequest) ParsedDate() (time.Time, error) {
+	return parseActivityDate(r.Date)
+}
+
 type DailyChangesRequest struct {
 	OceanParam
 	PaginationParams
@@ -22,6 +29,11 @@ func (r *DailyChangesRequest) SetDefaults() {
 	r.PaginationParams.SetDefaults()
 }
 
+// ParsedDate returns the requested UTC day, today if none was given
+func (r *DailyChangesRequest) ParsedDate() (time.Time, error) {
+	return parseActivityDate(r.Date)
+}
+
 type OceanActivityRequest struct {
 	OceanParam
 	DateRangeParams
@@ -52,8 +64,25 @@ func (r *RankChangesRequest) SetDefaults() {
 	r.PaginationParams.SetDefaults()
 }
 
+func (r *RankChangesRequest) Validate() error {
+	if r.PromotionsOnly && r.DemotionsOnly {
+		return ErrConflictingRankFilters
+	}
+	return r.DateRangeParams.Validate()
+}
+
 type CrewRankChangesRequest struct {
 	DateRangeParams
+	
+	PromotionsOnly bool `form:"promotions_only" binding:"omitempty"`
+	DemotionsOnly  bool `form:"demotions_only" binding:"omitempty"`
+}
+
+func (r *CrewRankChangesRequest) Validate() error {
+	if r.PromotionsOnly && r.DemotionsOnly {
+		return ErrConflictingRankFilters
+	}
+	return r.DateRangeParams.Validate()
 }
 
 type TrendingCrewsRequest struct {
@@ -93,3 +122,10 @@ func (r *TrendingFlagsRequest) SetDefaults() {
 		r.Metric = "wins"
 	}
 }
+
+func parseActivityDate(date string) (time.Time, error) {
+	if date == "" {
+		return time.Now().UTC().Truncate(24 * time.Hour), nil
+	}
+	return time.Parse("2006-01-02", date)
+}
@@ -51,6 +51,8 @@ type MarketPricesRequest struct {
 	HasBuyOffer  *bool `form:"has_buy_offer" binding:"omitempty"`
 	HasSellOffer *bool `form:"has_sell_offer" binding:"omitempty"`
 	
+	Category string `form:"category" binding:"omitempty,oneof=basic herb mineral foraged refined ship_supply"`
+	
 	SortBy string `form:"sort_by" binding:"omitempty,oneof=buy_price sell_price spread commodity island"`
 }
 
@@ -62,6 +64,9 @@ func (r *MarketPricesRequest) SetDefaults() {
 }
 
 type IslandMarketPricesRequest struct {
+	HasBuyOffer  *bool `form:"has_buy_offer" binding:"omitempty"`
+	HasSellOffer *bool `form:"has_sell_offer" binding:"omitempty"`
+	
 	Category string `form:"category" binding:"omitempty,oneof=basic herb mineral foraged refined ship_supply"`
 }
 
@@ -206,6 +211,13 @@ type MarketPriceResponse struct {
 	Spread *int `json:"spread,omitempty"`
 }
 
+type MarketPriceListResponse struct {
+	Ocean      string                `json:"ocean"`
+	ScrapedAt  time.Time             `json:"scraped_at"`
+	Prices     []MarketPriceResponse `json:"prices"`
+	Pagination Pagination            `json:"pagination"`
+}
+
 type IslandMarketPricesResponse struct {
 	Island    IslandBrief           `json:"island"`
 	ScrapedAt time.Time             `json:"scraped_at"`
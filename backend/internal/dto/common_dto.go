@@ -138,7 +138,8 @@ type OceanPathParam struct {
 }
 
 var (
-	ErrStartDateAfterEndDate = &ValidationError{Field: "start_date", Message: "start_date must be before end_date"}
+	ErrStartDateAfterEndDate  = &ValidationError{Field: "start_date", Message: "start_date must be before end_date"}
+	ErrConflictingRankFilters = &ValidationError{Field: "demotions_only", Message: "promotions_only and demotions_only cannot both be set"}
 )
 
 type ValidationError struct {
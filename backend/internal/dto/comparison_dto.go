@@ -1,6 +1,28 @@
 package dto
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
+
+// MaxComparisonDays is the longest date range a comparison covers, since its series have a point
+// per day for every compared crew or flag
+const MaxComparisonDays = 365
+
+var ErrComparisonRangeTooLong = &ValidationError{Field: "start_date", Message: fmt.Sprintf("the date range can't be longer than %d days", MaxComparisonDays)}
+
+// validateComparisonRange validates the date range of a comparison and caps its length
+func validateComparisonRange(d DateRangeParams) error {
+	if err := d.Validate(); err != nil {
+		return err
+	}
+	start, _ := d.ParsedStartDate()
+	end, _ := d.ParsedEndDate()
+	if end.Sub(start) > MaxComparisonDays*24*time.Hour {
+		return ErrComparisonRangeTooLong
+	}
+	return nil
+}
 
 type CrewComparisonResponse struct {
 	Crew1 CrewComparisonData `json:"crew1"`
@@ -20,6 +42,7 @@ type CrewComparisonData struct {
 	FlagName       string    `json:"flag_name,omitempty"`
 	CrewRank       string    `json:"crew_rank"`
 	FameLevel      string    `json:"fame_level"`
+	FameRank       *int      `json:"fame_rank,omitempty"`
 	TotalPVPWins   int       `json:"total_pvp_wins"`
 	TotalPVPLosses int       `json:"total_pvp_losses"`
 	WinRate        float64   `json:"win_rate"`
@@ -41,6 +64,7 @@ type FlagComparisonResponse struct {
 type FlagComparisonData struct {
 	Flag           FlagBrief `json:"flag"`
 	FameLevel      string    `json:"fame_level"`
+	FameRank       *int      `json:"fame_rank,omitempty"`
 	CrewCount      int       `json:"crew_count"`
 	TotalPVPWins   int       `json:"total_pvp_wins"`
 	TotalPVPLosses int       `json:"total_pvp_losses"`
@@ -52,12 +76,44 @@ type MultiCrewComparisonResponse struct {
 	Crews      []CrewComparisonData `json:"crews"`
 	SortedBy   string               `json:"sorted_by"`
 	ComparedAt time.Time            `json:"compared_at"`
+	
+	StartDate time.Time              `json:"start_date"`
+	EndDate   time.Time              `json:"end_date"`
+	Dates     []time.Time            `json:"dates"`
+	Series    []CrewComparisonSeries `json:"series"`
+}
+
+// CrewComparisonSeries holds a crew's end-of-day values, aligned with the response dates.
+// Days without a scrape are null.
+type CrewComparisonSeries struct {
+	CrewID   uint       `json:"crew_id"`
+	Wins     []*int     `json:"wins"`
+	Losses   []*int     `json:"losses"`
+	WinRate  []*float64 `json:"win_rate"`
+	FameRank []*int     `json:"fame_rank"`
+	CrewRank []*string  `json:"crew_rank"`
 }
 
 type MultiFlagComparisonResponse struct {
 	Flags      []FlagComparisonData `json:"flags"`
 	SortedBy   string               `json:"sorted_by"`
 	ComparedAt time.Time            `json:"compared_at"`
+	
+	StartDate time.Time              `json:"start_date"`
+	EndDate   time.Time              `json:"end_date"`
+	Dates     []time.Time            `json:"dates"`
+	Series    []FlagComparisonSeries `json:"series"`
+}
+
+// FlagComparisonSeries holds a flag's end-of-day values, aligned with the response dates.
+// Days without a scrape are null.
+type FlagComparisonSeries struct {
+	FlagID    uint       `json:"flag_id"`
+	Wins      []*int     `json:"wins"`
+	Losses    []*int     `json:"losses"`
+	WinRate   []*float64 `json:"win_rate"`
+	FameRank  []*int     `json:"fame_rank"`
+	CrewCount []*int     `json:"crew_count"`
 }
 
 type CrewTrendResponse struct {
@@ -81,6 +137,60 @@ type TrendPoint struct {
 	Value float64   `json:"value"`
 }
 
+type TrendingCrewResponse struct {
+	Rank     int       `json:"rank"`
+	Crew     CrewBrief `json:"crew"`
+	FlagName string    `json:"flag_name,omitempty"`
+	CrewRank string    `json:"crew_rank"`
+	
+	WinsGained    int      `json:"wins_gained"`
+	LossesGained  int      `json:"losses_gained"`
+	BattlesGained int      `json:"battles_gained"`
+	WinRate       float64  `json:"win_rate"`
+	WinRateChange *float64 `json:"win_rate_change,omitempty"`
+	
+	// Change is the rise in the requested metric over the period
+	Change    float64      `json:"change"`
+	Sparkline []TrendPoint `json:"sparkline"`
+}
+
+type TrendingCrewsResponse struct {
+	Ocean      string                 `json:"ocean"`
+	Period     string                 `json:"period"`
+	Metric     string                 `json:"metric"`
+	StartDate  time.Time              `json:"start_date"`
+	EndDate    time.Time              `json:"end_date"`
+	Crews      []TrendingCrewResponse `json:"crews"`
+	Pagination Pagination             `json:"pagination"`
+}
+
+type TrendingFlagResponse struct {
+	Rank int       `json:"rank"`
+	Flag FlagBrief `json:"flag"`
+	
+	CrewCount       int      `json:"crew_count"`
+	CrewCountChange int      `json:"crew_count_change"`
+	WinsGained      int      `json:"wins_gained"`
+	LossesGained    int      `json:"losses_gained"`
+	BattlesGained   int      `json:"battles_gained"`
+	WinRate         float64  `json:"win_rate"`
+	WinRateChange   *float64 `json:"win_rate_change,omitempty"`
+	
+	// Change is the rise in the requested metric over the period
+	Change    float64      `json:"change"`
+	Sparkline []TrendPoint `json:"sparkline"`
+}
+
+type TrendingFlagsResponse struct {
+	Ocean      string                 `json:"ocean"`
+	Period     string                 `json:"period"`
+	Metric     string                 `json:"metric"`
+	StartDate  time.Time              `json:"start_date"`
+	EndDate    time.Time              `json:"end_date"`
+	Flags      []TrendingFlagResponse `json:"flags"`
+	Pagination Pagination             `json:"pagination"`
+}
+
 type FlagTrendResponse struct {
 	Flag       FlagBrief       `json:"flag"`
 	Period     string          `json:"period"`
@@ -110,6 +220,15 @@ type OceanActivityResponse struct {
 	AvgDailyBattles float64 `json:"avg_daily_battles"`
 	MostActiveDay  string  `json:"most_active_day"`
 	LeastActiveDay string  `json:"least_active_day"`
+	
+	TotalLosses       int `json:"total_losses"`
+	NewCrews          int `json:"new_crews"`
+	VanishedCrews     int `json:"vanished_crews"`
+	NewFlags          int `json:"new_flags"`
+	VanishedFlags     int `json:"vanished_flags"`
+	GovernanceChanges int `json:"governance_changes"`
+	
+	MostActiveCrews []DailyPVPChangeResponse `json:"most_active_crews"`
 }
 
 type DailyActivitySummary struct {
@@ -118,6 +237,12 @@ type DailyActivitySummary struct {
 	TotalWins    int       `json:"total_wins"`
 	TotalLosses  int       `json:"total_losses"`
 	ActiveCrews  int       `json:"active_crews"`
+	
+	NewCrews          int `json:"new_crews"`
+	VanishedCrews     int `json:"vanished_crews"`
+	NewFlags          int `json:"new_flags"`
+	VanishedFlags     int `json:"vanished_flags"`
+	GovernanceChanges int `json:"governance_changes"`
 }
 
 type RankChangeResponse struct {
@@ -138,6 +263,10 @@ type RecentRankChangesResponse struct {
 	Ocean   string                    `json:"ocean"`
 	Period  string                    `json:"period"`
 	Changes []CrewRankChangeResponse  `json:"changes"`
+	
+	StartDate  time.Time  `json:"start_date"`
+	EndDate    time.Time  `json:"end_date"`
+	Pagination Pagination `json:"pagination"`
 }
 
 type CrewRankChangeResponse struct {
@@ -39,6 +39,10 @@ func (r *CrewSearchRequest) SetDefaults() {
 	r.PaginationParams.SetDefaults()
 }
 
+func (r *CrewSearchRequest) Validate() error {
+	return validateSearchQuery(r.Query)
+}
+
 type CrewStatsRequest struct {
 	OceanParam
 	PaginationParams
@@ -86,7 +90,9 @@ type CrewCompareRequest struct {
 }
 
 type MultiCrewCompareRequest struct {
-	CrewIDs []uint `form:"crew_ids" binding:"required,min=2,max=10,dive,min=1"`
+	DateRangeParams
+	
+	CrewIDs []uint `form:"ids" collection_format:"csv" binding:"required,min=2,max=10,unique,dive,min=1"`
 	SortBy  string `form:"sort_by" binding:"omitempty,oneof=wins win_rate battles rank"`
 }
 
@@ -96,6 +102,10 @@ func (r *MultiCrewCompareRequest) SetDefaults() {
 	}
 }
 
+func (r *MultiCrewCompareRequest) Validate() error {
+	return validateComparisonRange(r.DateRangeParams)
+}
+
 type CrewTrendRequest struct {
 	Period string `form:"period" binding:"omitempty,oneof=7d 30d 90d all"`
 }
@@ -260,7 +270,52 @@ type DailyActivityResponse struct {
 	TotalWins   int                      `json:"total_wins"`
 	TotalLosses int                      `json:"total_losses"`
 	ActiveCrews int                      `json:"active_crews"`
-	Changes     []DailyPVPChangeResponse `json:"changes"`
+	Changes     []DailyPVPChangeResponse `json:"changes,omitempty"`
+	
+	TotalBattles    int                      `json:"total_battles"`
+	MostActiveCrews []DailyPVPChangeResponse `json:"most_active_crews"`
+	
+	NewCrews          []CrewBrief                      `json:"new_crews"`
+	VanishedCrews     []CrewBrief                      `json:"vanished_crews"`
+	NewFlags          []FlagBrief                      `json:"new_flags"`
+	VanishedFlags     []FlagBrief                      `json:"vanished_flags"`
+	GovernanceChanges []IslandGovernanceChangeResponse `json:"governance_changes"`
+}
+
+type DailyChangesResponse struct {
+	Ocean      string                   `json:"ocean"`
+	Date       time.Time                `json:"date"`
+	Changes    []DailyPVPChangeResponse `json:"changes"`
+	Pagination Pagination               `json:"pagination"`
+}
+
+type NameHistoryEntryResponse struct {
+	Name      string     `json:"name"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	Days      int        `json:"days"`
+	IsCurrent bool       `json:"is_current"`
+}
+
+type CrewNameHistoryResponse struct {
+	Crew        CrewBrief                  `json:"crew"`
+	RenameCount int                        `json:"rename_count"`
+	History     []NameHistoryEntryResponse `json:"history"`
+}
+
+type LifecycleEventResponse struct {
+	Type          string    `json:"type"`
+	OccurredAt    time.Time `json:"occurred_at"`
+	MissedScrapes int       `json:"missed_scrapes"`
+}
+
+type CrewEventsResponse struct {
+	Crew          CrewBrief                `json:"crew"`
+	IsActive      bool                     `json:"is_active"`
+	MissedScrapes int                      `json:"missed_scrapes"`
+	FirstSeenAt   time.Time                `json:"first_seen_at"`
+	LastSeenAt    time.Time                `json:"last_seen_at"`
+	Events        []LifecycleEventResponse `json:"events"`
 }
 
 type CrewSearchResultResponse struct {
@@ -269,6 +324,10 @@ type CrewSearchResultResponse struct {
 	CrewRank  string `json:"crew_rank"`
 	FameLevel string `json:"fame_level"`
 	IsActive  bool   `json:"is_active"`
+	
+	// MatchedName is the former name the query matched, if it didn't match the current one
+	MatchedName string  `json:"matched_name,omitempty"`
+	Score       float64 `json:"score"`
 }
 
 type CrewSearchResponse struct {
@@ -38,6 +38,10 @@ func (r *FlagSearchRequest) SetDefaults() {
 	r.PaginationParams.SetDefaults()
 }
 
+func (r *FlagSearchRequest) Validate() error {
+	return validateSearchQuery(r.Query)
+}
+
 type FlagStatsRequest struct {
 	OceanParam
 	PaginationParams
@@ -83,14 +87,20 @@ type FlagMembershipHistoryRequest struct {
 	DateRangeParams
 	PaginationParams
 	
-	IncludeJoins  bool `form:"include_joins" binding:"omitempty"`
-	IncludeLeaves bool `form:"include_leaves" binding:"omitempty"`
+	IncludeJoins  *bool `form:"include_joins" binding:"omitempty"`
+	IncludeLeaves *bool `form:"include_leaves" binding:"omitempty"`
 }
 
 func (r *FlagMembershipHistoryRequest) SetDefaults() {
 	r.PaginationParams.SetDefaults()
-	r.IncludeJoins = true
-	r.IncludeLeaves = true
+	if r.IncludeJoins == nil {
+		includeJoins := true
+		r.IncludeJoins = &includeJoins
+	}
+	if r.IncludeLeaves == nil {
+		includeLeaves := true
+		r.IncludeLeaves = &includeLeaves
+	}
 }
 
 type FlagCompareRequest struct {
@@ -99,7 +109,9 @@ type FlagCompareRequest struct {
 }
 
 type MultiFlagCompareRequest struct {
-	FlagIDs []uint `form:"flag_ids" binding:"required,min=2,max=10,dive,min=1"`
+	DateRangeParams
+	
+	FlagIDs []uint `form:"ids" collection_format:"csv" binding:"required,min=2,max=10,unique,dive,min=1"`
 	SortBy  string `form:"sort_by" binding:"omitempty,oneof=wins win_rate crews fame"`
 }
 
@@ -109,6 +121,19 @@ func (r *MultiFlagCompareRequest) SetDefaults() {
 	}
 }
 
+func (r *MultiFlagCompareRequest) Validate() error {
+	return validateComparisonRange(r.DateRangeParams)
+}
+
+type FlagTerritoryRequest struct {
+	DateRangeParams
+}
+
+type FlagReputationRequest struct {
+	DateRangeParams
+	ReputationType string `form:"reputation_type" binding:"omitempty,oneof=Conqueror Explorer Patron Magnate"`
+}
+
 type FlagTrendRequest struct {
 	Period string `form:"period" binding:"omitempty,oneof=7d 30d 90d all"`
 }
@@ -196,17 +221,130 @@ type CrewFlagHistoryResponse struct {
 	History     []FlagMembershipLogResponse `json:"history"`
 }
 
+type FlagNameHistoryResponse struct {
+	Flag        FlagBrief                  `json:"flag"`
+	RenameCount int                        `json:"rename_count"`
+	History     []NameHistoryEntryResponse `json:"history"`
+}
+
+type FlagEventsResponse struct {
+	Flag          FlagBrief                `json:"flag"`
+	IsActive      bool                     `json:"is_active"`
+	MissedScrapes int                      `json:"missed_scrapes"`
+	FirstSeenAt   time.Time                `json:"first_seen_at"`
+	LastSeenAt    time.Time                `json:"last_seen_at"`
+	Events        []LifecycleEventResponse `json:"events"`
+}
+
+type FlagReputationLevelResponse struct {
+	ScrapedAt       time.Time `json:"scraped_at"`
+	ReputationLevel string    `json:"reputation_level"`
+}
+
+type FlagReputationTrackResponse struct {
+	ReputationType string    `json:"reputation_type"`
+	CurrentLevel   string    `json:"current_level"`
+	LastScrapedAt  time.Time `json:"last_scraped_at"`
+	
+	// StartLevel and EndLevel are the levels at the start and end of the requested range;
+	// LevelChange is how many levels the flag climbed in between, negative if it fell
+	StartLevel  string `json:"start_level,omitempty"`
+	EndLevel    string `json:"end_level,omitempty"`
+	LevelChange int    `json:"level_change"`
+	
+	// History lists the level at the start of the range and every change after it
+	History []FlagReputationLevelResponse `json:"history"`
+}
+
+type FlagReputationHistoryResponse struct {
+	Flag        FlagBrief                     `json:"flag"`
+	StartDate   time.Time                     `json:"start_date"`
+	EndDate     time.Time                     `json:"end_date"`
+	Reputations []FlagReputationTrackResponse `json:"reputations"`
+}
+
 type FlagMembershipResponse struct {
 	Flag          FlagBrief   `json:"flag"`
 	CurrentCrews  []CrewBrief `json:"current_crews"`
 	TotalCrews    int         `json:"total_crews"`
 	RecentJoins   []CrewFlagChangeResponse `json:"recent_joins,omitempty"`
 	RecentLeaves  []CrewFlagChangeResponse `json:"recent_leaves,omitempty"`
+	
+	StartDate     time.Time `json:"start_date"`
+	EndDate       time.Time `json:"end_date"`
+	StartingCrews int       `json:"starting_crews"`
+	CrewsJoined   int       `json:"crews_joined"`
+	CrewsLeft     int       `json:"crews_left"`
+	NetCrewChange int       `json:"net_crew_change"`
+	ChurnRate     float64   `json:"churn_rate"`
+	
+	Pagination Pagination `json:"pagination"`
 }
 
 type CrewFlagChangeResponse struct {
 	Crew      CrewBrief `json:"crew"`
 	Timestamp time.Time `json:"timestamp"`
+	
+	// Days the crew spent in the flag, up to now if it is still a member
+	Days int `json:"days"`
+	
+	// FromFlag is the flag a joining crew left; ToFlag the flag a departing crew joined
+	FromFlag *FlagBrief `json:"from_flag,omitempty"`
+	ToFlag   *FlagBrief `json:"to_flag,omitempty"`
+}
+
+type FlagTerritoryResponse struct {
+	Flag            FlagBrief                 `json:"flag"`
+	Islands         []TerritoryIslandResponse `json:"islands"`
+	IslandCount     int                       `json:"island_count"`
+	TotalPopulation int                       `json:"total_population"`
+	
+	StartDate       time.Time                 `json:"start_date"`
+	EndDate         time.Time                 `json:"end_date"`
+	Timeline        []TerritoryChangeResponse `json:"timeline"`
+	IslandsGained   int                       `json:"islands_gained"`
+	IslandsLost     int                       `json:"islands_lost"`
+	NetIslandChange int                       `json:"net_island_change"`
+}
+
+type TerritoryIslandResponse struct {
+	Island        IslandBrief       `json:"island"`
+	Archipelago   *ArchipelagoBrief `json:"archipelago,omitempty"`
+	GovernorName  string            `json:"governor_name,omitempty"`
+	Population    int               `json:"population"`
+	GovernedSince *time.Time        `json:"governed_since,omitempty"`
+	
+	// Days the flag has governed the island without interruption
+	Days int `json:"days"`
+}
+
+type TerritoryChangeResponse struct {
+	Island    IslandBrief `json:"island"`
+	Event     string      `json:"event"`
+	Timestamp time.Time   `json:"timestamp"`
+	
+	// Days the tenure gained or lost here lasted, up to now if the flag still governs the island
+	Days int `json:"days"`
+	
+	// FromFlag is the flag an island was taken from; ToFlag the flag that took a lost island
+	FromFlag *FlagBrief `json:"from_flag,omitempty"`
+	ToFlag   *FlagBrief `json:"to_flag,omitempty"`
+}
+
+type OceanTerritoryResponse struct {
+	Ocean             string                  `json:"ocean"`
+	TotalIslands      int                     `json:"total_islands"`
+	GovernedIslands   int                     `json:"governed_islands"`
+	UngovernedIslands int                     `json:"ungoverned_islands"`
+	Flags             []FlagTerritorySummary  `json:"flags"`
+}
+
+type FlagTerritorySummary struct {
+	Flag            FlagBrief     `json:"flag"`
+	IslandCount     int           `json:"island_count"`
+	Share           float64       `json:"share"`
+	TotalPopulation int           `json:"total_population"`
+	Islands         []IslandBrief `json:"islands"`
 }
 
 type FlagHistoryPointResponse struct {
@@ -214,6 +352,8 @@ type FlagHistoryPointResponse struct {
 	CrewCount   int       `json:"crew_count"`
 	TotalWins   int       `json:"total_wins"`
 	TotalLosses int       `json:"total_losses"`
+	DailyWins   int       `json:"daily_wins"`
+	DailyLosses int       `json:"daily_losses"`
 	FameLevel   string    `json:"fame_level"`
 	FameRank    *int      `json:"fame_rank,omitempty"`
 	WinRate     float64   `json:"win_rate"`
@@ -235,6 +375,10 @@ type FlagSearchResultResponse struct {
 	CrewCount int    `json:"crew_count"`
 	FameLevel string `json:"fame_level"`
 	IsActive  bool   `json:"is_active"`
+	
+	// MatchedName is the former name the query matched, if it didn't match the current one
+	MatchedName string  `json:"matched_name,omitempty"`
+	Score       float64 `json:"score"`
 }
 
 type FlagSearchResponse struct {
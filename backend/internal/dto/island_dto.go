@@ -44,13 +44,15 @@ func (r *IslandSearchRequest) SetDefaults() {
 	r.PaginationParams.SetDefaults()
 }
 
+func (r *IslandSearchRequest) Validate() error {
+	return validateSearchQuery(r.Query)
+}
+
 type IslandPopulationHistoryRequest struct {
 	DateRangeParams
 }
 
 type OceanPopulationRequest struct {
-	OceanParam
-	
 	TopN int `form:"top_n" binding:"omitempty,min=1,max=50"`
 }
 
@@ -212,6 +214,25 @@ type ArchipelagoResponse struct {
 	Color       string           `json:"color,omitempty"`
 	IslandCount int              `json:"island_count"`
 	Islands     []IslandBrief    `json:"islands,omitempty"`
+	
+	ColonizedIslands  int                           `json:"colonized_islands"`
+	GovernedIslands   int                           `json:"governed_islands"`
+	TotalPopulation   int                           `json:"total_population"`
+	Governors         []ArchipelagoGovernorResponse `json:"governors"`
+}
+
+// ArchipelagoGovernorResponse is a flag governing islands of an archipelago
+type ArchipelagoGovernorResponse struct {
+	Flag        FlagBrief `json:"flag"`
+	IslandCount int       `json:"island_count"`
+	// Share is the percentage of the archipelago's islands the flag governs
+	Share float64 `json:"share"`
+}
+
+type ArchipelagoDetailResponse struct {
+	ArchipelagoResponse
+	
+	Islands []IslandResponse `json:"islands"`
 }
 
 type ArchipelagoListResponse struct {
@@ -241,6 +262,12 @@ type OceanPopulationResponse struct {
 	IslandCount     int                           `json:"island_count"`
 	ScrapedAt       time.Time                     `json:"scraped_at"`
 	TopIslands      []IslandPopulationRankEntry   `json:"top_islands"`
+	
+	Growth7d  PopulationGrowth `json:"growth_7d"`
+	Growth30d PopulationGrowth `json:"growth_30d"`
+	
+	// UnusualSwings lists the islands whose 7 day growth stands out from the rest of the ocean
+	UnusualSwings []IslandPopulationSwing `json:"unusual_swings"`
 }
 
 type IslandPopulationRankEntry struct {
@@ -248,6 +275,28 @@ type IslandPopulationRankEntry struct {
 	Island     IslandBrief `json:"island"`
 	Population int         `json:"population"`
 	Percentage float64     `json:"percentage"`
+	
+	Growth7d      *PopulationGrowth `json:"growth_7d,omitempty"`
+	Growth30d     *PopulationGrowth `json:"growth_30d,omitempty"`
+	MinPopulation int               `json:"min_population_30d"`
+	MaxPopulation int               `json:"max_population_30d"`
+	AvgPopulation int               `json:"avg_population_30d"`
+	UnusualSwing  bool              `json:"unusual_swing"`
+}
+
+// PopulationGrowth is the change in population since the last record at or before the start
+// of a period. Percentage is omitted when the starting population was zero.
+type PopulationGrowth struct {
+	Change     int      `json:"change"`
+	Percentage *float64 `json:"percentage,omitempty"`
+}
+
+type IslandPopulationSwing struct {
+	Island     IslandBrief      `json:"island"`
+	Population int              `json:"population"`
+	Growth7d   PopulationGrowth `json:"growth_7d"`
+	// ZScore is how many standard deviations the island's 7 day growth is from the ocean mean
+	ZScore float64 `json:"z_score"`
 }
 
 type IslandGovernanceHistoryResponse struct {
@@ -265,12 +314,25 @@ type GovernanceChangeResponse struct {
 	IsCurrent    bool       `json:"is_current"`
 }
 
+type IslandGovernanceChangeResponse struct {
+	Island       IslandBrief `json:"island"`
+	FromFlag     *FlagBrief  `json:"from_flag,omitempty"`
+	ToFlag       *FlagBrief  `json:"to_flag,omitempty"`
+	FromGovernor string      `json:"from_governor,omitempty"`
+	ToGovernor   string      `json:"to_governor,omitempty"`
+	ChangedAt    time.Time   `json:"changed_at"`
+}
+
 type IslandSearchResultResponse struct {
 	IslandBrief
 	ArchipelagoName string   `json:"archipelago_name,omitempty"`
 	Size            string   `json:"size"`
 	Population      int      `json:"population"`
 	Commodities     []string `json:"commodities,omitempty"`
+	
+	// MatchedName is the former name the query matched, if it didn't match the current one
+	MatchedName string  `json:"matched_name,omitempty"`
+	Score       float64 `json:"score"`
 }
 
 type IslandSearchResponse struct {
@@ -47,10 +47,15 @@ type FameLeaderboardRequest struct {
 	PaginationParams
 	
 	EntityType string `form:"entity_type" binding:"required,oneof=crew flag"`
+	
+	Days int `form:"days" binding:"omitempty,min=1,max=90"`
 }
 
 func (r *FameLeaderboardRequest) SetDefaults() {
 	r.PaginationParams.SetDefaults()
+	if r.Days == 0 {
+		r.Days = 1
+	}
 }
 
 type ReputationLeaderboardRequest struct {
@@ -60,10 +65,15 @@ type ReputationLeaderboardRequest struct {
 	EntityType string `form:"entity_type" binding:"required,oneof=crew flag"`
 	
 	ReputationType string `form:"reputation_type" binding:"required,oneof=Conqueror Explorer Patron Magnate"`
+	
+	Days int `form:"days" binding:"omitempty,min=1,max=90"`
 }
 
 func (r *ReputationLeaderboardRequest) SetDefaults() {
 	r.PaginationParams.SetDefaults()
+	if r.Days == 0 {
+		r.Days = 1
+	}
 }
 
 type DailyLeaderboardRequest struct {
@@ -73,6 +83,9 @@ type DailyLeaderboardRequest struct {
 	Date string `form:"date" binding:"omitempty"`
 	
 	Type string `form:"type" binding:"omitempty,oneof=wins battles win_rate"`
+	
+	// MinBattles is a pointer so that an explicit 0 lifts the floor instead of getting the default
+	MinBattles *int `form:"min_battles" binding:"omitempty,min=0"`
 }
 
 func (r *DailyLeaderboardRequest) SetDefaults() {
@@ -80,18 +93,27 @@ func (r *DailyLeaderboardRequest) SetDefaults() {
 	if r.Type == "" {
 		r.Type = "wins"
 	}
+	if r.MinBattles == nil {
+		minBattles := 3
+		r.MinBattles = &minBattles
+	}
 }
 
 type LeaderboardSummaryRequest struct {
 	OceanParam
 	
 	Limit int `form:"limit" binding:"omitempty,min=1,max=25"`
+	
+	Days int `form:"days" binding:"omitempty,min=1,max=90"`
 }
 
 func (r *LeaderboardSummaryRequest) SetDefaults() {
 	if r.Limit == 0 {
 		r.Limit = 5
 	}
+	if r.Days == 0 {
+		r.Days = 1
+	}
 }
 
 // Response types
@@ -108,6 +130,8 @@ type CrewLeaderboardEntryResponse struct {
 	TotalPVPLosses int     `json:"total_pvp_losses"`
 	WinRate        float64 `json:"win_rate"`
 	TotalBattles   int     `json:"total_battles"`
+	PreviousRank   *int    `json:"previous_rank,omitempty"`
+	RankChange     *int    `json:"rank_change,omitempty"`
 }
 
 type CrewLeaderboardResponse struct {
@@ -152,12 +176,18 @@ type CrewFameLeaderboardEntryResponse struct {
 	FlagName  string `json:"flag_name,omitempty"`
 	FameLevel string `json:"fame_level"`
 	CrewRank  string `json:"crew_rank"`
+	
+	PreviousRank *int `json:"previous_rank,omitempty"`
+	RankChange   *int `json:"rank_change,omitempty"`
 }
 
 type CrewFameLeaderboardResponse struct {
 	Ocean      string                             `json:"ocean"`
+	Days       int                                `json:"days"`
 	UpdatedAt  time.Time                          `json:"updated_at"`
 	Entries    []CrewFameLeaderboardEntryResponse `json:"entries"`
+	Climbers   []CrewFameLeaderboardEntryResponse `json:"climbers"`
+	Fallers    []CrewFameLeaderboardEntryResponse `json:"fallers"`
 	Pagination Pagination                         `json:"pagination"`
 }
 
@@ -168,12 +198,18 @@ type FlagFameLeaderboardEntryResponse struct {
 	Ocean     string `json:"ocean"`
 	FameLevel string `json:"fame_level"`
 	CrewCount int    `json:"crew_count"`
+	
+	PreviousRank *int `json:"previous_rank,omitempty"`
+	RankChange   *int `json:"rank_change,omitempty"`
 }
 
 type FlagFameLeaderboardResponse struct {
 	Ocean      string                             `json:"ocean"`
+	Days       int                                `json:"days"`
 	UpdatedAt  time.Time                          `json:"updated_at"`
 	Entries    []FlagFameLeaderboardEntryResponse `json:"entries"`
+	Climbers   []FlagFameLeaderboardEntryResponse `json:"climbers"`
+	Fallers    []FlagFameLeaderboardEntryResponse `json:"fallers"`
 	Pagination Pagination                         `json:"pagination"`
 }
 
@@ -184,14 +220,20 @@ type ReputationLeaderboardEntryResponse struct {
 	Name            string `json:"name"`
 	Ocean           string `json:"ocean"`
 	ReputationLevel string `json:"reputation_level"`
+	
+	PreviousLevel *string `json:"previous_level,omitempty"`
+	LevelChange   *int    `json:"level_change,omitempty"`
 }
 
 type ReputationLeaderboardResponse struct {
 	Ocean          string                               `json:"ocean"`
 	ReputationType string                               `json:"reputation_type"`
 	EntityType     string                               `json:"entity_type"`
+	Days           int                                  `json:"days"`
 	UpdatedAt      time.Time                            `json:"updated_at"`
 	Entries        []ReputationLeaderboardEntryResponse `json:"entries"`
+	Climbers       []ReputationLeaderboardEntryResponse `json:"climbers"`
+	Fallers        []ReputationLeaderboardEntryResponse `json:"fallers"`
 	Pagination     Pagination                           `json:"pagination"`
 }
 
@@ -204,6 +246,9 @@ type LeaderboardSummaryResponse struct {
 	TopCrewsByRank    []CrewLeaderboardEntryResponse `json:"top_crews_by_rank"`
 	TopFlagsByWins    []FlagLeaderboardEntryResponse `json:"top_flags_by_wins"`
 	TopFlagsByWinRate []FlagLeaderboardEntryResponse `json:"top_flags_by_win_rate"`
+	
+	FameClimbers []CrewFameLeaderboardEntryResponse `json:"fame_climbers"`
+	FameFallers  []CrewFameLeaderboardEntryResponse `json:"fame_fallers"`
 }
 
 type DailyCrewLeaderboardEntryResponse struct {
@@ -215,6 +260,8 @@ type DailyCrewLeaderboardEntryResponse struct {
 	DailyLosses int      `json:"daily_losses"`
 	DailyBattles int     `json:"daily_battles"`
 	DailyWinRate float64 `json:"daily_win_rate"`
+	PreviousRank *int    `json:"previous_rank,omitempty"`
+	RankChange   *int    `json:"rank_change,omitempty"`
 }
 
 type DailyLeaderboardResponse struct {
@@ -222,6 +269,7 @@ type DailyLeaderboardResponse struct {
 	Date      time.Time                           `json:"date"`
 	Type      string                              `json:"type"`
 	Entries   []DailyCrewLeaderboardEntryResponse `json:"entries"`
+	Pagination Pagination                         `json:"pagination"`
 }
 
 type LeaderboardTypesResponse struct {
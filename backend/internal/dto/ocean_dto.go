@@ -7,6 +7,8 @@ type OceanResponse struct {
 	DisplayName string `json:"display_name"`
 	IsActive  bool   `json:"is_active"`
 	BaseURL   string `json:"base_url"`
+	
+	LastScrapedAt *time.Time `json:"last_scraped_at,omitempty"`
 }
 
 type OceanListResponse struct {
@@ -24,6 +26,13 @@ type OceanStatsResponse struct {
 	TotalPVPWins    int       `json:"total_pvp_wins"`
 	LastScrapedAt   time.Time `json:"last_scraped_at"`
 	
+	TotalPVPLosses   int     `json:"total_pvp_losses"`
+	RecentPVPBattles int     `json:"recent_pvp_battles"`
+	TotalIslands     int     `json:"total_islands"`
+	ColonizedIslands int     `json:"colonized_islands"`
+	TotalPopulation  int     `json:"total_population"`
+	AvgTaxRate       float64 `json:"avg_tax_rate"`
+	TaxedCommodities int     `json:"taxed_commodities"`
 }
 
 type OceanComparisonResponse struct {
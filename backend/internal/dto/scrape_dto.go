@@ -13,7 +13,7 @@ type ScrapeJobListRequest struct {
 	
 	Ocean   string `form:"ocean" binding:"omitempty,oneof=emerald meridian cerulean obsidian"`
 	JobType string `form:"job_type" binding:"omitempty,oneof=crew_fame flag_fame crew_info battle_info daily_full"`
-	Status  string `form:"status" binding:"omitempty,oneof=running completed failed"`
+	Status  string `form:"status" binding:"omitempty,oneof=running completed failed cancelled interrupted"`
 }
 
 func (r *ScrapeJobListRequest) SetDefaults() {
@@ -44,12 +44,32 @@ func (r *ScrapeHistoryRequest) SetDefaults() {
 
 type UpdateScrapeScheduleRequest struct {
 	Ocean     string `json:"ocean" binding:"required,oneof=emerald meridian cerulean obsidian"`
+	JobType   string `json:"job_type" binding:"omitempty,oneof=crew_fame flag_fame crew_info battle_info daily_full"`
 	Frequency string `json:"frequency" binding:"required,oneof=hourly daily weekly"`
 	IsEnabled bool   `json:"is_enabled"`
 	
+	// Time is the HH:MM Pacific time daily and weekly jobs run at; hourly jobs use its minutes
 	Time string `json:"time" binding:"omitempty"`
 }
 
+var ErrInvalidScheduleTime = &ValidationError{Field: "time", Message: "time must be formatted as HH:MM"}
+
+func (r *UpdateScrapeScheduleRequest) SetDefaults() {
+	if r.JobType == "" {
+		r.JobType = "daily_full"
+	}
+	if r.Time == "" {
+		r.Time = "03:30"
+	}
+}
+
+func (r *UpdateScrapeScheduleRequest) Validate() error {
+	if _, err := time.Parse("15:04", r.Time); err != nil {
+		return ErrInvalidScheduleTime
+	}
+	return nil
+}
+
 // Response types
 type ScrapeJobResponse struct {
 	ID             uint       `json:"id"`
@@ -64,6 +84,11 @@ type ScrapeJobResponse struct {
 	ItemsFailed    int        `json:"items_failed"`
 	SuccessRate    float64    `json:"success_rate"`
 	ErrorMessage   string     `json:"error_message,omitempty"`
+
+	ReprocessedJobID *uint `json:"reprocessed_job_id,omitempty"`
+
+	Stage       string `json:"stage,omitempty"`
+	ResumeCount int    `json:"resume_count"`
 }
 
 type ScrapeJobListResponse struct {
@@ -91,7 +116,9 @@ type ScrapeStatusResponse struct {
 
 type ScrapeScheduleResponse struct {
 	Ocean         string     `json:"ocean"`
+	JobType       string     `json:"job_type"`
 	Frequency     string     `json:"frequency"`
+	Time          string     `json:"time"`
 	NextRun       *time.Time `json:"next_run,omitempty"`
 	LastRun       *time.Time `json:"last_run,omitempty"`
 	LastStatus    string     `json:"last_status,omitempty"`
@@ -109,6 +136,13 @@ type ScrapeTriggerResponse struct {
 	Job       ScrapeJobResponse `json:"job"`
 }
 
+type ScrapeCancelResponse struct {
+	Success bool              `json:"success"`
+	Message string            `json:"message"`
+	JobID   uint              `json:"job_id"`
+	Job     ScrapeJobResponse `json:"job"`
+}
+
 type ScrapeHistoryResponse struct {
 	Ocean           string              `json:"ocean"`
 	TotalJobs       int                 `json:"total_jobs"`
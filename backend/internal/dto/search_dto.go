@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+type SearchRequest struct {
+	PaginationParams
+
+	Query string   `form:"q" binding:"required,min=2,max=100"`
+	Ocean string   `form:"ocean" binding:"omitempty,oneof=emerald meridian cerulean obsidian"`
+	Types []string `form:"types" collection_format:"csv" binding:"omitempty,unique,dive,oneof=crew flag island"`
+}
+
+func (r *SearchRequest) SetDefaults() {
+	r.PaginationParams.SetDefaults()
+}
+
+func (r *SearchRequest) Validate() error {
+	return validateSearchQuery(r.Query)
+}
+
+var ErrSearchQueryTooShort = &ValidationError{Field: "q", Message: "q must be at least 2 characters long, not counting surrounding spaces"}
+
+// validateSearchQuery checks the length of a search query as it is searched for, trimmed of
+// spaces, since a query of spaces or a single letter matches nearly every name
+func validateSearchQuery(q string) error {
+	if utf8.RuneCountInString(strings.TrimSpace(q)) < 2 {
+		return ErrSearchQueryTooShort
+	}
+	return nil
+}
+
+// Response types
+
+// SearchResultResponse is one ranked hit; the field matching Type holds the entity
+type SearchResultResponse struct {
+	Type   string                      `json:"type"`
+	Crew   *CrewSearchResultResponse   `json:"crew,omitempty"`
+	Flag   *FlagSearchResultResponse   `json:"flag,omitempty"`
+	Island *IslandSearchResultResponse `json:"island,omitempty"`
+}
+
+type SearchResponse struct {
+	Query      string                 `json:"query"`
+	Results    []SearchResultResponse `json:"results"`
+	TotalCount int                    `json:"total_count"`
+}
@@ -0,0 +1,65 @@
+package fetcher
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Fetcher retrieves pages from the Puzzle Pirates website. The scraper and the CSV poller
+// fetch everything through one, so they can run against recorded pages instead of the live site.
+type Fetcher interface {
+	Fetch(url string) ([]byte, error)
+}
+
+// Fetch modes
+const (
+	ModeLive   = "live"
+	ModeRecord = "record"
+	ModeReplay = "replay"
+)
+
+// Options selects how pages are fetched: from the live site, from the live site while saving
+// every page under Dir, or from pages previously saved under Dir
+type Options struct {
+	Mode string
+	Dir  string
+}
+
+func (o Options) Validate() error {
+	switch o.Mode {
+	case "", ModeLive:
+		return nil
+	case ModeRecord, ModeReplay:
+		if o.Dir == "" {
+			return fmt.Errorf("fetch mode %s needs a page directory", o.Mode)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown fetch mode %q", o.Mode)
+}
+
+// New returns a fetcher for valid options. Live requests wait delay between each other.
+func New(opts Options, delay time.Duration) Fetcher {
+	switch opts.Mode {
+	case ModeRecord:
+		return NewRecordingFetcher(NewLiveFetcher(delay), opts.Dir)
+	case ModeReplay:
+		return NewReplayFetcher(opts.Dir)
+	}
+	return NewLiveFetcher(delay)
+}
+
+// ErrNotRecorded is returned when replaying a page that was never recorded
+var ErrNotRecorded = errors.New("page not recorded")
+
+// StatusError is returned for pages the site answered with an error status
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: unexpected status code %d (%s)", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
@@ -0,0 +1,72 @@
+package fetcher
+
+import (
+	"log"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/gocolly/colly/v2/debug"
+)
+
+// liveRequestTimeout bounds each request, leaving room for the market CSVs, which are large
+const liveRequestTimeout = 30 * time.Second
+
+// LiveFetcher fetches pages from the live site one at a time
+type LiveFetcher struct {
+	collector *colly.Collector
+}
+
+// NewLiveFetcher creates a fetcher that waits delay between requests to the site
+func NewLiveFetcher(delay time.Duration) *LiveFetcher {
+	collector := colly.NewCollector(
+		colly.Debugger(&debug.LogDebugger{}),
+		colly.AllowURLRevisit(),
+		colly.MaxBodySize(0),
+	)
+	collector.SetRequestTimeout(liveRequestTimeout)
+
+	// Set rate limiting: one request at a time, delay apart
+	collector.Limit(&colly.LimitRule{
+		DomainGlob:  "*.puzzlepirates.com",
+		Parallelism: 1,
+		Delay:       delay,
+	})
+
+	// Set user agent
+	collector.UserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36"
+
+	// Handle errors
+	collector.OnError(func(r *colly.Response, err error) {
+		log.Printf("Request URL: %s failed with error: %v\n", r.Request.URL, err)
+	})
+
+	return &LiveFetcher{collector: collector}
+}
+
+// Fetch fetches a page, returning a StatusError if the site answers with an error status
+func (f *LiveFetcher) Fetch(url string) ([]byte, error) {
+	var body []byte
+	var fetchErr error
+
+	// Create a temporary collector for this request to avoid handler conflicts
+	tempCollector := f.collector.Clone()
+	tempCollector.OnResponse(func(r *colly.Response) {
+		body = r.Body
+	})
+	tempCollector.OnError(func(r *colly.Response, err error) {
+		fetchErr = err
+		if r.StatusCode != 0 {
+			fetchErr = &StatusError{URL: url, StatusCode: r.StatusCode}
+		}
+	})
+
+	visitErr := tempCollector.Visit(url)
+	if fetchErr != nil {
+		return nil, fetchErr
+	}
+	if visitErr != nil {
+		return nil, visitErr
+	}
+
+	return body, nil
+}
@@ -0,0 +1,85 @@
+package fetcher
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// PagePath returns where a page is saved under dir: the URL's host and path, with its query,
+// sorted by key, appended after an @. For example the crew info page of crew 42 on Emerald
+// is saved at emerald.puzzlepirates.com/yoweb/crew/info.wm@crewid=42.
+func PagePath(dir, pageURL string) (string, error) {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid page URL %q: %w", pageURL, err)
+	}
+
+	name := u.Path
+	if u.RawQuery != "" {
+		query, err := url.ParseQuery(u.RawQuery)
+		if err != nil {
+			return "", fmt.Errorf("invalid query in page URL %q: %w", pageURL, err)
+		}
+		name += "@" + query.Encode()
+	}
+	return filepath.Join(dir, u.Host, filepath.FromSlash(name)), nil
+}
+
+// ReplayFetcher serves pages saved under a directory, see PagePath
+type ReplayFetcher struct {
+	dir string
+}
+
+func NewReplayFetcher(dir string) *ReplayFetcher {
+	return &ReplayFetcher{dir: dir}
+}
+
+// Fetch returns the saved page, or ErrNotRecorded if there is none
+func (f *ReplayFetcher) Fetch(pageURL string) ([]byte, error) {
+	path, err := PagePath(f.dir, pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%s: %w", pageURL, ErrNotRecorded)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// RecordingFetcher fetches pages through another fetcher and saves each one under a directory,
+// where a ReplayFetcher can serve them later. Pages that fail to fetch are not saved.
+type RecordingFetcher struct {
+	next Fetcher
+	dir  string
+}
+
+func NewRecordingFetcher(next Fetcher, dir string) *RecordingFetcher {
+	return &RecordingFetcher{next: next, dir: dir}
+}
+
+func (f *RecordingFetcher) Fetch(pageURL string) ([]byte, error) {
+	body, err := f.next.Fetch(pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := PagePath(f.dir, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to save %s: %w", pageURL, err)
+	}
+	if err := os.WriteFile(path, body, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to save %s: %w", pageURL, err)
+	}
+	return body, nil
+}
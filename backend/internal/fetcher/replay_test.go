@@ -0,0 +1,97 @@
+package fetcher
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// pageMap serves pages from memory, failing for unknown URLs
+type pageMap map[string]string
+
+func (m pageMap) Fetch(url string) ([]byte, error) {
+	page, ok := m[url]
+	if !ok {
+		return nil, &StatusError{URL: url, StatusCode: 404}
+	}
+	return []byte(page), nil
+}
+
+func TestPagePath(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{
+			url:  "https://emerald.puzzlepirates.com/ratings/top_fame_97.html",
+			want: "pages/emerald.puzzlepirates.com/ratings/top_fame_97.html",
+		},
+		{
+			url:  "https://emerald.puzzlepirates.com/yoweb/crew/info.wm?crewid=42",
+			want: "pages/emerald.puzzlepirates.com/yoweb/crew/info.wm@crewid=42",
+		},
+		{
+			// Query parameters are sorted so equivalent URLs share a page
+			url:  "https://meridian.puzzlepirates.com/yoweb/crew/battleinfo.wm?crewid=42&classic=false",
+			want: "pages/meridian.puzzlepirates.com/yoweb/crew/battleinfo.wm@classic=false&crewid=42",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			got, err := PagePath("pages", tt.url)
+			if err != nil {
+				t.Fatalf("PagePath() error = %v", err)
+			}
+			if got != filepath.FromSlash(tt.want) {
+				t.Errorf("PagePath() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+	crewURL := "https://emerald.puzzlepirates.com/yoweb/crew/info.wm?crewid=42"
+	missingURL := "https://emerald.puzzlepirates.com/yoweb/crew/info.wm?crewid=43"
+
+	recorder := NewRecordingFetcher(pageMap{crewURL: "<html>Crew 42</html>"}, dir)
+	if _, err := recorder.Fetch(crewURL); err != nil {
+		t.Fatalf("recording %s: %v", crewURL, err)
+	}
+	var statusErr *StatusError
+	if _, err := recorder.Fetch(missingURL); !errors.As(err, &statusErr) || statusErr.StatusCode != 404 {
+		t.Errorf("recording a failing page: error = %v, want the 404 passed through", err)
+	}
+
+	replayer := NewReplayFetcher(dir)
+	page, err := replayer.Fetch(crewURL)
+	if err != nil {
+		t.Fatalf("replaying %s: %v", crewURL, err)
+	}
+	if string(page) != "<html>Crew 42</html>" {
+		t.Errorf("replayed page = %q, want the recorded one", page)
+	}
+	if _, err := replayer.Fetch(missingURL); !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("replaying a page that failed to record: error = %v, want ErrNotRecorded", err)
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	tests := []struct {
+		opts    Options
+		wantErr bool
+	}{
+		{Options{}, false},
+		{Options{Mode: ModeLive}, false},
+		{Options{Mode: ModeReplay, Dir: "pages"}, false},
+		{Options{Mode: ModeRecord}, true},
+		{Options{Mode: "offline", Dir: "pages"}, true},
+	}
+
+	for _, tt := range tests {
+		if err := tt.opts.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%+v.Validate() error = %v, wantErr %v", tt.opts, err, tt.wantErr)
+		}
+	}
+}
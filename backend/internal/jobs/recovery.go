@@ -0,0 +1,102 @@
+package jobs
+
+import (
+	"cutlass_analytics/internal/fetcher"
+	"cutlass_analytics/internal/models"
+	"cutlass_analytics/internal/scraper"
+	"errors"
+	"log"
+	"time"
+)
+
+// reprocessStaleAfter is how long a running reprocess job can go without a heartbeat before its
+// process is taken for dead
+const reprocessStaleAfter = 5 * scraper.ReprocessHeartbeat
+
+// resumeWindow is how long after it started an interrupted scrape job is still resumed. Older
+// jobs are left to the next scheduled run, since what they scraped is stale by then.
+const resumeWindow = 12 * time.Hour
+
+// recoverJobs picks up the scrape jobs a previous server process left unfinished. Jobs still
+// marked running were orphaned by a crash and are marked interrupted. The newest interrupted job
+// of each ocean and job type is resumed if it started within resumeWindow, and the others are
+// marked failed. Reprocess jobs run in their own process and are only failed once their
+// heartbeat stops.
+func (s *Scheduler) recoverJobs() error {
+	if err := s.failStaleReprocessJobs(); err != nil {
+		return err
+	}
+
+	var orphaned []models.ScrapeJob
+	if err := s.db.Where("status = ? AND reprocessed_job_id IS NULL", models.ScrapeJobStatusRunning).
+		Find(&orphaned).Error; err != nil {
+		return err
+	}
+	for i := range orphaned {
+		log.Printf("Scrape job %d (%s, %s) was orphaned by a server restart", orphaned[i].ID,
+			orphaned[i].JobType, orphaned[i].Ocean)
+		if err := orphaned[i].MarkInterrupted(s.db); err != nil {
+			return err
+		}
+	}
+
+	var interrupted []models.ScrapeJob
+	if err := s.db.Where("status = ?", models.ScrapeJobStatusInterrupted).
+		Order("started_at DESC").Find(&interrupted).Error; err != nil {
+		return err
+	}
+
+	cutoff := time.Now().Add(-resumeWindow)
+	recovered := make(map[jobKey]bool)
+	for i := range interrupted {
+		job := &interrupted[i]
+		key := jobKey{ocean: job.Ocean, jobType: job.JobType}
+		if recovered[key] || job.StartedAt.Before(cutoff) {
+			if err := job.MarkFailed(s.db, errors.New("interrupted and not resumed")); err != nil {
+				return err
+			}
+			continue
+		}
+		recovered[key] = true
+
+		if _, _, err := s.launch(key, func(f fetcher.Fetcher) (*scraper.Scraper, error) {
+			return scraper.ResumeScraper(s.db, f, job)
+		}); err != nil {
+			log.Printf("Failed to resume scrape job %d: %v", job.ID, err)
+			if err := job.MarkFailed(s.db, err); err != nil {
+				return err
+			}
+			continue
+		}
+		log.Printf("Resumed scrape job %d (%s, %s) at stage %q", job.ID, job.JobType, job.Ocean, job.Stage)
+	}
+
+	// Only running jobs can still be resumed, so the checkpoints of any other job are left over
+	running := s.db.Model(&models.ScrapeJob{}).Select("id").Where("status = ?", models.ScrapeJobStatusRunning)
+	return s.db.Unscoped().Where("scrape_job_id NOT IN (?)", running).
+		Delete(&models.ScrapeJobCheckpoint{}).Error
+}
+
+// failStaleReprocessJobs marks failed the running reprocess jobs whose process stopped sending
+// heartbeats, such as one killed without a chance to mark its job, so their ocean isn't reported
+// as being scraped forever
+func (s *Scheduler) failStaleReprocessJobs() error {
+	var stale []models.ScrapeJob
+	if err := s.db.Where("status = ? AND reprocessed_job_id IS NOT NULL AND updated_at < ?",
+		models.ScrapeJobStatusRunning, time.Now().Add(-reprocessStaleAfter)).
+		Find(&stale).Error; err != nil {
+		return err
+	}
+	for i := range stale {
+		job := &stale[i]
+		log.Printf("Reprocess job %d (%s, %s) stopped sending heartbeats", job.ID, job.JobType, job.Ocean)
+		if err := job.MarkFailed(s.db, errors.New("reprocess process stopped")); err != nil {
+			return err
+		}
+		if err := s.db.Unscoped().Where("scrape_job_id = ?", job.ID).
+			Delete(&models.ScrapeJobCheckpoint{}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,10 +1,15 @@
 package jobs
 
 import (
+	"context"
+	"cutlass_analytics/internal/archive"
+	"cutlass_analytics/internal/fetcher"
 	"cutlass_analytics/internal/models"
 	"cutlass_analytics/internal/poller"
 	"cutlass_analytics/internal/scraper"
 	"cutlass_analytics/internal/types"
+	"errors"
+	"fmt"
 	"log"
 	"sync"
 	"time"
@@ -13,19 +18,55 @@ import (
 	"gorm.io/gorm"
 )
 
+// csvPollerSpec is when the CSV poller runs. Unlike scrape jobs it isn't in scrape_schedules:
+// it polls every ocean in one run, which is not tied to a scrape job, and runs more often than
+// the hourly schedules allow.
+const csvPollerSpec = "*/10 * * * *"
+
+// ErrJobAlreadyRunning is returned by StartJob while a job of the same type runs for the ocean
+var ErrJobAlreadyRunning = errors.New("a scrape job of this type is already running for the ocean")
+
+// ErrJobNotRunning is returned by CancelJob for jobs the scheduler isn't running
+var ErrJobNotRunning = errors.New("scrape job is not running")
+
+// ErrSchedulerStopped is returned by StartJob once the scheduler is stopping
+var ErrSchedulerStopped = errors.New("scheduler is stopped")
+
 // Scheduler manages cron jobs for scraping operations
 type Scheduler struct {
-	cron      *cron.Cron
-	db        *gorm.DB
-	csvPoller *poller.CSVPoller
-	stop      chan struct{}
-	wg        sync.WaitGroup
-	running   bool
-	mu        sync.Mutex
+	cron         *cron.Cron
+	db           *gorm.DB
+	fetchOptions fetcher.Options
+	csvPoller    *poller.CSVPoller
+	stop         chan struct{}
+	wg           sync.WaitGroup
+	running      bool
+	mu           sync.Mutex
+
+	// jobs holds the scrape jobs currently running, at most one per ocean and job type
+	jobs   map[jobKey]*runningJob
+	jobsMu sync.Mutex
+
+	// schedules holds the cron entries of the enabled scrape schedules
+	schedules   map[jobKey]scheduledEntry
+	schedulesMu sync.Mutex
+}
+
+// jobKey identifies the scrape jobs that may not run at the same time
+type jobKey struct {
+	ocean   types.Ocean
+	jobType models.ScrapeJobType
 }
 
-// NewScheduler creates a new scheduler instance
-func NewScheduler(db *gorm.DB) *Scheduler {
+// runningJob is a scrape job started by the scheduler that hasn't finished yet
+type runningJob struct {
+	jobID  uint
+	cancel context.CancelCauseFunc
+	done   chan struct{}
+}
+
+// NewScheduler creates a new scheduler instance whose jobs fetch pages as fetchOptions selects
+func NewScheduler(db *gorm.DB, fetchOptions fetcher.Options) *Scheduler {
 	// Create cron with PST timezone
 	pstLocation, err := time.LoadLocation("America/Los_Angeles")
 	if err != nil {
@@ -35,18 +76,14 @@ func NewScheduler(db *gorm.DB) *Scheduler {
 
 	c := cron.New(cron.WithLocation(pstLocation))
 
-	// Configure oceans for the CSV poller
-	oceans := []types.Ocean{
-		types.OceanEmerald,
-		types.OceanMeridian,
-		types.OceanCerulean,
-	}
-
 	return &Scheduler{
-		cron:      c,
-		db:        db,
-		csvPoller: poller.NewCSVPoller(db, oceans),
-		stop:      make(chan struct{}),
+		cron:         c,
+		db:           db,
+		fetchOptions: fetchOptions,
+		csvPoller:    poller.NewCSVPoller(db, fetcher.New(fetchOptions, 0), types.AllOceans()),
+		stop:         make(chan struct{}),
+		jobs:         make(map[jobKey]*runningJob),
+		schedules:    make(map[jobKey]scheduledEntry),
 	}
 }
 
@@ -59,22 +96,50 @@ func (s *Scheduler) Start() error {
 		return nil
 	}
 
-	// Schedule daily scraper at 3:30 AM PST
-	// Cron format: minute hour day month day-of-week
-	_, err := s.cron.AddFunc("30 3 * * *", s.runDailyScrapers)
+	// Pick up the scrape jobs the previous server process didn't finish
+	if err := s.recoverJobs(); err != nil {
+		return err
+	}
+
+	// Schedule scrape jobs from the scrape_schedules table, checking it for changes every minute
+	if err := s.seedSchedules(); err != nil {
+		return err
+	}
+	if err := s.SyncSchedules(); err != nil {
+		return err
+	}
+	_, err := s.cron.AddFunc("* * * * *", s.syncSchedules)
 	if err != nil {
 		return err
 	}
 
 	// Schedule CSV poller every 10 minutes
-	_, err = s.cron.AddFunc("*/10 * * * *", s.runCSVPoller)
+	_, err = s.cron.AddFunc(csvPollerSpec, s.runCSVPoller)
+	if err != nil {
+		return err
+	}
+
+	// Schedule market order retention daily at 4:30 AM PST
+	_, err = s.cron.AddFunc("30 4 * * *", s.runMarketRetention)
+	if err != nil {
+		return err
+	}
+
+	// Check for reprocess jobs whose process died every 5 minutes
+	_, err = s.cron.AddFunc("*/5 * * * *", s.runStaleReprocessCheck)
+	if err != nil {
+		return err
+	}
+
+	// Schedule page archive retention daily at 4:45 AM PST
+	_, err = s.cron.AddFunc("45 4 * * *", s.runArchiveRetention)
 	if err != nil {
 		return err
 	}
 
 	s.cron.Start()
 	s.running = true
-	log.Println("Scheduler started - Daily scraper scheduled for 3:30 AM PST, CSV poller every 10 minutes")
+	log.Println("Scheduler started - Scrape jobs scheduled from scrape_schedules, CSV poller every 10 minutes, market retention at 4:30 AM PST, archive retention at 4:45 AM PST, stale reprocess job check every 5 minutes")
 
 	return nil
 }
@@ -88,6 +153,15 @@ func (s *Scheduler) Stop() {
 		return
 	}
 
+	// Interrupt the running scrape jobs so the cron jobs waiting on them can return. They are
+	// resumed when the scheduler next starts.
+	s.jobsMu.Lock()
+	close(s.stop)
+	for _, job := range s.jobs {
+		job.cancel(scraper.ErrInterrupted)
+	}
+	s.jobsMu.Unlock()
+
 	ctx := s.cron.Stop()
 	select {
 	case <-ctx.Done():
@@ -96,59 +170,87 @@ func (s *Scheduler) Stop() {
 		log.Println("Warning: Scheduler stop timeout")
 	}
 
-	close(s.stop)
 	s.wg.Wait()
 	s.running = false
 }
 
-// RunOnce runs all scrapers immediately in a goroutine
-// This is useful for running jobs on server startup
-func (s *Scheduler) RunOnce() {
-	go s.runDailyScrapers()
-	log.Println("Triggered immediate scraper job run on server startup")
+// StartJob starts a scrape job of jobType for ocean in the background and returns the job as
+// created. Only one job of a type runs per ocean at a time; starting another one fails with
+// ErrJobAlreadyRunning.
+func (s *Scheduler) StartJob(ocean types.Ocean, jobType models.ScrapeJobType) (*models.ScrapeJob, error) {
+	job, _, err := s.startJob(ocean, jobType)
+	return job, err
 }
 
-// runDailyScrapers runs all scrapers for all oceans
-func (s *Scheduler) runDailyScrapers() {
-	log.Println("Starting daily scraper job for all oceans")
-
-	oceans := []types.Ocean{
-		types.OceanEmerald,
-		types.OceanMeridian,
-		types.OceanCerulean,
-	}
+// CancelJob cancels the running scrape job id. The job stops before fetching its next page and is
+// marked cancelled.
+func (s *Scheduler) CancelJob(id uint) error {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
 
-	var wg sync.WaitGroup
-	for _, ocean := range oceans {
-		wg.Add(1)
-		go func(o types.Ocean) {
-			defer wg.Done()
-			if err := s.runScraperForOcean(o); err != nil {
-				log.Printf("Error running scraper for ocean %s: %v", o, err)
-			}
-		}(ocean)
+	for _, job := range s.jobs {
+		if job.jobID == id {
+			job.cancel(nil)
+			return nil
+		}
 	}
+	return ErrJobNotRunning
+}
 
-	wg.Wait()
-	log.Println("Daily scraper job completed for all oceans")
+// startJob starts a scrape job and returns a copy of it as created, along with a channel that is
+// closed once the job has finished
+func (s *Scheduler) startJob(ocean types.Ocean, jobType models.ScrapeJobType) (*models.ScrapeJob, <-chan struct{}, error) {
+	key := jobKey{ocean: ocean, jobType: jobType}
+	return s.launch(key, func(f fetcher.Fetcher) (*scraper.Scraper, error) {
+		return scraper.NewScraper(s.db, f, ocean, jobType)
+	})
 }
 
-// runScraperForOcean runs the full scraper for a specific ocean
-func (s *Scheduler) runScraperForOcean(ocean types.Ocean) error {
-	log.Printf("Starting scraper for ocean: %s", ocean)
+// launch runs the scraper newScraper creates in the background, unless a job for key is already
+// running. It returns a copy of the scraper's job and a channel that is closed once it's finished.
+func (s *Scheduler) launch(key jobKey, newScraper func(fetcher.Fetcher) (*scraper.Scraper, error)) (*models.ScrapeJob, <-chan struct{}, error) {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
 
-	scraperInstance, err := scraper.NewScraper(s.db, ocean, models.ScrapeJobTypeDailyFull)
-	if err != nil {
-		return err
+	select {
+	case <-s.stop:
+		return nil, nil, ErrSchedulerStopped
+	default:
 	}
 
-	if err := scraperInstance.Run(); err != nil {
-		log.Printf("Scraper failed for ocean %s: %v", ocean, err)
-		return err
+	if running, ok := s.jobs[key]; ok {
+		return nil, nil, fmt.Errorf("%w (job %d)", ErrJobAlreadyRunning, running.jobID)
 	}
 
-	log.Printf("Scraper completed successfully for ocean: %s", ocean)
-	return nil
+	// Each job gets its own fetcher so the oceans are rate limited independently
+	f := fetcher.New(s.fetchOptions, scraper.RequestDelay)
+	scraperInstance, err := newScraper(f)
+	if err != nil {
+		return nil, nil, err
+	}
+	job := *scraperInstance.Job()
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	running := &runningJob{jobID: job.ID, cancel: cancel, done: make(chan struct{})}
+	s.jobs[key] = running
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer close(running.done)
+		defer func() {
+			s.jobsMu.Lock()
+			delete(s.jobs, key)
+			s.jobsMu.Unlock()
+			cancel(nil)
+		}()
+
+		if err := scraperInstance.Run(ctx); err != nil {
+			log.Printf("Scrape job %d (%s, %s) failed: %v", job.ID, key.jobType, key.ocean, err)
+		}
+	}()
+
+	return &job, running.done, nil
 }
 
 // runCSVPoller runs the CSV poller to import market orders
@@ -157,3 +259,29 @@ func (s *Scheduler) runCSVPoller() {
 		log.Printf("CSV poller error: %v", err)
 	}
 }
+
+// runMarketRetention downsamples market order snapshots older than the retention window
+func (s *Scheduler) runMarketRetention() {
+	cutoff := time.Now().Add(-poller.MarketOrderRetention)
+	if err := s.csvPoller.ApplyRetention(cutoff); err != nil {
+		log.Printf("Market retention error: %v", err)
+	}
+}
+
+// runStaleReprocessCheck fails the reprocess jobs that stopped sending heartbeats
+func (s *Scheduler) runStaleReprocessCheck() {
+	if err := s.failStaleReprocessJobs(); err != nil {
+		log.Printf("Stale reprocess job check error: %v", err)
+	}
+}
+
+// runArchiveRetention deletes archived pages older than the retention window
+func (s *Scheduler) runArchiveRetention() {
+	cutoff := time.Now().Add(-archive.Retention)
+	deleted, err := archive.Prune(s.db, cutoff)
+	if err != nil {
+		log.Printf("Archive retention error: %v", err)
+		return
+	}
+	log.Printf("Archive retention: deleted %d pages fetched before %s", deleted, cutoff.Format(time.RFC3339))
+}
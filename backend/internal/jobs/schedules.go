@@ -0,0 +1,126 @@
+package jobs
+
+import (
+	"cutlass_analytics/internal/models"
+	"cutlass_analytics/internal/types"
+	"log"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// scheduledEntry is the cron entry running an enabled scrape schedule
+type scheduledEntry struct {
+	spec string
+	id   cron.EntryID
+}
+
+// seedSchedules fills an empty scrape_schedules table with the schedule the scheduler used to
+// hardcode: a full scrape of every ocean daily at 3:30 AM PST
+func (s *Scheduler) seedSchedules() error {
+	var count int64
+	if err := s.db.Model(&models.ScrapeSchedule{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	oceans := types.AllOceans()
+	schedules := make([]models.ScrapeSchedule, len(oceans))
+	for i, ocean := range oceans {
+		schedules[i] = models.ScrapeSchedule{
+			Ocean:     ocean,
+			JobType:   models.ScrapeJobTypeDailyFull,
+			Frequency: models.ScrapeFrequencyDaily,
+			Time:      "03:30",
+			IsEnabled: true,
+		}
+	}
+	return s.db.Create(&schedules).Error
+}
+
+// SyncSchedules brings the cron entries in line with the enabled scrape schedules, adding,
+// replacing and removing entries whose schedule changed. It runs every minute, so edits to the
+// scrape_schedules table take effect without a restart.
+func (s *Scheduler) SyncSchedules() error {
+	var schedules []models.ScrapeSchedule
+	if err := s.db.Find(&schedules).Error; err != nil {
+		return err
+	}
+
+	wanted := make(map[jobKey]string)
+	for _, schedule := range schedules {
+		if !schedule.IsEnabled {
+			continue
+		}
+		spec, err := schedule.CronSpec()
+		if err != nil {
+			log.Printf("Skipping %s schedule for %s: %v", schedule.JobType, schedule.Ocean, err)
+			continue
+		}
+		wanted[jobKey{ocean: schedule.Ocean, jobType: schedule.JobType}] = spec
+	}
+
+	s.schedulesMu.Lock()
+	defer s.schedulesMu.Unlock()
+
+	for key, entry := range s.schedules {
+		if wanted[key] != entry.spec {
+			s.cron.Remove(entry.id)
+			delete(s.schedules, key)
+			log.Printf("Unscheduled %s scrapes for %s", key.jobType, key.ocean)
+		}
+	}
+
+	for key, spec := range wanted {
+		if _, ok := s.schedules[key]; ok {
+			continue
+		}
+		id, err := s.cron.AddFunc(spec, func() { s.runScheduledJob(key) })
+		if err != nil {
+			return err
+		}
+		s.schedules[key] = scheduledEntry{spec: spec, id: id}
+		log.Printf("Scheduled %s scrapes for %s at %q", key.jobType, key.ocean, spec)
+	}
+
+	return nil
+}
+
+// NextRun returns when the scrape schedule of jobType for ocean runs next, nil if it isn't
+// scheduled
+func (s *Scheduler) NextRun(ocean types.Ocean, jobType models.ScrapeJobType) *time.Time {
+	s.schedulesMu.Lock()
+	defer s.schedulesMu.Unlock()
+
+	entry, ok := s.schedules[jobKey{ocean: ocean, jobType: jobType}]
+	if !ok {
+		return nil
+	}
+	next := s.cron.Entry(entry.id).Next
+	if next.IsZero() {
+		return nil
+	}
+	return &next
+}
+
+// syncSchedules runs SyncSchedules from cron
+func (s *Scheduler) syncSchedules() {
+	if err := s.SyncSchedules(); err != nil {
+		log.Printf("Scrape schedule sync error: %v", err)
+	}
+}
+
+// runScheduledJob runs a scheduled scrape job and waits for it to finish. A run is skipped while
+// the previous one is still going.
+func (s *Scheduler) runScheduledJob(key jobKey) {
+	job, done, err := s.startJob(key.ocean, key.jobType)
+	if err != nil {
+		log.Printf("Scheduled %s scrape for %s not started: %v", key.jobType, key.ocean, err)
+		return
+	}
+	<-done
+
+	log.Printf("Scheduled %s scrape for %s finished (job %d)", key.jobType, key.ocean, job.ID)
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ArchivedPage keeps the raw body of a page fetched by a scrape job or the CSV poller, gzip
+// compressed, so it can be parsed again after a parser fix. Market CSVs have no scrape job; they
+// are found by URL, which names the ocean, and fetch time, which is the import time, and link to
+// the snapshot they were imported as.
+type ArchivedPage struct {
+	gorm.Model
+	ScrapeJobID *uint     `gorm:"index:idx_archived_page_job_url" json:"scrape_job_id,omitempty"`
+	URL         string    `gorm:"type:text;not null;index:idx_archived_page_job_url" json:"url"`
+	FetchedAt   time.Time `gorm:"not null;index" json:"fetched_at"`
+
+	// MarketSnapshotID is nil for pages fetched by scrape jobs and for CSVs that had no orders
+	MarketSnapshotID *uint `gorm:"index" json:"market_snapshot_id,omitempty"`
+
+	// Size is the length of the uncompressed body
+	Size int    `gorm:"not null" json:"size"`
+	Body []byte `gorm:"type:bytea;not null" json:"-"`
+}
+
+func (ArchivedPage) TableName() string {
+	return "archived_pages"
+}
+
+// NewArchivedPage compresses body into a page archived for the scrape job jobID, if any
+func NewArchivedPage(jobID *uint, url string, body []byte, fetchedAt time.Time) (*ArchivedPage, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(body); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return &ArchivedPage{
+		ScrapeJobID: jobID,
+		URL:         url,
+		FetchedAt:   fetchedAt,
+		Size:        len(body),
+		Body:        buf.Bytes(),
+	}, nil
+}
+
+// Content decompresses the page body
+func (p *ArchivedPage) Content() ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(p.Body))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
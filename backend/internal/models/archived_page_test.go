@@ -0,0 +1,38 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestArchivedPageContent(t *testing.T) {
+	body := []byte(strings.Repeat("<tr><td>Crew</td><td>Fame</td></tr>\n", 200))
+	jobID := uint(7)
+
+	page, err := NewArchivedPage(&jobID, "https://emerald.puzzlepirates.com/yoweb/crew/info.wm?crewid=1", body, time.Now())
+	if err != nil {
+		t.Fatalf("NewArchivedPage: %v", err)
+	}
+	if page.Size != len(body) {
+		t.Errorf("Size = %d, want %d", page.Size, len(body))
+	}
+	if len(page.Body) >= len(body) {
+		t.Errorf("compressed body is %d bytes, want fewer than %d", len(page.Body), len(body))
+	}
+
+	content, err := page.Content()
+	if err != nil {
+		t.Fatalf("Content: %v", err)
+	}
+	if string(content) != string(body) {
+		t.Error("Content does not round-trip the archived body")
+	}
+}
+
+func TestArchivedPageContentCorrupt(t *testing.T) {
+	page := ArchivedPage{Body: []byte("not gzip")}
+	if _, err := page.Content(); err == nil {
+		t.Error("expected an error for a body that isn't gzip")
+	}
+}
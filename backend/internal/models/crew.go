@@ -15,6 +15,7 @@ type Crew struct {
 	Name        string    `gorm:"type:varchar(100);not null" json:"name"`
 	FlagID      *uint     `gorm:"index" json:"flag_id,omitempty"`
 	IsActive    bool      `gorm:"default:true" json:"is_active"`
+	MissedScrapes int     `gorm:"default:0" json:"missed_scrapes"`
 	FirstSeenAt time.Time `gorm:"not null" json:"first_seen_at"`
 	LastSeenAt  time.Time `gorm:"not null" json:"last_seen_at"`
 
@@ -71,3 +72,25 @@ func (c *Crew) GetLatestFameRecord(db *gorm.DB) (*CrewFameRecord, error) {
 	}
 	return &record, nil
 }
+
+// RecordMiss counts a scrape in a row that didn't find the crew and reports whether that made it
+// inactive, which happens once threshold consecutive scrapes have missed it
+func (c *Crew) RecordMiss(threshold int) bool {
+	c.MissedScrapes++
+	if c.IsActive && c.MissedScrapes >= threshold {
+		c.IsActive = false
+		return true
+	}
+	return false
+}
+
+// RecordSighting resets the miss count of a crew a scrape found and reports whether it
+// reappeared after being marked inactive
+func (c *Crew) RecordSighting() bool {
+	c.MissedScrapes = 0
+	if !c.IsActive {
+		c.IsActive = true
+		return true
+	}
+	return false
+}
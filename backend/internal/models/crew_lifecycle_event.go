@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LifecycleEventType string
+
+const (
+	LifecycleEventFirstSeen  LifecycleEventType = "first_seen"
+	LifecycleEventDisbanded  LifecycleEventType = "disbanded"
+	LifecycleEventDissolved  LifecycleEventType = "dissolved"
+	LifecycleEventReappeared LifecycleEventType = "reappeared"
+)
+
+// CrewLifecycleEvent records a crew appearing for the first time, being marked disbanded after
+// missing consecutive scrapes, or reappearing afterwards
+type CrewLifecycleEvent struct {
+	gorm.Model
+	CrewID     uint               `gorm:"not null;index" json:"crew_id"`
+	EventType  LifecycleEventType `gorm:"type:varchar(20);not null" json:"event_type"`
+	OccurredAt time.Time          `gorm:"not null;index" json:"occurred_at"`
+
+	// MissedScrapes is how many scrapes in a row had missed the crew when the event happened
+	MissedScrapes int   `gorm:"default:0" json:"missed_scrapes"`
+	ScrapeJobID   *uint `gorm:"index" json:"scrape_job_id,omitempty"`
+
+	Crew Crew `gorm:"foreignKey:CrewID" json:"crew,omitempty"`
+}
+
+func (CrewLifecycleEvent) TableName() string {
+	return "crew_lifecycle_events"
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CrewNameHistory is a period during which a crew went by a name. Renames end the current
+// record and start a new one, so former names are kept after the crew row is updated.
+type CrewNameHistory struct {
+	gorm.Model
+	CrewID    uint       `gorm:"not null;index" json:"crew_id"`
+	Name      string     `gorm:"type:varchar(100);not null" json:"name"`
+	StartedAt time.Time  `gorm:"not null" json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+
+	Crew Crew `gorm:"foreignKey:CrewID" json:"crew,omitempty"`
+}
+
+func (CrewNameHistory) TableName() string {
+	return "crew_name_history"
+}
+
+func (h *CrewNameHistory) IsCurrent() bool {
+	return h.EndedAt == nil
+}
+
+func (h *CrewNameHistory) Duration() time.Duration {
+	endTime := time.Now()
+	if h.EndedAt != nil {
+		endTime = *h.EndedAt
+	}
+	return endTime.Sub(h.StartedAt)
+}
+
+func (h *CrewNameHistory) DurationDays() int {
+	return int(h.Duration().Hours() / 24)
+}
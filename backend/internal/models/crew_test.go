@@ -0,0 +1,41 @@
+package models
+
+import "testing"
+
+func TestCrewLifecycle(t *testing.T) {
+	crew := Crew{IsActive: true}
+
+	for i := 1; i < 3; i++ {
+		if crew.RecordMiss(3) {
+			t.Fatalf("miss %d deactivated the crew before the threshold", i)
+		}
+	}
+	if !crew.IsActive || crew.MissedScrapes != 2 {
+		t.Fatalf("after 2 misses: active = %v, missed = %d, want true, 2", crew.IsActive, crew.MissedScrapes)
+	}
+
+	if !crew.RecordMiss(3) {
+		t.Fatalf("third miss should deactivate the crew")
+	}
+	if crew.IsActive {
+		t.Errorf("crew should be inactive after reaching the threshold")
+	}
+	if crew.RecordMiss(3) {
+		t.Errorf("misses after deactivation should not deactivate the crew again")
+	}
+
+	if !crew.RecordSighting() {
+		t.Fatalf("sighting an inactive crew should report it reappeared")
+	}
+	if !crew.IsActive || crew.MissedScrapes != 0 {
+		t.Errorf("after sighting: active = %v, missed = %d, want true, 0", crew.IsActive, crew.MissedScrapes)
+	}
+
+	crew.RecordMiss(3)
+	if crew.RecordSighting() {
+		t.Errorf("sighting an active crew should not report it reappeared")
+	}
+	if crew.MissedScrapes != 0 {
+		t.Errorf("sighting should reset misses, got %d", crew.MissedScrapes)
+	}
+}
@@ -14,11 +14,13 @@ type Flag struct {
 	Ocean       types.Ocean     `gorm:"uniqueIndex:idx_flag_ocean;type:varchar(20);not null" json:"ocean"`
 	Name        string    `gorm:"type:varchar(100);not null" json:"name"`
 	IsActive    bool      `gorm:"default:true" json:"is_active"`
+	MissedScrapes int     `gorm:"default:0" json:"missed_scrapes"`
 	FirstSeenAt time.Time `gorm:"not null" json:"first_seen_at"`
 	LastSeenAt  time.Time `gorm:"not null" json:"last_seen_at"`
 
-	Crews           []Crew           `gorm:"foreignKey:FlagID" json:"crews,omitempty"`
-	FlagFameRecords []FlagFameRecord `gorm:"foreignKey:FlagID" json:"fame_records,omitempty"`
+	Crews             []Crew                 `gorm:"foreignKey:FlagID" json:"crews,omitempty"`
+	FlagFameRecords   []FlagFameRecord       `gorm:"foreignKey:FlagID" json:"fame_records,omitempty"`
+	ReputationRecords []FlagReputationRecord `gorm:"foreignKey:FlagID" json:"reputation_records,omitempty"`
 }
 
 func (Flag) TableName() string {
@@ -44,3 +46,25 @@ func (f *Flag) ActiveCrewCount(db *gorm.DB) int64 {
 	db.Model(&Crew{}).Where("flag_id = ? AND is_active = ?", f.ID, true).Count(&count)
 	return count
 }
+
+// RecordMiss counts a scrape in a row that didn't find the flag and reports whether that made it
+// inactive, which happens once threshold consecutive scrapes have missed it
+func (f *Flag) RecordMiss(threshold int) bool {
+	f.MissedScrapes++
+	if f.IsActive && f.MissedScrapes >= threshold {
+		f.IsActive = false
+		return true
+	}
+	return false
+}
+
+// RecordSighting resets the miss count of a flag a scrape found and reports whether it
+// reappeared after being marked inactive
+func (f *Flag) RecordSighting() bool {
+	f.MissedScrapes = 0
+	if !f.IsActive {
+		f.IsActive = true
+		return true
+	}
+	return false
+}
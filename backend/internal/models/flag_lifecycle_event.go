@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FlagLifecycleEvent records a flag appearing for the first time, being marked dissolved after
+// missing consecutive scrapes, or reappearing afterwards
+type FlagLifecycleEvent struct {
+	gorm.Model
+	FlagID     uint               `gorm:"not null;index" json:"flag_id"`
+	EventType  LifecycleEventType `gorm:"type:varchar(20);not null" json:"event_type"`
+	OccurredAt time.Time          `gorm:"not null;index" json:"occurred_at"`
+
+	// MissedScrapes is how many scrapes in a row had missed the flag when the event happened
+	MissedScrapes int   `gorm:"default:0" json:"missed_scrapes"`
+	ScrapeJobID   *uint `gorm:"index" json:"scrape_job_id,omitempty"`
+
+	Flag Flag `gorm:"foreignKey:FlagID" json:"flag,omitempty"`
+}
+
+func (FlagLifecycleEvent) TableName() string {
+	return "flag_lifecycle_events"
+}
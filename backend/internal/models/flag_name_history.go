@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FlagNameHistory is a period during which a flag went by a name. Renames end the current
+// record and start a new one, so former names are kept after the flag row is updated.
+type FlagNameHistory struct {
+	gorm.Model
+	FlagID    uint       `gorm:"not null;index" json:"flag_id"`
+	Name      string     `gorm:"type:varchar(100);not null" json:"name"`
+	StartedAt time.Time  `gorm:"not null" json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+
+	Flag Flag `gorm:"foreignKey:FlagID" json:"flag,omitempty"`
+}
+
+func (FlagNameHistory) TableName() string {
+	return "flag_name_history"
+}
+
+func (h *FlagNameHistory) IsCurrent() bool {
+	return h.EndedAt == nil
+}
+
+func (h *FlagNameHistory) Duration() time.Duration {
+	endTime := time.Now()
+	if h.EndedAt != nil {
+		endTime = *h.EndedAt
+	}
+	return endTime.Sub(h.StartedAt)
+}
+
+func (h *FlagNameHistory) DurationDays() int {
+	return int(h.Duration().Hours() / 24)
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"cutlass_analytics/internal/types"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FlagReputationRecord is a flag's level in one reputation as shown on its info page. Unlike crew
+// reputation, flag pages list levels only, so there is no rank.
+type FlagReputationRecord struct {
+	gorm.Model
+	FlagID         uint                 `gorm:"uniqueIndex:idx_flag_rep_date_type;not null" json:"flag_id"`
+	ScrapedAt      time.Time            `gorm:"uniqueIndex:idx_flag_rep_date_type;not null;index" json:"scraped_at"`
+	ReputationType types.ReputationType `gorm:"uniqueIndex:idx_flag_rep_date_type;type:varchar(20);not null" json:"reputation_type"`
+
+	ReputationLevel types.FameLevel `gorm:"type:varchar(30)" json:"reputation_level"`
+
+	Flag Flag `gorm:"foreignKey:FlagID" json:"flag,omitempty"`
+}
+
+func (FlagReputationRecord) TableName() string {
+	return "flag_reputation_records"
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"sort"
 	"time"
 
 	"gorm.io/gorm"
@@ -26,4 +27,89 @@ func (IslandGovernanceHistory) TableName() string {
 
 func (h *IslandGovernanceHistory) IsCurrent() bool {
 	return h.EndedAt == nil
-}
\ No newline at end of file
+}
+
+// GovernanceTenure is one unbroken stretch of a flag governing an island. Consecutive
+// governance records under the same flag, such as a new governor from the same flag,
+// belong to the same tenure.
+type GovernanceTenure struct {
+	IslandID  uint
+	FlagID    uint
+	StartedAt time.Time
+	EndedAt   *time.Time
+
+	// PreviousFlagID and NextFlagID are the flags governing the island before and after
+	// the tenure, nil when the island was ungoverned or the tenure has no neighbour
+	PreviousFlagID *uint
+	NextFlagID     *uint
+
+	// FirstSeen is set when the tenure starts with the island's first governance record,
+	// meaning the flag already held the island when tracking began
+	FirstSeen bool
+}
+
+func (t *GovernanceTenure) IsCurrent() bool {
+	return t.EndedAt == nil
+}
+
+func (t *GovernanceTenure) Duration() time.Duration {
+	endTime := time.Now()
+	if t.EndedAt != nil {
+		endTime = *t.EndedAt
+	}
+	return endTime.Sub(t.StartedAt)
+}
+
+func (t *GovernanceTenure) DurationDays() int {
+	return int(t.Duration().Hours() / 24)
+}
+
+// FlagTenures builds a flag's tenures from the governance records of the islands it governed.
+// history must hold every record of those islands so gains and losses can be attributed;
+// tenures are returned ordered by island, then start.
+func FlagTenures(history []IslandGovernanceHistory, flagID uint) []GovernanceTenure {
+	records := make([]IslandGovernanceHistory, len(history))
+	copy(records, history)
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].IslandID != records[j].IslandID {
+			return records[i].IslandID < records[j].IslandID
+		}
+		return records[i].StartedAt.Before(records[j].StartedAt)
+	})
+
+	var tenures []GovernanceTenure
+	var current *GovernanceTenure
+	for i := range records {
+		record := &records[i]
+		firstOfIsland := i == 0 || records[i-1].IslandID != record.IslandID
+		governed := record.FlagID != nil && *record.FlagID == flagID
+
+		if current != nil && (firstOfIsland || !governed) {
+			if !firstOfIsland {
+				current.NextFlagID = record.FlagID
+			}
+			tenures = append(tenures, *current)
+			current = nil
+		}
+		if !governed {
+			continue
+		}
+
+		if current == nil {
+			current = &GovernanceTenure{
+				IslandID:  record.IslandID,
+				FlagID:    flagID,
+				StartedAt: record.StartedAt,
+				FirstSeen: firstOfIsland,
+			}
+			if !firstOfIsland {
+				current.PreviousFlagID = records[i-1].FlagID
+			}
+		}
+		current.EndedAt = record.EndedAt
+	}
+	if current != nil {
+		tenures = append(tenures, *current)
+	}
+	return tenures
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFlagTenures(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	ended := func(d int) *time.Time { end := day(d); return &end }
+	flag := func(id uint) *uint { return &id }
+
+	history := []IslandGovernanceHistory{
+		// Island 1: held from the start, new governor from the same flag, lost to flag 8
+		{IslandID: 1, FlagID: flag(7), GovernorName: "Anne", StartedAt: day(1), EndedAt: ended(3)},
+		{IslandID: 1, FlagID: flag(7), GovernorName: "Bart", StartedAt: day(3), EndedAt: ended(10)},
+		{IslandID: 1, FlagID: flag(8), GovernorName: "Cora", StartedAt: day(10)},
+		// Island 2: taken while ungoverned and still held, listed out of order
+		{IslandID: 2, FlagID: flag(7), GovernorName: "Dirk", StartedAt: day(5)},
+		{IslandID: 2, FlagID: nil, StartedAt: day(1), EndedAt: ended(5)},
+		// Island 3: never governed by flag 7
+		{IslandID: 3, FlagID: flag(8), GovernorName: "Eve", StartedAt: day(1)},
+	}
+
+	tenures := FlagTenures(history, 7)
+	if len(tenures) != 2 {
+		t.Fatalf("expected 2 tenures, got %d", len(tenures))
+	}
+
+	lost := tenures[0]
+	if lost.IslandID != 1 || !lost.StartedAt.Equal(day(1)) || lost.EndedAt == nil || !lost.EndedAt.Equal(day(10)) {
+		t.Errorf("island 1 tenure = %v to %v, want Jan 1 to Jan 10", lost.StartedAt, lost.EndedAt)
+	}
+	if !lost.FirstSeen || lost.PreviousFlagID != nil {
+		t.Errorf("island 1 tenure should start with the first record")
+	}
+	if lost.NextFlagID == nil || *lost.NextFlagID != 8 {
+		t.Errorf("island 1 next flag = %v, want 8", lost.NextFlagID)
+	}
+	if lost.DurationDays() != 9 {
+		t.Errorf("island 1 tenure = %d days, want 9", lost.DurationDays())
+	}
+
+	held := tenures[1]
+	if held.IslandID != 2 || !held.StartedAt.Equal(day(5)) || !held.IsCurrent() {
+		t.Errorf("island 2 tenure = %v to %v, want current since Jan 5", held.StartedAt, held.EndedAt)
+	}
+	// The island was ungoverned before, so the tenure is a gain with no previous flag
+	if held.FirstSeen || held.PreviousFlagID != nil {
+		t.Errorf("island 2 tenure should be a gain from an ungoverned island")
+	}
+}
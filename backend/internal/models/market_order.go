@@ -1,7 +1,10 @@
 package models
 
 import (
+	"crypto/sha256"
 	"cutlass_analytics/internal/types"
+	"encoding/hex"
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
@@ -10,6 +13,9 @@ import (
 // MarketOrder represents market order data from the buysell CSV export
 // Each row in the CSV contains both buy and sell data for a specific shop
 // Source: https://{ocean}.puzzlepirates.com/yoweb/econ/buysell.wm
+//
+// Rows are only inserted when a shop's offer changes. A row stays current until a
+// later snapshot retires it, so the order book for any snapshot can be rebuilt.
 type MarketOrder struct {
 	gorm.Model
 	Ocean         types.Ocean `gorm:"type:varchar(20);not null;index" json:"ocean"`
@@ -23,8 +29,37 @@ type MarketOrder struct {
 	SellQuantity int `gorm:"not null;default:0" json:"sell_quantity"`
 
 	ImportedAt time.Time `gorm:"not null;index" json:"imported_at"`
+
+	SnapshotID        uint       `gorm:"not null;default:0;index" json:"snapshot_id"`
+	RetiredSnapshotID *uint      `gorm:"index" json:"retired_snapshot_id,omitempty"`
+	RetiredAt         *time.Time `gorm:"index" json:"retired_at,omitempty"`
+
+	DataHash string `gorm:"type:varchar(64)" json:"data_hash,omitempty"`
 }
 
 func (MarketOrder) TableName() string {
 	return "market_orders"
 }
+
+// ShopKey identifies the shop offer this row belongs to across snapshots
+func (o *MarketOrder) ShopKey() string {
+	return fmt.Sprintf("%s|%s|%s|%s", o.Ocean, o.IslandName, o.CommodityName, o.ShopName)
+}
+
+// ComputeHash returns a hash of the offer's prices and quantities
+func (o *MarketOrder) ComputeHash() string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d|%d|%d|%d", o.BuyPrice, o.BuyQuantity, o.SellPrice, o.SellQuantity)))
+	return hex.EncodeToString(sum[:])
+}
+
+func (o *MarketOrder) IsCurrent() bool {
+	return o.RetiredAt == nil
+}
+
+func (o *MarketOrder) HasBuyOffer() bool {
+	return o.BuyPrice > 0 && o.BuyQuantity > 0
+}
+
+func (o *MarketOrder) HasSellOffer() bool {
+	return o.SellPrice > 0 && o.SellQuantity > 0
+}
@@ -33,3 +33,56 @@ func (p *MarketPrice) Spread() *int {
 	spread := *p.SellPrice - *p.BuyPrice
 	return &spread
 }
+
+func (p *MarketPrice) HasBuyOffer() bool {
+	return p.BuyPrice != nil
+}
+
+func (p *MarketPrice) HasSellOffer() bool {
+	return p.SellPrice != nil
+}
+
+// SummarizeMarketOrders reduces shop orders to the best bid and ask per island and commodity.
+// Quantities are the total offered across all shops at the best price. IslandID and
+// CommodityID are left unset; Island.Name and Commodity.Name carry the order's names.
+func SummarizeMarketOrders(orders []MarketOrder, scrapedAt time.Time) []MarketPrice {
+	index := make(map[string]int)
+	var prices []MarketPrice
+
+	for _, order := range orders {
+		key := order.IslandName + "|" + order.CommodityName
+		i, ok := index[key]
+		if !ok {
+			i = len(prices)
+			index[key] = i
+			prices = append(prices, MarketPrice{
+				ScrapedAt: scrapedAt,
+				Island:    Island{Ocean: order.Ocean, Name: order.IslandName},
+				Commodity: Commodity{Name: order.CommodityName},
+			})
+		}
+		price := &prices[i]
+
+		if order.HasBuyOffer() {
+			switch {
+			case price.BuyPrice == nil || order.BuyPrice > *price.BuyPrice:
+				buyPrice, buyQuantity := order.BuyPrice, order.BuyQuantity
+				price.BuyPrice, price.BuyQuantity = &buyPrice, &buyQuantity
+			case order.BuyPrice == *price.BuyPrice:
+				*price.BuyQuantity += order.BuyQuantity
+			}
+		}
+
+		if order.HasSellOffer() {
+			switch {
+			case price.SellPrice == nil || order.SellPrice < *price.SellPrice:
+				sellPrice, sellQuantity := order.SellPrice, order.SellQuantity
+				price.SellPrice, price.SellQuantity = &sellPrice, &sellQuantity
+			case order.SellPrice == *price.SellPrice:
+				*price.SellQuantity += order.SellQuantity
+			}
+		}
+	}
+
+	return prices
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummarizeMarketOrders(t *testing.T) {
+	orders := []MarketOrder{
+		{IslandName: "Maia-Insel", CommodityName: "Iron", ShopName: "A", BuyPrice: 10, BuyQuantity: 100, SellPrice: 20, SellQuantity: 5},
+		{IslandName: "Maia-Insel", CommodityName: "Iron", ShopName: "B", BuyPrice: 12, BuyQuantity: 40, SellPrice: 18, SellQuantity: 0},
+		{IslandName: "Maia-Insel", CommodityName: "Iron", ShopName: "C", BuyPrice: 12, BuyQuantity: 10, SellPrice: 20, SellQuantity: 7},
+		{IslandName: "Chachapoya-Insel", CommodityName: "Hemp", ShopName: "D", BuyPrice: 2, BuyQuantity: 0, SellPrice: 10, SellQuantity: 30},
+	}
+
+	summaries := SummarizeMarketOrders(orders, time.Now())
+	if len(summaries) != 2 {
+		t.Fatalf("expected 2 summaries, got %d", len(summaries))
+	}
+
+	iron := summaries[0]
+	if iron.Island.Name != "Maia-Insel" || iron.Commodity.Name != "Iron" {
+		t.Errorf("first summary = %s/%s, want Maia-Insel/Iron", iron.Island.Name, iron.Commodity.Name)
+	}
+	if iron.BuyPrice == nil || *iron.BuyPrice != 12 || *iron.BuyQuantity != 50 {
+		t.Errorf("iron bid = %v x %v, want 12 x 50", iron.BuyPrice, iron.BuyQuantity)
+	}
+	// Shop B lists 18 but has nothing in stock, so the best ask is 20
+	if iron.SellPrice == nil || *iron.SellPrice != 20 || *iron.SellQuantity != 12 {
+		t.Errorf("iron ask = %v x %v, want 20 x 12", iron.SellPrice, iron.SellQuantity)
+	}
+
+	hemp := summaries[1]
+	if hemp.BuyPrice != nil {
+		t.Errorf("hemp bid = %d, want none", *hemp.BuyPrice)
+	}
+	if hemp.SellPrice == nil || *hemp.SellPrice != 10 || *hemp.SellQuantity != 30 {
+		t.Errorf("hemp ask = %v x %v, want 10 x 30", hemp.SellPrice, hemp.SellQuantity)
+	}
+}
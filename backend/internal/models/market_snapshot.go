@@ -0,0 +1,32 @@
+package models
+
+import (
+	"cutlass_analytics/internal/types"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MarketSnapshot records a single poll of an ocean's buysell CSV export.
+// Orders are stored as change rows: a MarketOrder is part of every snapshot from
+// its SnapshotID up to (but not including) its RetiredSnapshotID.
+type MarketSnapshot struct {
+	gorm.Model
+	Ocean      types.Ocean `gorm:"type:varchar(20);not null;index:idx_market_snapshot_ocean_date" json:"ocean"`
+	ImportedAt time.Time   `gorm:"not null;index:idx_market_snapshot_ocean_date" json:"imported_at"`
+
+	OrderCount     int `gorm:"default:0" json:"order_count"`
+	OrdersInserted int `gorm:"default:0" json:"orders_inserted"`
+	OrdersRetired  int `gorm:"default:0" json:"orders_retired"`
+
+	// IsDownsampled is set once the snapshot has been rolled up into market_prices
+	IsDownsampled bool `gorm:"default:false;index" json:"is_downsampled"`
+}
+
+func (MarketSnapshot) TableName() string {
+	return "market_snapshots"
+}
+
+func (s *MarketSnapshot) OrdersUnchanged() int {
+	return s.OrderCount - s.OrdersInserted
+}
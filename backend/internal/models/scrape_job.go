@@ -13,6 +13,10 @@ const (
 	ScrapeJobStatusRunning   ScrapeJobStatus = "running"
 	ScrapeJobStatusCompleted ScrapeJobStatus = "completed"
 	ScrapeJobStatusFailed    ScrapeJobStatus = "failed"
+	ScrapeJobStatusCancelled ScrapeJobStatus = "cancelled"
+
+	// ScrapeJobStatusInterrupted marks a job stopped by a shutdown or crash that can be resumed
+	ScrapeJobStatusInterrupted ScrapeJobStatus = "interrupted"
 )
 
 type ScrapeJobType string
@@ -36,6 +40,16 @@ type ScrapeJob struct {
 	ItemsProcessed int    `gorm:"default:0" json:"items_processed"`
 	ItemsFailed    int    `gorm:"default:0" json:"items_failed"`
 	ErrorMessage   string `gorm:"type:text" json:"error_message,omitempty"`
+
+	// ReprocessedJobID is the job whose archived pages this job parsed again instead of fetching
+	ReprocessedJobID *uint `gorm:"index" json:"reprocessed_job_id,omitempty"`
+
+	// Stage is the part of the job being worked on, where an interrupted job resumes
+	Stage string `gorm:"type:varchar(20)" json:"stage,omitempty"`
+	// StageScrapedAt is the time the records of the stage are stamped with, which a resumed job
+	// keeps stamping the rest of them with
+	StageScrapedAt *time.Time `json:"stage_scraped_at,omitempty"`
+	ResumeCount    int        `gorm:"default:0" json:"resume_count"`
 }
 
 func (ScrapeJob) TableName() string {
@@ -69,6 +83,20 @@ func (s *ScrapeJob) MarkFailed(db *gorm.DB, err error) error {
 	return db.Save(s).Error
 }
 
+func (s *ScrapeJob) MarkCancelled(db *gorm.DB) error {
+	now := time.Now()
+	s.EndedAt = &now
+	s.Status = ScrapeJobStatusCancelled
+	return db.Save(s).Error
+}
+
+func (s *ScrapeJob) MarkInterrupted(db *gorm.DB) error {
+	now := time.Now()
+	s.EndedAt = &now
+	s.Status = ScrapeJobStatusInterrupted
+	return db.Save(s).Error
+}
+
 func (s *ScrapeJob) IncrementProcessed() {
 	s.ItemsProcessed++
 }
@@ -89,6 +117,10 @@ func (s *ScrapeJob) IsRunning() bool {
 	return s.Status == ScrapeJobStatusRunning
 }
 
+func (s *ScrapeJob) IsReprocessing() bool {
+	return s.ReprocessedJobID != nil
+}
+
 func (s *ScrapeJob) SuccessRate() float64 {
 	total := s.ItemsProcessed + s.ItemsFailed
 	if total == 0 {
@@ -0,0 +1,28 @@
+package models
+
+import "gorm.io/gorm"
+
+type CheckpointOutcome string
+
+const (
+	CheckpointProcessed CheckpointOutcome = "processed"
+	// CheckpointMissing is an item whose page says it no longer exists
+	CheckpointMissing CheckpointOutcome = "missing"
+	CheckpointFailed  CheckpointOutcome = "failed"
+	// CheckpointSkipped is an item there was nothing to scrape for
+	CheckpointSkipped CheckpointOutcome = "skipped"
+)
+
+// ScrapeJobCheckpoint records an item a running scrape job has finished with, such as a crew, so
+// the job skips it when resumed after an interruption. Checkpoints are deleted once the job ends.
+type ScrapeJobCheckpoint struct {
+	gorm.Model
+	ScrapeJobID uint              `gorm:"not null;uniqueIndex:idx_scrape_checkpoint_item" json:"scrape_job_id"`
+	Stage       string            `gorm:"type:varchar(20);not null;uniqueIndex:idx_scrape_checkpoint_item" json:"stage"`
+	ItemID      uint64            `gorm:"not null;uniqueIndex:idx_scrape_checkpoint_item" json:"item_id"`
+	Outcome     CheckpointOutcome `gorm:"type:varchar(20);not null" json:"outcome"`
+}
+
+func (ScrapeJobCheckpoint) TableName() string {
+	return "scrape_job_checkpoints"
+}
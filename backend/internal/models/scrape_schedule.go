@@ -0,0 +1,51 @@
+package models
+
+import (
+	"cutlass_analytics/internal/types"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ScrapeFrequency string
+
+const (
+	ScrapeFrequencyHourly ScrapeFrequency = "hourly"
+	ScrapeFrequencyDaily  ScrapeFrequency = "daily"
+	ScrapeFrequencyWeekly ScrapeFrequency = "weekly"
+)
+
+// ScrapeSchedule is how often the scheduler runs a type of scrape job for an ocean. Time is the
+// Pacific time of day, as HH:MM, daily jobs run at; hourly jobs only use its minutes and weekly
+// jobs run at it on Sundays.
+type ScrapeSchedule struct {
+	gorm.Model
+	Ocean     types.Ocean     `gorm:"type:varchar(20);not null;uniqueIndex:idx_scrape_schedule_ocean_type" json:"ocean"`
+	JobType   ScrapeJobType   `gorm:"type:varchar(50);not null;uniqueIndex:idx_scrape_schedule_ocean_type" json:"job_type"`
+	Frequency ScrapeFrequency `gorm:"type:varchar(20);not null" json:"frequency"`
+	Time      string          `gorm:"type:varchar(5);not null" json:"time"`
+	IsEnabled bool            `gorm:"not null" json:"is_enabled"`
+}
+
+func (ScrapeSchedule) TableName() string {
+	return "scrape_schedules"
+}
+
+// CronSpec returns the cron expression the schedule runs on
+func (s *ScrapeSchedule) CronSpec() (string, error) {
+	at, err := time.Parse("15:04", s.Time)
+	if err != nil {
+		return "", fmt.Errorf("invalid schedule time %q, want HH:MM", s.Time)
+	}
+
+	switch s.Frequency {
+	case ScrapeFrequencyHourly:
+		return fmt.Sprintf("%d * * * *", at.Minute()), nil
+	case ScrapeFrequencyDaily:
+		return fmt.Sprintf("%d %d * * *", at.Minute(), at.Hour()), nil
+	case ScrapeFrequencyWeekly:
+		return fmt.Sprintf("%d %d * * 0", at.Minute(), at.Hour()), nil
+	}
+	return "", fmt.Errorf("unknown schedule frequency %q", s.Frequency)
+}
@@ -0,0 +1,33 @@
+package models
+
+import "testing"
+
+func TestScrapeScheduleCronSpec(t *testing.T) {
+	tests := []struct {
+		name      string
+		frequency ScrapeFrequency
+		time      string
+		want      string
+		wantErr   bool
+	}{
+		{name: "hourly uses the minutes", frequency: ScrapeFrequencyHourly, time: "03:15", want: "15 * * * *"},
+		{name: "daily", frequency: ScrapeFrequencyDaily, time: "03:30", want: "30 3 * * *"},
+		{name: "weekly runs on sundays", frequency: ScrapeFrequencyWeekly, time: "22:05", want: "5 22 * * 0"},
+		{name: "invalid time", frequency: ScrapeFrequencyDaily, time: "25:00", wantErr: true},
+		{name: "missing time", frequency: ScrapeFrequencyDaily, time: "", wantErr: true},
+		{name: "unknown frequency", frequency: "monthly", time: "03:30", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := ScrapeSchedule{Frequency: tt.frequency, Time: tt.time}
+			got, err := schedule.CronSpec()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CronSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("CronSpec() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
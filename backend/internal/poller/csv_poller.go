@@ -1,13 +1,15 @@
 package poller
 
 import (
+	"bytes"
+	"cutlass_analytics/internal/archive"
+	"cutlass_analytics/internal/fetcher"
 	"cutlass_analytics/internal/models"
 	"cutlass_analytics/internal/types"
 	"encoding/csv"
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -18,16 +20,16 @@ import (
 // CSVPoller handles fetching and importing market order data from the Puzzle Pirates buysell endpoint
 type CSVPoller struct {
 	db      *gorm.DB
-	client  *http.Client
+	fetcher fetcher.Fetcher
 	oceans  []types.Ocean
 }
 
-// NewCSVPoller creates a new CSV poller instance
-func NewCSVPoller(db *gorm.DB, oceans []types.Ocean) *CSVPoller {
+// NewCSVPoller creates a new CSV poller instance fetching the market CSVs through f
+func NewCSVPoller(db *gorm.DB, f fetcher.Fetcher, oceans []types.Ocean) *CSVPoller {
 	return &CSVPoller{
-		db:     db,
-		client: &http.Client{Timeout: 30 * time.Second},
-		oceans: oceans,
+		db:      db,
+		fetcher: f,
+		oceans:  oceans,
 	}
 }
 
@@ -40,48 +42,60 @@ func getBuySellURL(ocean types.Ocean) string {
 func (p *CSVPoller) Run() error {
 	log.Println("CSV poller: Starting market order import...")
 
-	var allOrders []models.MarketOrder
 	now := time.Now()
+	imported := 0
 
 	for _, ocean := range p.oceans {
-		orders, err := p.fetchAndParse(ocean, now)
+		orders, page, err := p.fetchAndParse(ocean, now)
 		if err != nil {
 			log.Printf("CSV poller: Error fetching %s ocean: %v", ocean, err)
 			continue
 		}
 		log.Printf("CSV poller: Fetched %d orders from %s ocean", len(orders), ocean)
-		allOrders = append(allOrders, orders...)
+
+		if len(orders) == 0 {
+			continue
+		}
+
+		// Import each ocean as its own snapshot so a failed fetch leaves that ocean's book untouched
+		snapshot, err := p.importOrders(ocean, orders, now, page)
+		if err != nil {
+			return fmt.Errorf("failed to import orders for %s: %w", ocean, err)
+		}
+		log.Printf("CSV poller: Snapshot %d for %s ocean: %d orders, %d inserted, %d retired",
+			snapshot.ID, ocean, snapshot.OrderCount, snapshot.OrdersInserted, snapshot.OrdersRetired)
+		imported += len(orders)
 	}
 
-	if len(allOrders) == 0 {
+	if imported == 0 {
 		log.Println("CSV poller: No orders fetched from any ocean")
 		return nil
 	}
 
-	// Import orders atomically
-	if err := p.importOrders(allOrders); err != nil {
-		return fmt.Errorf("failed to import orders: %w", err)
-	}
-
-	log.Printf("CSV poller: Successfully imported %d market orders", len(allOrders))
+	log.Printf("CSV poller: Successfully imported %d market orders", imported)
 	return nil
 }
 
-// fetchAndParse fetches the CSV from a specific ocean and parses it
-func (p *CSVPoller) fetchAndParse(ocean types.Ocean, importTime time.Time) ([]models.MarketOrder, error) {
+// fetchAndParse fetches the CSV from a specific ocean, archives it so the import can be
+// reprocessed, and parses it. The archived page is nil if archiving failed.
+func (p *CSVPoller) fetchAndParse(ocean types.Ocean, importTime time.Time) ([]models.MarketOrder, *models.ArchivedPage, error) {
 	url := getBuySellURL(ocean)
 
-	resp, err := p.client.Get(url)
+	body, err := p.fetcher.Fetch(url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch CSV: %w", err)
+		return nil, nil, fmt.Errorf("failed to fetch CSV: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	page, err := archive.Store(p.db, nil, url, body, importTime)
+	if err != nil {
+		log.Printf("CSV poller: Failed to archive %s: %v", url, err)
 	}
 
-	return p.parseCSV(resp.Body, ocean, importTime)
+	orders, err := p.parseCSV(bytes.NewReader(body), ocean, importTime)
+	if err != nil {
+		return nil, nil, err
+	}
+	return orders, page, nil
 }
 
 // parseCSV reads and parses CSV data from a reader
@@ -169,28 +183,119 @@ func (p *CSVPoller) parseRecord(record []string, ocean types.Ocean, importTime t
 	}, nil
 }
 
-// importOrders replaces all existing market orders with new ones atomically
-func (p *CSVPoller) importOrders(orders []models.MarketOrder) error {
-	return p.db.Transaction(func(tx *gorm.DB) error {
-		// Delete all existing market orders
-		if err := tx.Exec("DELETE FROM market_orders").Error; err != nil {
-			return fmt.Errorf("failed to clear existing orders: %w", err)
+// importOrders records a new snapshot for an ocean, inserting only the shop offers that
+// changed since the previous snapshot and retiring offers that disappeared. page is the
+// archived CSV the orders were parsed from, linked to the snapshot unless nil.
+func (p *CSVPoller) importOrders(ocean types.Ocean, orders []models.MarketOrder, importTime time.Time, page *models.ArchivedPage) (*models.MarketSnapshot, error) {
+	snapshot := &models.MarketSnapshot{
+		Ocean:      ocean,
+		ImportedAt: importTime,
+	}
+
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(snapshot).Error; err != nil {
+			return fmt.Errorf("failed to create snapshot: %w", err)
+		}
+
+		if page != nil {
+			if err := tx.Model(page).Update("market_snapshot_id", snapshot.ID).Error; err != nil {
+				return fmt.Errorf("failed to link archived CSV: %w", err)
+			}
+		}
+
+		// Load the current order book for this ocean
+		var current []models.MarketOrder
+		if err := tx.Where("ocean = ? AND retired_at IS NULL", ocean).
+			Find(&current).Error; err != nil {
+			return fmt.Errorf("failed to load current orders: %w", err)
+		}
+
+		inserts, retiredIDs, total := diffOrders(current, orders)
+
+		// Retire offers that changed or are no longer listed
+		if len(retiredIDs) > 0 {
+			if err := tx.Model(&models.MarketOrder{}).
+				Where("id IN ?", retiredIDs).
+				Updates(map[string]interface{}{
+					"retired_at":          importTime,
+					"retired_snapshot_id": snapshot.ID,
+				}).Error; err != nil {
+				return fmt.Errorf("failed to retire orders: %w", err)
+			}
+		}
+
+		for i := range inserts {
+			inserts[i].SnapshotID = snapshot.ID
 		}
 
 		// Batch insert new orders for better performance
 		batchSize := 100
-		for i := 0; i < len(orders); i += batchSize {
+		for i := 0; i < len(inserts); i += batchSize {
 			end := i + batchSize
-			if end > len(orders) {
-				end = len(orders)
+			if end > len(inserts) {
+				end = len(inserts)
 			}
-			batch := orders[i:end]
+			batch := inserts[i:end]
 
 			if err := tx.Create(&batch).Error; err != nil {
 				return fmt.Errorf("failed to insert orders batch: %w", err)
 			}
 		}
 
-		return nil
+		snapshot.OrderCount = total
+		snapshot.OrdersInserted = len(inserts)
+		snapshot.OrdersRetired = len(retiredIDs)
+		return tx.Save(snapshot).Error
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// diffOrders compares the current order book with freshly parsed orders.
+// It returns the orders to insert, the IDs of current orders to retire, and the
+// number of distinct shop offers in the new snapshot.
+func diffOrders(current []models.MarketOrder, incoming []models.MarketOrder) ([]models.MarketOrder, []uint, int) {
+	// Deduplicate incoming rows per shop, keeping the last one listed
+	latest := make(map[string]models.MarketOrder, len(incoming))
+	keys := make([]string, 0, len(incoming))
+	for _, order := range incoming {
+		key := order.ShopKey()
+		if _, seen := latest[key]; !seen {
+			keys = append(keys, key)
+		}
+		order.DataHash = order.ComputeHash()
+		latest[key] = order
+	}
+
+	currentByKey := make(map[string]models.MarketOrder, len(current))
+	for _, order := range current {
+		currentByKey[order.ShopKey()] = order
+	}
+
+	var inserts []models.MarketOrder
+	var retiredIDs []uint
+	for _, key := range keys {
+		order := latest[key]
+		existing, ok := currentByKey[key]
+		if ok {
+			delete(currentByKey, key)
+			if existing.DataHash == order.DataHash {
+				continue
+			}
+			retiredIDs = append(retiredIDs, existing.ID)
+		}
+		inserts = append(inserts, order)
+	}
+
+	// Anything left in the current book was not listed in this snapshot
+	for _, order := range current {
+		if _, ok := currentByKey[order.ShopKey()]; ok {
+			retiredIDs = append(retiredIDs, order.ID)
+		}
+	}
+
+	return inserts, retiredIDs, len(keys)
 }
@@ -0,0 +1,107 @@
+package poller
+
+import (
+	"cutlass_analytics/internal/fetcher"
+	"cutlass_analytics/internal/models"
+	"cutlass_analytics/internal/testutil"
+	"cutlass_analytics/internal/types"
+	"testing"
+)
+
+// TestCSVPollerRunReplay imports the Emerald market CSV recorded in testdata/pages twice.
+// Meridian has no recorded CSV and must be skipped without failing the run.
+func TestCSVPollerRunReplay(t *testing.T) {
+	db := testutil.DB(t)
+	p := NewCSVPoller(db, fetcher.NewReplayFetcher("testdata/pages"), []types.Ocean{types.OceanEmerald, types.OceanMeridian})
+
+	for run := 1; run <= 2; run++ {
+		if err := p.Run(); err != nil {
+			t.Fatalf("run %d: Run() error = %v", run, err)
+		}
+	}
+
+	var snapshots []models.MarketSnapshot
+	db.Order("id").Find(&snapshots)
+	if len(snapshots) != 2 {
+		t.Fatalf("stored %d snapshots, want one per run", len(snapshots))
+	}
+	for _, snapshot := range snapshots {
+		if snapshot.Ocean != types.OceanEmerald || snapshot.OrderCount != 3 {
+			t.Errorf("snapshot %d = %s with %d orders, want emerald with 3", snapshot.ID, snapshot.Ocean, snapshot.OrderCount)
+		}
+	}
+	// The second import finds the same offers, so nothing changes
+	if snapshots[0].OrdersInserted != 3 || snapshots[1].OrdersInserted != 0 || snapshots[1].OrdersRetired != 0 {
+		t.Errorf("inserted %d then %d, retired %d, want 3 then 0, 0",
+			snapshots[0].OrdersInserted, snapshots[1].OrdersInserted, snapshots[1].OrdersRetired)
+	}
+
+	var current int64
+	db.Model(&models.MarketOrder{}).Where("ocean = ? AND retired_at IS NULL", types.OceanEmerald).Count(&current)
+	if current != 3 {
+		t.Errorf("current order book has %d orders, want 3", current)
+	}
+}
+
+// TestCSVPollerReprocess imports the recorded Emerald CSV twice, then rebuilds the market history
+// from the archived copies. The rebuilt snapshots must replace the originals and match them.
+func TestCSVPollerReprocess(t *testing.T) {
+	db := testutil.DB(t)
+	p := NewCSVPoller(db, fetcher.NewReplayFetcher("testdata/pages"), []types.Ocean{types.OceanEmerald})
+
+	for run := 1; run <= 2; run++ {
+		if err := p.Run(); err != nil {
+			t.Fatalf("run %d: Run() error = %v", run, err)
+		}
+	}
+
+	var original []models.MarketSnapshot
+	db.Order("id").Find(&original)
+	var pages []models.ArchivedPage
+	db.Where("url = ?", getBuySellURL(types.OceanEmerald)).Order("id").Find(&pages)
+	if len(pages) != 2 {
+		t.Fatalf("archived %d CSVs, want one per run", len(pages))
+	}
+	for i, page := range pages {
+		if page.ScrapeJobID != nil || page.MarketSnapshotID == nil || *page.MarketSnapshotID != original[i].ID {
+			t.Errorf("CSV %d archived for job %v and snapshot %v, want no job and snapshot %d",
+				page.ID, page.ScrapeJobID, page.MarketSnapshotID, original[i].ID)
+		}
+	}
+
+	// Reprocessing reads the archive only, so it needs no fetcher
+	reprocessor := NewCSVPoller(db, nil, []types.Ocean{types.OceanEmerald})
+	if err := reprocessor.Reprocess(types.OceanEmerald, original[0].ImportedAt); err != nil {
+		t.Fatalf("Reprocess() error = %v", err)
+	}
+
+	var rebuilt []models.MarketSnapshot
+	db.Order("id").Find(&rebuilt)
+	if len(rebuilt) != 2 {
+		t.Fatalf("%d snapshots after reprocessing, want 2", len(rebuilt))
+	}
+	for i, snapshot := range rebuilt {
+		if snapshot.ID <= original[1].ID {
+			t.Errorf("snapshot %d was kept, want it replaced", snapshot.ID)
+		}
+		if !snapshot.ImportedAt.Equal(original[i].ImportedAt) || snapshot.OrderCount != original[i].OrderCount ||
+			snapshot.OrdersInserted != original[i].OrdersInserted || snapshot.OrdersRetired != original[i].OrdersRetired {
+			t.Errorf("rebuilt snapshot %d = %+v, want it to match %+v", i, snapshot, original[i])
+		}
+	}
+
+	db.Where("url = ?", getBuySellURL(types.OceanEmerald)).Order("id").Find(&pages)
+	for i, page := range pages {
+		if page.MarketSnapshotID == nil || *page.MarketSnapshotID != rebuilt[i].ID {
+			t.Errorf("CSV %d linked to snapshot %v, want %d", page.ID, page.MarketSnapshotID, rebuilt[i].ID)
+		}
+	}
+
+	var orders int64
+	db.Model(&models.MarketOrder{}).Where("ocean = ?", types.OceanEmerald).Count(&orders)
+	var current int64
+	db.Model(&models.MarketOrder{}).Where("ocean = ? AND retired_at IS NULL", types.OceanEmerald).Count(&current)
+	if orders != 3 || current != 3 {
+		t.Errorf("%d orders with %d current after reprocessing, want 3 and 3", orders, current)
+	}
+}
@@ -1,6 +1,7 @@
 package poller
 
 import (
+	"cutlass_analytics/internal/fetcher"
 	"cutlass_analytics/internal/models"
 	"cutlass_analytics/internal/types"
 	"strings"
@@ -267,7 +268,7 @@ func TestGetBuySellURL(t *testing.T) {
 
 func TestNewCSVPoller(t *testing.T) {
 	oceans := []types.Ocean{types.OceanEmerald, types.OceanMeridian}
-	p := NewCSVPoller(nil, oceans)
+	p := NewCSVPoller(nil, fetcher.NewReplayFetcher(t.TempDir()), oceans)
 
 	if p == nil {
 		t.Fatal("expected non-nil poller")
@@ -275,7 +276,81 @@ func TestNewCSVPoller(t *testing.T) {
 	if len(p.oceans) != 2 {
 		t.Errorf("expected 2 oceans, got %d", len(p.oceans))
 	}
-	if p.client == nil {
-		t.Error("expected non-nil HTTP client")
+	if p.fetcher == nil {
+		t.Error("expected non-nil fetcher")
+	}
+}
+
+func TestDiffOrders(t *testing.T) {
+	ocean := types.OceanEmerald
+	order := func(id uint, shop string, buyPrice, sellPrice int) models.MarketOrder {
+		o := models.MarketOrder{
+			Ocean:         ocean,
+			IslandName:    "Maia-Insel",
+			CommodityName: "Iron",
+			ShopName:      shop,
+			BuyPrice:      buyPrice,
+			BuyQuantity:   100,
+			SellPrice:     sellPrice,
+			SellQuantity:  50,
+		}
+		o.ID = id
+		o.DataHash = o.ComputeHash()
+		return o
+	}
+
+	current := []models.MarketOrder{
+		order(1, "Unchanged Shop", 10, 20),
+		order(2, "Changed Shop", 10, 20),
+		order(3, "Closed Shop", 10, 20),
+	}
+	incoming := []models.MarketOrder{
+		order(0, "Unchanged Shop", 10, 20),
+		order(0, "Changed Shop", 11, 20),
+		order(0, "New Shop", 9, 25),
+	}
+
+	inserts, retiredIDs, total := diffOrders(current, incoming)
+
+	if total != 3 {
+		t.Errorf("total = %d, want 3", total)
+	}
+	if len(inserts) != 2 {
+		t.Fatalf("expected 2 inserts, got %d", len(inserts))
+	}
+	if inserts[0].ShopName != "Changed Shop" || inserts[1].ShopName != "New Shop" {
+		t.Errorf("unexpected inserts: %s, %s", inserts[0].ShopName, inserts[1].ShopName)
+	}
+	for _, o := range inserts {
+		if o.DataHash == "" {
+			t.Errorf("insert for %s is missing a data hash", o.ShopName)
+		}
+	}
+
+	retired := map[uint]bool{}
+	for _, id := range retiredIDs {
+		retired[id] = true
+	}
+	if len(retiredIDs) != 2 || !retired[2] || !retired[3] {
+		t.Errorf("retiredIDs = %v, want [2 3]", retiredIDs)
+	}
+}
+
+func TestDiffOrders_DuplicateShopRows(t *testing.T) {
+	incoming := []models.MarketOrder{
+		{Ocean: types.OceanEmerald, IslandName: "Maia-Insel", CommodityName: "Iron", ShopName: "Shop", BuyPrice: 10},
+		{Ocean: types.OceanEmerald, IslandName: "Maia-Insel", CommodityName: "Iron", ShopName: "Shop", BuyPrice: 12},
+	}
+
+	inserts, retiredIDs, total := diffOrders(nil, incoming)
+
+	if total != 1 || len(inserts) != 1 {
+		t.Fatalf("expected a single offer, got total=%d inserts=%d", total, len(inserts))
+	}
+	if inserts[0].BuyPrice != 12 {
+		t.Errorf("BuyPrice = %d, want the last listed row (12)", inserts[0].BuyPrice)
+	}
+	if len(retiredIDs) != 0 {
+		t.Errorf("expected no retired orders, got %v", retiredIDs)
 	}
 }
@@ -0,0 +1,125 @@
+package poller
+
+import (
+	"bytes"
+	"cutlass_analytics/internal/archive"
+	"cutlass_analytics/internal/models"
+	"cutlass_analytics/internal/types"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Reprocess rebuilds an ocean's market history from the CSVs archived since since, without any
+// network access, so the order book can be regenerated after a parser fix. The snapshots imported
+// since then are replaced: the orders they inserted are deleted, the orders they retired are
+// restored, and every archived CSV is parsed and imported again in the order it was fetched.
+// Downsampled snapshots no longer have their orders, so since must come after them. The rebuild
+// runs in one transaction and should not overlap with a poll of the same ocean.
+func (p *CSVPoller) Reprocess(ocean types.Ocean, since time.Time) error {
+	pages, err := archive.LoadSince(p.db, getBuySellURL(ocean), since)
+	if err != nil {
+		return fmt.Errorf("failed to load archived CSVs: %w", err)
+	}
+	if len(pages) == 0 {
+		return fmt.Errorf("no %s market CSVs archived since %s", ocean, since.Format(time.RFC3339))
+	}
+
+	var snapshots []models.MarketSnapshot
+	if err := p.db.Where("ocean = ? AND imported_at >= ?", ocean, since).
+		Order("imported_at ASC").
+		Find(&snapshots).Error; err != nil {
+		return fmt.Errorf("failed to load snapshots: %w", err)
+	}
+
+	// Every snapshot being replaced must be rebuilt from its own CSV
+	archived := make(map[uint]bool, len(pages))
+	for _, page := range pages {
+		if page.MarketSnapshotID != nil {
+			archived[*page.MarketSnapshotID] = true
+		}
+	}
+	snapshotIDs := make([]uint, len(snapshots))
+	for i, snapshot := range snapshots {
+		if snapshot.IsDownsampled {
+			return fmt.Errorf("snapshot %d was already downsampled", snapshot.ID)
+		}
+		if !archived[snapshot.ID] {
+			return fmt.Errorf("snapshot %d has no archived CSV", snapshot.ID)
+		}
+		snapshotIDs[i] = snapshot.ID
+	}
+
+	rebuilt := 0
+	err = p.db.Transaction(func(tx *gorm.DB) error {
+		if err := p.discardSnapshots(tx, snapshotIDs); err != nil {
+			return err
+		}
+
+		replay := &CSVPoller{db: tx, oceans: []types.Ocean{ocean}}
+		for i := range pages {
+			page := &pages[i]
+			body, err := page.Content()
+			if err != nil {
+				return fmt.Errorf("failed to decompress archived CSV %d: %w", page.ID, err)
+			}
+
+			orders, err := replay.parseCSV(bytes.NewReader(body), ocean, page.FetchedAt)
+			if err != nil {
+				return fmt.Errorf("failed to parse archived CSV %d: %w", page.ID, err)
+			}
+			if len(orders) == 0 {
+				continue
+			}
+
+			if _, err := replay.importOrders(ocean, orders, page.FetchedAt, page); err != nil {
+				return fmt.Errorf("failed to import archived CSV %d: %w", page.ID, err)
+			}
+			rebuilt++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("CSV poller: Replaced %d %s snapshots with %d rebuilt from %d archived CSVs",
+		len(snapshots), ocean, rebuilt, len(pages))
+	return nil
+}
+
+// discardSnapshots undoes the given snapshots, which must be an ocean's latest: the orders they
+// inserted are deleted and the orders they retired return to the order book
+func (p *CSVPoller) discardSnapshots(tx *gorm.DB, snapshotIDs []uint) error {
+	if len(snapshotIDs) == 0 {
+		return nil
+	}
+
+	if err := tx.Unscoped().Where("snapshot_id IN ?", snapshotIDs).
+		Delete(&models.MarketOrder{}).Error; err != nil {
+		return fmt.Errorf("failed to delete orders: %w", err)
+	}
+
+	if err := tx.Model(&models.MarketOrder{}).
+		Where("retired_snapshot_id IN ?", snapshotIDs).
+		Updates(map[string]interface{}{
+			"retired_at":          nil,
+			"retired_snapshot_id": nil,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to restore retired orders: %w", err)
+	}
+
+	if err := tx.Model(&models.ArchivedPage{}).
+		Where("market_snapshot_id IN ?", snapshotIDs).
+		Update("market_snapshot_id", nil).Error; err != nil {
+		return fmt.Errorf("failed to unlink archived CSVs: %w", err)
+	}
+
+	if err := tx.Unscoped().Where("id IN ?", snapshotIDs).
+		Delete(&models.MarketSnapshot{}).Error; err != nil {
+		return fmt.Errorf("failed to delete snapshots: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package poller
+
+import (
+	"cutlass_analytics/internal/models"
+	"cutlass_analytics/internal/types"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MarketOrderRetention is how long full order snapshots are kept before being
+// downsampled into daily market_prices rows
+const MarketOrderRetention = 7 * 24 * time.Hour
+
+// ApplyRetention downsamples snapshots imported before cutoff into market_prices,
+// keeping the last snapshot of each day per ocean, then deletes order rows that
+// are no longer part of any retained snapshot
+func (p *CSVPoller) ApplyRetention(cutoff time.Time) error {
+	var snapshots []models.MarketSnapshot
+	if err := p.db.Where("imported_at < ? AND is_downsampled = ?", cutoff, false).
+		Order("imported_at ASC").
+		Find(&snapshots).Error; err != nil {
+		return fmt.Errorf("failed to load snapshots: %w", err)
+	}
+
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	// Group snapshots by ocean and day; the last snapshot of the day represents it
+	type dayKey struct {
+		ocean types.Ocean
+		day   string
+	}
+	groups := make(map[dayKey][]models.MarketSnapshot)
+	var order []dayKey
+	for _, snapshot := range snapshots {
+		key := dayKey{ocean: snapshot.Ocean, day: snapshot.ImportedAt.UTC().Format("2006-01-02")}
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], snapshot)
+	}
+
+	priceRows := 0
+	for _, key := range order {
+		group := groups[key]
+		representative := group[len(group)-1]
+
+		err := p.db.Transaction(func(tx *gorm.DB) error {
+			count, err := p.downsampleSnapshot(tx, representative)
+			if err != nil {
+				return err
+			}
+			priceRows += count
+
+			ids := make([]uint, len(group))
+			for i, snapshot := range group {
+				ids[i] = snapshot.ID
+			}
+			return tx.Model(&models.MarketSnapshot{}).
+				Where("id IN ?", ids).
+				Update("is_downsampled", true).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to downsample %s snapshots for %s: %w", key.ocean, key.day, err)
+		}
+	}
+
+	// Orders retired before the cutoff only belong to snapshots that are now downsampled
+	result := p.db.Unscoped().
+		Where("retired_at IS NOT NULL AND retired_at < ?", cutoff).
+		Delete(&models.MarketOrder{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete retired orders: %w", result.Error)
+	}
+
+	log.Printf("CSV poller: Downsampled %d snapshots into %d market prices, deleted %d retired orders",
+		len(snapshots), priceRows, result.RowsAffected)
+	return nil
+}
+
+// downsampleSnapshot writes one market_prices row per island and commodity for the
+// order book as it stood at the given snapshot
+func (p *CSVPoller) downsampleSnapshot(tx *gorm.DB, snapshot models.MarketSnapshot) (int, error) {
+	var orders []models.MarketOrder
+	if err := tx.Where("ocean = ? AND snapshot_id <= ?", snapshot.Ocean, snapshot.ID).
+		Where("retired_snapshot_id IS NULL OR retired_snapshot_id > ?", snapshot.ID).
+		Find(&orders).Error; err != nil {
+		return 0, fmt.Errorf("failed to load orders for snapshot %d: %w", snapshot.ID, err)
+	}
+
+	summaries := models.SummarizeMarketOrders(orders, snapshot.ImportedAt)
+	if len(summaries) == 0 {
+		return 0, nil
+	}
+
+	var islands []models.Island
+	if err := tx.Where("ocean = ?", snapshot.Ocean).Find(&islands).Error; err != nil {
+		return 0, fmt.Errorf("failed to load islands: %w", err)
+	}
+	islandIDs := make(map[string]uint, len(islands))
+	for _, island := range islands {
+		islandIDs[island.Name] = island.ID
+	}
+
+	commodityIDs := make(map[string]uint)
+	prices := make([]models.MarketPrice, 0, len(summaries))
+	for _, summary := range summaries {
+		if !summary.HasBuyOffer() && !summary.HasSellOffer() {
+			continue
+		}
+
+		islandID, ok := islandIDs[summary.Island.Name]
+		if !ok {
+			// Island has not been scraped yet; nothing to attach the price to
+			continue
+		}
+
+		commodityName := summary.Commodity.Name
+		commodityID, ok := commodityIDs[commodityName]
+		if !ok {
+			var commodity models.Commodity
+			if err := tx.Where("name = ?", commodityName).
+				FirstOrCreate(&commodity, models.Commodity{
+					Name:        commodityName,
+					DisplayName: commodityName,
+					Category:    types.ClassifyCommodity(commodityName),
+				}).Error; err != nil {
+				return 0, fmt.Errorf("failed to get/create commodity: %w", err)
+			}
+			commodityID = commodity.ID
+			commodityIDs[commodityName] = commodityID
+		}
+
+		prices = append(prices, models.MarketPrice{
+			IslandID:     islandID,
+			CommodityID:  commodityID,
+			ScrapedAt:    snapshot.ImportedAt,
+			BuyPrice:     summary.BuyPrice,
+			BuyQuantity:  summary.BuyQuantity,
+			SellPrice:    summary.SellPrice,
+			SellQuantity: summary.SellQuantity,
+		})
+	}
+
+	if len(prices) == 0 {
+		return 0, nil
+	}
+
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(&prices, 100).Error; err != nil {
+		return 0, fmt.Errorf("failed to insert market prices: %w", err)
+	}
+
+	return len(prices), nil
+}
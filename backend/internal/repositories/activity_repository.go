@@ -0,0 +1,376 @@
+package repositories
+
+import (
+	"cutlass_analytics/internal/models"
+	"cutlass_analytics/internal/types"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ActivityRepository struct {
+	db *gorm.DB
+}
+
+func NewActivityRepository(db *gorm.DB) *ActivityRepository {
+	return &ActivityRepository{db: db}
+}
+
+// RankChange is a crew moving to another crew rank between two consecutive battle records
+type RankChange struct {
+	CrewID    uint
+	ScrapedAt time.Time
+	FromRank  types.CrewRank
+	ToRank    types.CrewRank
+}
+
+func (c *RankChange) IsPromotion() bool {
+	return c.ToRank.Order() > c.FromRank.Order()
+}
+
+const (
+	RankChangesAll        = ""
+	RankChangesPromotions = "promotions"
+	RankChangesDemotions  = "demotions"
+)
+
+// rankChangesQuery pairs every battle record scraped in [@start, @end) with the crew's previous
+// record, including the last one before @start, and maps both ranks to their ordinals.
+// The filter selecting the crews is filled in by the caller.
+const rankChangesQuery = `
+	WITH records AS (
+		SELECT r.crew_id, r.scraped_at, r.crew_rank
+		FROM crew_battle_records r
+		JOIN crews c ON c.id = r.crew_id AND c.deleted_at IS NULL
+		WHERE r.deleted_at IS NULL AND %[1]s AND r.scraped_at >= @start AND r.scraped_at < @end
+		UNION ALL
+		SELECT * FROM (
+			SELECT DISTINCT ON (r.crew_id) r.crew_id, r.scraped_at, r.crew_rank
+			FROM crew_battle_records r
+			JOIN crews c ON c.id = r.crew_id AND c.deleted_at IS NULL
+			WHERE r.deleted_at IS NULL AND %[1]s AND r.scraped_at < @start
+			ORDER BY r.crew_id, r.scraped_at DESC
+		) baseline
+	),
+	transitions AS (
+		SELECT crew_id, scraped_at, crew_rank AS to_rank,
+			LAG(crew_rank) OVER (PARTITION BY crew_id ORDER BY scraped_at) AS from_rank
+		FROM records
+	)
+	SELECT transitions.*, %[2]s AS from_order, %[3]s AS to_order
+	FROM transitions
+`
+
+// GetRankChanges returns the crew rank changes of an ocean's crews in [startDate, endDate),
+// most recent first. direction is one of RankChangesAll, RankChangesPromotions or RankChangesDemotions.
+func (r *ActivityRepository) GetRankChanges(ocean types.Ocean, startDate, endDate time.Time, direction string, offset, limit int) ([]RankChange, int64, error) {
+	changes := r.rankChanges("c.ocean = @ocean", map[string]interface{}{
+		"ocean": ocean,
+		"start": startDate,
+		"end":   endDate,
+	}, direction)
+
+	// Count total before pagination
+	var total int64
+	if err := changes.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var results []RankChange
+	err := changes.Order("scraped_at DESC, crew_id ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&results).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return results, total, nil
+}
+
+// GetCrewRankChanges returns a crew's rank changes in [startDate, endDate), oldest first
+func (r *ActivityRepository) GetCrewRankChanges(crewID uint, startDate, endDate time.Time, direction string) ([]RankChange, error) {
+	var results []RankChange
+	err := r.rankChanges("r.crew_id = @crew_id", map[string]interface{}{
+		"crew_id": crewID,
+		"start":   startDate,
+		"end":     endDate,
+	}, direction).
+		Order("scraped_at ASC").
+		Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// rankChanges selects the transitions between two known, different crew ranks
+func (r *ActivityRepository) rankChanges(filter string, args map[string]interface{}, direction string) *gorm.DB {
+	query := fmt.Sprintf(rankChangesQuery, filter, crewRankOrderSQL("from_rank"), crewRankOrderSQL("to_rank"))
+	changes := r.db.Table("(?) AS changes", r.db.Raw(query, args)).
+		Where("scraped_at >= ? AND from_order > 0 AND to_order > 0 AND from_order <> to_order", args["start"])
+
+	switch direction {
+	case RankChangesPromotions:
+		changes = changes.Where("to_order > from_order")
+	case RankChangesDemotions:
+		changes = changes.Where("to_order < from_order")
+	}
+	return changes
+}
+
+// DailyActivityTotals sums an ocean's battle records scraped on one UTC day.
+// ActiveCrews counts the crews that fought at least one PvP battle that day.
+type DailyActivityTotals struct {
+	Date        time.Time
+	TotalWins   int
+	TotalLosses int
+	ActiveCrews int
+}
+
+// DailyLifecycleCounts counts the crews, flags and island governors that changed on one UTC day
+type DailyLifecycleCounts struct {
+	Date              time.Time
+	NewCrews          int
+	VanishedCrews     int
+	NewFlags          int
+	VanishedFlags     int
+	GovernanceChanges int
+}
+
+// CrewPVPChange is the PvP wins and losses a crew gained over a range and its crew rank
+// before and after. RankBefore comes from the crew's last record before the range, or its
+// first record in the range if it has none.
+type CrewPVPChange struct {
+	CrewID       uint
+	ScrapedAt    time.Time
+	WinsGained   int
+	LossesGained int
+	RankBefore   types.CrewRank
+	RankAfter    types.CrewRank
+}
+
+func (c *CrewPVPChange) RankChanged() bool {
+	return c.RankBefore != c.RankAfter
+}
+
+// GovernanceChange is an island passing from one governor to another
+type GovernanceChange struct {
+	IslandID             uint
+	FlagID               *uint
+	GovernorName         string
+	StartedAt            time.Time
+	PreviousFlagID       *uint
+	PreviousGovernorName string
+}
+
+// governanceChangesQuery pairs every governance record of an ocean's islands started in
+// [@start, @end) with the island's previous record. An island's first record is when it was
+// first scraped, not a change of governor, so it is left out.
+const governanceChangesQuery = `
+	SELECT * FROM (
+		SELECT g.island_id, g.flag_id, g.governor_name, g.started_at,
+			LAG(g.id) OVER w AS previous_id,
+			LAG(g.flag_id) OVER w AS previous_flag_id,
+			LAG(g.governor_name) OVER w AS previous_governor_name
+		FROM island_governance_history g
+		JOIN islands i ON i.id = g.island_id AND i.deleted_at IS NULL
+		WHERE g.deleted_at IS NULL AND i.ocean = @ocean AND g.started_at < @end
+		WINDOW w AS (PARTITION BY g.island_id ORDER BY g.started_at, g.id)
+	) history
+	WHERE previous_id IS NOT NULL AND started_at >= @start
+`
+
+// crewPVPChangesQuery sums the PvP gains of an ocean's crews over the battle records scraped
+// in [@start, @end) and takes their rank from the last record before @start and the last
+// one in the range. Crews without records in the range are left out.
+const crewPVPChangesQuery = `
+	WITH records AS (
+		SELECT r.crew_id, r.scraped_at, r.crew_rank, r.daily_pvp_wins, r.daily_pvp_losses
+		FROM crew_battle_records r
+		JOIN crews c ON c.id = r.crew_id AND c.deleted_at IS NULL
+		WHERE r.deleted_at IS NULL AND c.ocean = @ocean AND r.scraped_at >= @start AND r.scraped_at < @end
+		UNION ALL
+		SELECT * FROM (
+			SELECT DISTINCT ON (r.crew_id) r.crew_id, r.scraped_at, r.crew_rank, 0, 0
+			FROM crew_battle_records r
+			JOIN crews c ON c.id = r.crew_id AND c.deleted_at IS NULL
+			WHERE r.deleted_at IS NULL AND c.ocean = @ocean AND r.scraped_at < @start
+			ORDER BY r.crew_id, r.scraped_at DESC
+		) baseline
+	)
+	SELECT crew_id, MAX(scraped_at) AS scraped_at,
+		SUM(daily_pvp_wins) AS wins_gained,
+		SUM(daily_pvp_losses) AS losses_gained,
+		(ARRAY_AGG(crew_rank ORDER BY scraped_at ASC))[1] AS rank_before,
+		(ARRAY_AGG(crew_rank ORDER BY scraped_at DESC))[1] AS rank_after
+	FROM records
+	GROUP BY crew_id
+	HAVING MAX(scraped_at) >= @start
+`
+
+// lifecycleCountsQuery counts per UTC day the crews and flags first seen, the inactive crews
+// and flags last seen, and the governance changes of an ocean in [@start, @end)
+const lifecycleCountsQuery = `
+	WITH events AS (
+		SELECT date_trunc('day', first_seen_at) AS date, 'new_crew' AS kind
+		FROM crews
+		WHERE deleted_at IS NULL AND ocean = @ocean AND first_seen_at >= @start AND first_seen_at < @end
+		UNION ALL
+		SELECT date_trunc('day', last_seen_at), 'vanished_crew'
+		FROM crews
+		WHERE deleted_at IS NULL AND ocean = @ocean AND NOT is_active AND last_seen_at >= @start AND last_seen_at < @end
+		UNION ALL
+		SELECT date_trunc('day', first_seen_at), 'new_flag'
+		FROM flags
+		WHERE deleted_at IS NULL AND ocean = @ocean AND first_seen_at >= @start AND first_seen_at < @end
+		UNION ALL
+		SELECT date_trunc('day', last_seen_at), 'vanished_flag'
+		FROM flags
+		WHERE deleted_at IS NULL AND ocean = @ocean AND NOT is_active AND last_seen_at >= @start AND last_seen_at < @end
+		UNION ALL
+		SELECT date_trunc('day', started_at), 'governance_change'
+		FROM (` + governanceChangesQuery + `) changes
+	)
+	SELECT date,
+		COUNT(*) FILTER (WHERE kind = 'new_crew') AS new_crews,
+		COUNT(*) FILTER (WHERE kind = 'vanished_crew') AS vanished_crews,
+		COUNT(*) FILTER (WHERE kind = 'new_flag') AS new_flags,
+		COUNT(*) FILTER (WHERE kind = 'vanished_flag') AS vanished_flags,
+		COUNT(*) FILTER (WHERE kind = 'governance_change') AS governance_changes
+	FROM events
+	GROUP BY date
+	ORDER BY date
+`
+
+// GetDailyTotals returns an ocean's PvP activity for each UTC day with battle records in [startDate, endDate)
+func (r *ActivityRepository) GetDailyTotals(ocean types.Ocean, startDate, endDate time.Time) ([]DailyActivityTotals, error) {
+	var totals []DailyActivityTotals
+	err := r.oceanBattleRecords(ocean, startDate, endDate).
+		Select("date_trunc('day', r.scraped_at) AS date, " +
+			"SUM(r.daily_pvp_wins) AS total_wins, SUM(r.daily_pvp_losses) AS total_losses, " +
+			"COUNT(DISTINCT r.crew_id) FILTER (WHERE r.daily_pvp_wins + r.daily_pvp_losses > 0) AS active_crews").
+		Group("date_trunc('day', r.scraped_at)").
+		Order("date ASC").
+		Find(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+// GetDailyLifecycleCounts returns an ocean's new and vanished crews and flags and its
+// governance changes for each UTC day with any of them in [startDate, endDate)
+func (r *ActivityRepository) GetDailyLifecycleCounts(ocean types.Ocean, startDate, endDate time.Time) ([]DailyLifecycleCounts, error) {
+	var counts []DailyLifecycleCounts
+	err := r.db.Raw(lifecycleCountsQuery, map[string]interface{}{
+		"ocean": ocean,
+		"start": startDate,
+		"end":   endDate,
+	}).Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// GetCrewPVPChanges returns the PvP changes of an ocean's crews in [startDate, endDate), most
+// battles first. Crews that neither fought nor changed rank, or gained fewer wins or losses
+// than the minimums, are left out.
+func (r *ActivityRepository) GetCrewPVPChanges(ocean types.Ocean, startDate, endDate time.Time, minWins, minLosses, offset, limit int) ([]CrewPVPChange, int64, error) {
+	changes := r.db.Table("(?) AS changes", r.db.Raw(crewPVPChangesQuery, map[string]interface{}{
+		"ocean": ocean,
+		"start": startDate,
+		"end":   endDate,
+	})).
+		Where("(wins_gained + losses_gained > 0 OR rank_before <> rank_after) AND wins_gained >= ? AND losses_gained >= ?", minWins, minLosses)
+
+	// Count total before pagination
+	var total int64
+	if err := changes.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var results []CrewPVPChange
+	err := changes.Order("wins_gained + losses_gained DESC, wins_gained DESC, crew_id ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&results).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return results, total, nil
+}
+
+// GetNewCrews returns the crews of an ocean first seen in [startDate, endDate)
+func (r *ActivityRepository) GetNewCrews(ocean types.Ocean, startDate, endDate time.Time) ([]models.Crew, error) {
+	var crews []models.Crew
+	err := r.db.Preload("Flag").
+		Where("ocean = ? AND first_seen_at >= ? AND first_seen_at < ?", ocean, startDate, endDate).
+		Order("first_seen_at ASC, id ASC").
+		Find(&crews).Error
+	if err != nil {
+		return nil, err
+	}
+	return crews, nil
+}
+
+// GetVanishedCrews returns the inactive crews of an ocean last seen in [startDate, endDate)
+func (r *ActivityRepository) GetVanishedCrews(ocean types.Ocean, startDate, endDate time.Time) ([]models.Crew, error) {
+	var crews []models.Crew
+	err := r.db.Preload("Flag").
+		Where("ocean = ? AND is_active = ? AND last_seen_at >= ? AND last_seen_at < ?", ocean, false, startDate, endDate).
+		Order("last_seen_at ASC, id ASC").
+		Find(&crews).Error
+	if err != nil {
+		return nil, err
+	}
+	return crews, nil
+}
+
+// GetNewFlags returns the flags of an ocean first seen in [startDate, endDate)
+func (r *ActivityRepository) GetNewFlags(ocean types.Ocean, startDate, endDate time.Time) ([]models.Flag, error) {
+	var flags []models.Flag
+	err := r.db.Where("ocean = ? AND first_seen_at >= ? AND first_seen_at < ?", ocean, startDate, endDate).
+		Order("first_seen_at ASC, id ASC").
+		Find(&flags).Error
+	if err != nil {
+		return nil, err
+	}
+	return flags, nil
+}
+
+// GetVanishedFlags returns the inactive flags of an ocean last seen in [startDate, endDate)
+func (r *ActivityRepository) GetVanishedFlags(ocean types.Ocean, startDate, endDate time.Time) ([]models.Flag, error) {
+	var flags []models.Flag
+	err := r.db.Where("ocean = ? AND is_active = ? AND last_seen_at >= ? AND last_seen_at < ?", ocean, false, startDate, endDate).
+		Order("last_seen_at ASC, id ASC").
+		Find(&flags).Error
+	if err != nil {
+		return nil, err
+	}
+	return flags, nil
+}
+
+// GetGovernanceChanges returns the governor changes of an ocean's islands in [startDate, endDate), oldest first
+func (r *ActivityRepository) GetGovernanceChanges(ocean types.Ocean, startDate, endDate time.Time) ([]GovernanceChange, error) {
+	var changes []GovernanceChange
+	err := r.db.Table("(?) AS changes", r.db.Raw(governanceChangesQuery, map[string]interface{}{
+		"ocean": ocean,
+		"start": startDate,
+		"end":   endDate,
+	})).
+		Order("started_at ASC, island_id ASC").
+		Find(&changes).Error
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// oceanBattleRecords selects the battle records of an ocean's crews scraped in [startDate, endDate)
+func (r *ActivityRepository) oceanBattleRecords(ocean types.Ocean, startDate, endDate time.Time) *gorm.DB {
+	return r.db.Table("crew_battle_records r").
+		Joins("JOIN crews c ON c.id = r.crew_id AND c.deleted_at IS NULL").
+		Where("r.deleted_at IS NULL AND c.ocean = ? AND r.scraped_at >= ? AND r.scraped_at < ?", ocean, startDate, endDate)
+}
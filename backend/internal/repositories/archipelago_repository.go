@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"cutlass_analytics/internal/models"
+	"cutlass_analytics/internal/types"
+
+	"gorm.io/gorm"
+)
+
+type ArchipelagoRepository struct {
+	db *gorm.DB
+}
+
+func NewArchipelagoRepository(db *gorm.DB) *ArchipelagoRepository {
+	return &ArchipelagoRepository{db: db}
+}
+
+func (r *ArchipelagoRepository) FindByID(id uint) (*models.Archipelago, error) {
+	var archipelago models.Archipelago
+	err := r.db.First(&archipelago, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &archipelago, nil
+}
+
+func (r *ArchipelagoRepository) List(ocean types.Ocean) ([]models.Archipelago, error) {
+	var archipelagos []models.Archipelago
+	err := r.db.Where("ocean = ?", ocean).
+		Order("name ASC").
+		Find(&archipelagos).Error
+	if err != nil {
+		return nil, err
+	}
+	return archipelagos, nil
+}
+
+// GetIslands returns the islands of the given archipelagos with their governor flags, by name
+func (r *ArchipelagoRepository) GetIslands(archipelagoIDs []uint) ([]models.Island, error) {
+	var islands []models.Island
+	if len(archipelagoIDs) == 0 {
+		return islands, nil
+	}
+	err := r.db.Preload("Archipelago").Preload("GovernorFlag").
+		Where("archipelago_id IN ?", archipelagoIDs).
+		Order("name ASC").
+		Find(&islands).Error
+	if err != nil {
+		return nil, err
+	}
+	return islands, nil
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"cutlass_analytics/internal/dto"
+	"cutlass_analytics/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CommodityRepository struct {
+	db *gorm.DB
+}
+
+func NewCommodityRepository(db *gorm.DB) *CommodityRepository {
+	return &CommodityRepository{db: db}
+}
+
+func (r *CommodityRepository) FindByID(id uint) (*models.Commodity, error) {
+	var commodity models.Commodity
+	err := r.db.First(&commodity, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &commodity, nil
+}
+
+func (r *CommodityRepository) FindByNames(names []string) ([]models.Commodity, error) {
+	var commodities []models.Commodity
+	if len(names) == 0 {
+		return commodities, nil
+	}
+	err := r.db.Where("name IN ?", names).
+		Find(&commodities).Error
+	if err != nil {
+		return nil, err
+	}
+	return commodities, nil
+}
+
+func (r *CommodityRepository) List(req dto.CommodityListRequest) ([]models.Commodity, error) {
+	query := r.db.Model(&models.Commodity{})
+
+	// Apply filters
+	if req.Category != "" {
+		query = query.Where("category = ?", req.Category)
+	}
+	if req.IsSpawnable != nil {
+		query = query.Where("is_spawnable = ?", *req.IsSpawnable)
+	}
+	if req.IsRare != nil {
+		query = query.Where("is_rare = ?", *req.IsRare)
+	}
+
+	var commodities []models.Commodity
+	if err := query.Order("category ASC, name ASC").Find(&commodities).Error; err != nil {
+		return nil, err
+	}
+	return commodities, nil
+}
+
+// Search matches commodities whose name or display name contains the query, case-insensitively.
+// Names starting with the query are listed first.
+func (r *CommodityRepository) Search(q string, limit int) ([]models.Commodity, error) {
+	pattern := "%" + escapeLike(q) + "%"
+	prefix := escapeLike(q) + "%"
+
+	var commodities []models.Commodity
+	err := r.db.Where("name ILIKE ? OR display_name ILIKE ?", pattern, pattern).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "CASE WHEN name ILIKE ? OR display_name ILIKE ? THEN 0 ELSE 1 END, name ASC",
+			Vars:               []interface{}{prefix, prefix},
+			WithoutParentheses: true,
+		}}).
+		Limit(limit).
+		Find(&commodities).Error
+	if err != nil {
+		return nil, err
+	}
+	return commodities, nil
+}
+
+// GetSpawnIslands returns the islands a commodity spawns on, across all oceans
+func (r *CommodityRepository) GetSpawnIslands(commodityID uint) ([]models.Island, error) {
+	var islands []models.Island
+	err := r.db.Joins("JOIN island_commodities ON island_commodities.island_id = islands.id AND island_commodities.deleted_at IS NULL").
+		Where("island_commodities.commodity_id = ?", commodityID).
+		Order("islands.ocean ASC, islands.name ASC").
+		Find(&islands).Error
+	if err != nil {
+		return nil, err
+	}
+	return islands, nil
+}
@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"cutlass_analytics/internal/types"
+	"fmt"
 	"strings"
 
 	"gorm.io/gorm"
@@ -33,3 +35,56 @@ func applySorting(query *gorm.DB, sortBy string, sortOrder string) *gorm.DB {
 
 	return query.Order(orderClause)
 }
+
+// escapeLike escapes LIKE wildcards so user input is matched literally
+func escapeLike(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	s = strings.ReplaceAll(s, "%", "\\%")
+	s = strings.ReplaceAll(s, "_", "\\_")
+	return s
+}
+
+// crewRankOrderSQL maps a crew rank column to its ordinal so ranks can be compared in SQL
+func crewRankOrderSQL(column string) string {
+	ranks := []types.CrewRank{
+		types.CrewRankSailors,
+		types.CrewRankMostlyHarmless,
+		types.CrewRankScurvyDogs,
+		types.CrewRankScoundrels,
+		types.CrewRankBlaggards,
+		types.CrewRankDreadPirates,
+		types.CrewRankSeaLords,
+		types.CrewRankImperials,
+	}
+
+	var b strings.Builder
+	b.WriteString("CASE " + column)
+	for _, rank := range ranks {
+		fmt.Fprintf(&b, " WHEN '%s' THEN %d", rank, rank.Order())
+	}
+	b.WriteString(" ELSE 0 END")
+	return b.String()
+}
+
+// fameLevelOrderSQL maps a fame or reputation level column to its ordinal so levels can be compared in SQL
+func fameLevelOrderSQL(column string) string {
+	levels := []types.FameLevel{
+		types.FameLevelObscure,
+		types.FameLevelRumored,
+		types.FameLevelNoted,
+		types.FameLevelRecognized,
+		types.FameLevelDistinguished,
+		types.FameLevelCelebrated,
+		types.FameLevelEminent,
+		types.FameLevelRenowned,
+		types.FameLevelIllustrious,
+	}
+
+	var b strings.Builder
+	b.WriteString("CASE " + column)
+	for _, level := range levels {
+		fmt.Fprintf(&b, " WHEN '%s' THEN %d", level, level.Order())
+	}
+	b.WriteString(" ELSE 0 END")
+	return b.String()
+}
@@ -27,6 +27,20 @@ func (r *CrewRepository) FindByID(id uint) (*models.Crew, error) {
 	return &crew, nil
 }
 
+func (r *CrewRepository) FindByIDs(ids []uint) ([]models.Crew, error) {
+	var crews []models.Crew
+	if len(ids) == 0 {
+		return crews, nil
+	}
+	err := r.db.Preload("Flag").
+		Where("id IN ?", ids).
+		Find(&crews).Error
+	if err != nil {
+		return nil, err
+	}
+	return crews, nil
+}
+
 func (r *CrewRepository) FindByGameID(gameID uint64, ocean types.Ocean) (*models.Crew, error) {
 	var crew models.Crew
 	err := r.db.Preload("Flag").
@@ -98,6 +112,28 @@ func (r *CrewRepository) GetLatestBattleRecord(crewID uint) (*models.CrewBattleR
 	return &record, nil
 }
 
+// GetLatestBattleRecords returns the most recent battle record of each crew, keyed by crew ID
+func (r *CrewRepository) GetLatestBattleRecords(crewIDs []uint) (map[uint]models.CrewBattleRecord, error) {
+	recordsByCrew := make(map[uint]models.CrewBattleRecord, len(crewIDs))
+	if len(crewIDs) == 0 {
+		return recordsByCrew, nil
+	}
+
+	var records []models.CrewBattleRecord
+	err := r.db.Select("DISTINCT ON (crew_id) *").
+		Where("crew_id IN ?", crewIDs).
+		Order("crew_id, scraped_at DESC").
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		recordsByCrew[record.CrewID] = record
+	}
+	return recordsByCrew, nil
+}
+
 func (r *CrewRepository) GetBattleRecords(crewID uint, startDate, endDate time.Time) ([]models.CrewBattleRecord, error) {
 	var records []models.CrewBattleRecord
 	err := r.db.Where("crew_id = ? AND scraped_at >= ? AND scraped_at <= ?", crewID, startDate, endDate).
@@ -134,3 +170,94 @@ func (r *CrewRepository) GetFameHistory(crewID uint) ([]models.CrewFameRecord, e
 func (r *CrewRepository) GetCurrentStats(crewID uint) (*models.CrewBattleRecord, error) {
 	return r.GetLatestBattleRecord(crewID)
 }
+
+// GetFlagHistory returns a crew's flag memberships, most recent first
+func (r *CrewRepository) GetFlagHistory(crewID uint) ([]models.CrewFlagHistory, error) {
+	var history []models.CrewFlagHistory
+	err := r.db.Preload("Flag").
+		Where("crew_id = ?", crewID).
+		Order("joined_at DESC, id DESC").
+		Find(&history).Error
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// GetNameHistory returns the names a crew has gone by, most recent first
+func (r *CrewRepository) GetNameHistory(crewID uint) ([]models.CrewNameHistory, error) {
+	var history []models.CrewNameHistory
+	err := r.db.Where("crew_id = ?", crewID).
+		Order("started_at DESC, id DESC").
+		Find(&history).Error
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// GetLifecycleEvents returns when a crew was first seen, marked inactive and reappeared, most recent first
+func (r *CrewRepository) GetLifecycleEvents(crewID uint) ([]models.CrewLifecycleEvent, error) {
+	var events []models.CrewLifecycleEvent
+	err := r.db.Where("crew_id = ?", crewID).
+		Order("occurred_at DESC, id DESC").
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// GetLatestFameRecords returns the most recent fame record of each crew, keyed by crew ID
+func (r *CrewRepository) GetLatestFameRecords(crewIDs []uint) (map[uint]models.CrewFameRecord, error) {
+	recordsByCrew := make(map[uint]models.CrewFameRecord, len(crewIDs))
+	if len(crewIDs) == 0 {
+		return recordsByCrew, nil
+	}
+
+	var records []models.CrewFameRecord
+	err := r.db.Select("DISTINCT ON (crew_id) *").
+		Where("crew_id IN ?", crewIDs).
+		Order("crew_id, scraped_at DESC").
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		recordsByCrew[record.CrewID] = record
+	}
+	return recordsByCrew, nil
+}
+
+// GetDailyBattleRecords returns the last battle record of each crew per UTC day in [startDate, endDate)
+func (r *CrewRepository) GetDailyBattleRecords(crewIDs []uint, startDate, endDate time.Time) ([]models.CrewBattleRecord, error) {
+	var records []models.CrewBattleRecord
+	if len(crewIDs) == 0 {
+		return records, nil
+	}
+	err := r.db.Select("DISTINCT ON (crew_id, date_trunc('day', scraped_at)) *").
+		Where("crew_id IN ? AND scraped_at >= ? AND scraped_at < ?", crewIDs, startDate, endDate).
+		Order("crew_id, date_trunc('day', scraped_at), scraped_at DESC").
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// GetDailyFameRecords returns the last fame record of each crew per UTC day in [startDate, endDate)
+func (r *CrewRepository) GetDailyFameRecords(crewIDs []uint, startDate, endDate time.Time) ([]models.CrewFameRecord, error) {
+	var records []models.CrewFameRecord
+	if len(crewIDs) == 0 {
+		return records, nil
+	}
+	err := r.db.Select("DISTINCT ON (crew_id, date_trunc('day', scraped_at)) *").
+		Where("crew_id IN ? AND scraped_at >= ? AND scraped_at < ?", crewIDs, startDate, endDate).
+		Order("crew_id, date_trunc('day', scraped_at), scraped_at DESC").
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
@@ -4,6 +4,8 @@ import (
 	"cutlass_analytics/internal/dto"
 	"cutlass_analytics/internal/models"
 	"cutlass_analytics/internal/types"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -100,6 +102,99 @@ func (r *FlagRepository) GetActiveCrewCount(flagID uint) (int64, error) {
 	return count, nil
 }
 
+// GetNameHistory returns the names a flag has gone by, most recent first
+func (r *FlagRepository) GetNameHistory(flagID uint) ([]models.FlagNameHistory, error) {
+	var history []models.FlagNameHistory
+	err := r.db.Where("flag_id = ?", flagID).
+		Order("started_at DESC, id DESC").
+		Find(&history).Error
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// GetLifecycleEvents returns when a flag was first seen, marked inactive and reappeared, most recent first
+func (r *FlagRepository) GetLifecycleEvents(flagID uint) ([]models.FlagLifecycleEvent, error) {
+	var events []models.FlagLifecycleEvent
+	err := r.db.Where("flag_id = ?", flagID).
+		Order("occurred_at DESC, id DESC").
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// GetGovernedIslands returns the islands a flag currently governs
+func (r *FlagRepository) GetGovernedIslands(flagID uint) ([]models.Island, error) {
+	var islands []models.Island
+	err := r.db.Where("governor_flag_id = ?", flagID).
+		Preload("Archipelago").
+		Order("name ASC").
+		Find(&islands).Error
+	if err != nil {
+		return nil, err
+	}
+	return islands, nil
+}
+
+// GetGovernanceHistory returns every governance record of the islands a flag has ever governed,
+// including the records of other governors, ordered by island and start
+func (r *FlagRepository) GetGovernanceHistory(flagID uint) ([]models.IslandGovernanceHistory, error) {
+	governed := r.db.Model(&models.IslandGovernanceHistory{}).
+		Select("island_id").
+		Where("flag_id = ?", flagID)
+
+	var history []models.IslandGovernanceHistory
+	err := r.db.Where("island_id IN (?)", governed).
+		Order("island_id, started_at, id").
+		Find(&history).Error
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+func (r *FlagRepository) FindByIDs(ids []uint) ([]models.Flag, error) {
+	var flags []models.Flag
+	if len(ids) == 0 {
+		return flags, nil
+	}
+	err := r.db.Where("id IN ?", ids).
+		Find(&flags).Error
+	if err != nil {
+		return nil, err
+	}
+	return flags, nil
+}
+
+// GetActiveCrewCounts returns the number of active crews per flag, keyed by flag ID
+func (r *FlagRepository) GetActiveCrewCounts(flagIDs []uint) (map[uint]int, error) {
+	counts := make(map[uint]int, len(flagIDs))
+	if len(flagIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		FlagID uint
+		Count  int
+	}
+	err := r.db.Model(&models.Crew{}).
+		Select("flag_id, COUNT(*) AS count").
+		Where("flag_id IN ? AND is_active = ?", flagIDs, true).
+		Group("flag_id").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.FlagID] = row.Count
+	}
+	return counts, nil
+}
+
 func (r *FlagRepository) GetLatestFameRecord(flagID uint) (*models.FlagFameRecord, error) {
 	var record models.FlagFameRecord
 	err := r.db.Where("flag_id = ?", flagID).
@@ -111,6 +206,28 @@ func (r *FlagRepository) GetLatestFameRecord(flagID uint) (*models.FlagFameRecor
 	return &record, nil
 }
 
+// GetLatestFameRecords returns the most recent fame record of each flag, keyed by flag ID
+func (r *FlagRepository) GetLatestFameRecords(flagIDs []uint) (map[uint]models.FlagFameRecord, error) {
+	recordsByFlag := make(map[uint]models.FlagFameRecord, len(flagIDs))
+	if len(flagIDs) == 0 {
+		return recordsByFlag, nil
+	}
+
+	var records []models.FlagFameRecord
+	err := r.db.Select("DISTINCT ON (flag_id) *").
+		Where("flag_id IN ?", flagIDs).
+		Order("flag_id, scraped_at DESC").
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		recordsByFlag[record.FlagID] = record
+	}
+	return recordsByFlag, nil
+}
+
 func (r *FlagRepository) GetFameHistory(flagID uint) ([]models.FlagFameRecord, error) {
 	var records []models.FlagFameRecord
 	err := r.db.Where("flag_id = ?", flagID).
@@ -121,3 +238,286 @@ func (r *FlagRepository) GetFameHistory(flagID uint) ([]models.FlagFameRecord, e
 	}
 	return records, nil
 }
+
+// FlagBattleTotals is the combined PvP record of a flag's member crews.
+// Date is the UTC day the totals belong to; it is zero for current totals.
+type FlagBattleTotals struct {
+	Date           time.Time
+	ScrapedAt      time.Time
+	CrewCount      int
+	TotalPVPWins   int
+	TotalPVPLosses int
+	DailyPVPWins   int
+	DailyPVPLosses int
+}
+
+func (t *FlagBattleTotals) WinRate() float64 {
+	total := t.TotalPVPWins + t.TotalPVPLosses
+	if total == 0 {
+		return 0
+	}
+	return float64(t.TotalPVPWins) / float64(total) * 100
+}
+
+func (t *FlagBattleTotals) TotalBattles() int {
+	return t.TotalPVPWins + t.TotalPVPLosses
+}
+
+// flagCrewRecordsCTE selects the battle records of every crew that has been in the flag.
+// counts is 0 for a crew's first record, which carries its lifetime totals as deltas, and 1
+// for the rest.
+const flagCrewRecordsCTE = `
+	flag_crew_records AS (
+		SELECT r.*,
+			CASE WHEN LAG(r.scraped_at) OVER (PARTITION BY r.crew_id ORDER BY r.scraped_at) IS NULL
+				THEN 0 ELSE 1 END AS counts
+		FROM crew_battle_records r
+		WHERE r.deleted_at IS NULL AND r.crew_id IN (
+			SELECT crew_id FROM crew_flag_history WHERE flag_id = @flag_id AND deleted_at IS NULL
+		)
+	)
+`
+
+// currentFlagTotalsQuery sums, for every crew currently in the flag, the battle deltas of its
+// records since it joined, so battles it fought before joining don't count. The daily deltas
+// are those of each crew's latest record. A crew's first record carries its lifetime totals as
+// deltas, which it may have fought anywhere, so its deltas don't count.
+const currentFlagTotalsQuery = `
+	WITH ` + flagCrewRecordsCTE + `
+	SELECT COUNT(*) AS crew_count,
+		COALESCE(SUM(total_pvp_wins), 0) AS total_pvp_wins,
+		COALESCE(SUM(total_pvp_losses), 0) AS total_pvp_losses,
+		COALESCE(SUM(daily_pvp_wins), 0) AS daily_pvp_wins,
+		COALESCE(SUM(daily_pvp_losses), 0) AS daily_pvp_losses,
+		MAX(scraped_at) AS scraped_at
+	FROM (
+		SELECT r.crew_id,
+			SUM(r.daily_pvp_wins * r.counts) AS total_pvp_wins,
+			SUM(r.daily_pvp_losses * r.counts) AS total_pvp_losses,
+			(ARRAY_AGG(r.daily_pvp_wins * r.counts ORDER BY r.scraped_at DESC))[1] AS daily_pvp_wins,
+			(ARRAY_AGG(r.daily_pvp_losses * r.counts ORDER BY r.scraped_at DESC))[1] AS daily_pvp_losses,
+			MAX(r.scraped_at) AS scraped_at
+		FROM flag_crew_records r
+		JOIN crew_flag_history h ON h.crew_id = r.crew_id AND h.flag_id = @flag_id
+			AND h.left_at IS NULL AND h.deleted_at IS NULL
+		WHERE r.scraped_at >= h.joined_at
+		GROUP BY r.crew_id
+	) members
+`
+
+// dailyFlagTotalsQuery sums, per UTC day, the last battle record of each member crew
+// for totals and every member battle record for the daily deltas. A crew is a member
+// from the scrape that saw it join up to, but not including, the one that saw it leave.
+// Its totals are the running sum of the deltas of its member records, so only the
+// battles it fought while in the flag count; records before @start still add to them. As
+// in currentFlagTotalsQuery, the deltas of a crew's first record don't count.
+const dailyFlagTotalsQuery = `
+	WITH ` + flagCrewRecordsCTE + `,
+	member_records AS (
+		SELECT r.crew_id, r.scraped_at, date_trunc('day', r.scraped_at) AS day,
+			SUM(r.daily_pvp_wins * r.counts) OVER crew_records AS total_pvp_wins,
+			SUM(r.daily_pvp_losses * r.counts) OVER crew_records AS total_pvp_losses,
+			r.daily_pvp_wins * r.counts AS daily_pvp_wins,
+			r.daily_pvp_losses * r.counts AS daily_pvp_losses
+		FROM flag_crew_records r
+		JOIN crew_flag_history h ON h.crew_id = r.crew_id AND h.flag_id = @flag_id AND h.deleted_at IS NULL
+			AND h.joined_at <= r.scraped_at AND (h.left_at IS NULL OR r.scraped_at < h.left_at)
+		WHERE r.scraped_at < @end
+		WINDOW crew_records AS (PARTITION BY r.crew_id ORDER BY r.scraped_at)
+	),
+	ranged AS (
+		SELECT * FROM member_records WHERE scraped_at >= @start
+	),
+	deltas AS (
+		SELECT day, SUM(daily_pvp_wins) AS daily_pvp_wins, SUM(daily_pvp_losses) AS daily_pvp_losses
+		FROM ranged
+		GROUP BY day
+	),
+	closing AS (
+		SELECT DISTINCT ON (crew_id, day) *
+		FROM ranged
+		ORDER BY crew_id, day, scraped_at DESC
+	)
+	SELECT closing.day AS date, COUNT(*) AS crew_count,
+		SUM(closing.total_pvp_wins) AS total_pvp_wins,
+		SUM(closing.total_pvp_losses) AS total_pvp_losses,
+		deltas.daily_pvp_wins, deltas.daily_pvp_losses,
+		MAX(closing.scraped_at) AS scraped_at
+	FROM closing
+	JOIN deltas ON deltas.day = closing.day
+	GROUP BY closing.day, deltas.daily_pvp_wins, deltas.daily_pvp_losses
+`
+
+// GetCurrentBattleTotals sums the battles the crews currently in a flag fought since joining it
+func (r *FlagRepository) GetCurrentBattleTotals(flagID uint) (*FlagBattleTotals, error) {
+	var totals FlagBattleTotals
+	err := r.db.Raw(currentFlagTotalsQuery, map[string]interface{}{
+		"flag_id": flagID,
+	}).Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	return &totals, nil
+}
+
+// GetDailyBattleTotals returns a flag's combined PvP record for each day with battle records
+// in [startDate, endDate). A crew's battles only count toward the flag while it was a member.
+func (r *FlagRepository) GetDailyBattleTotals(flagID uint, startDate, endDate time.Time) ([]FlagBattleTotals, error) {
+	var totals []FlagBattleTotals
+	err := r.db.Table("(?) AS totals", r.db.Raw(dailyFlagTotalsQuery, map[string]interface{}{
+		"flag_id": flagID,
+		"start":   startDate,
+		"end":     endDate,
+	})).
+		Order("date ASC").
+		Find(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+// GetFameRecords returns a flag's fame records scraped in [startDate, endDate)
+func (r *FlagRepository) GetFameRecords(flagID uint, startDate, endDate time.Time) ([]models.FlagFameRecord, error) {
+	var records []models.FlagFameRecord
+	err := r.db.Where("flag_id = ? AND scraped_at >= ? AND scraped_at < ?", flagID, startDate, endDate).
+		Order("scraped_at ASC").
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// GetReputationRecords returns a flag's reputation records scraped in [startDate, endDate),
+// oldest first
+func (r *FlagRepository) GetReputationRecords(flagID uint, startDate, endDate time.Time) ([]models.FlagReputationRecord, error) {
+	var records []models.FlagReputationRecord
+	err := r.db.Where("flag_id = ? AND scraped_at >= ? AND scraped_at < ?", flagID, startDate, endDate).
+		Order("scraped_at ASC, reputation_type ASC").
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// GetLatestReputationRecords returns a flag's most recent record of each reputation type,
+// keyed by reputation type
+func (r *FlagRepository) GetLatestReputationRecords(flagID uint) (map[types.ReputationType]models.FlagReputationRecord, error) {
+	var records []models.FlagReputationRecord
+	err := r.db.Select("DISTINCT ON (reputation_type) *").
+		Where("flag_id = ?", flagID).
+		Order("reputation_type, scraped_at DESC").
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	recordsByType := make(map[types.ReputationType]models.FlagReputationRecord, len(records))
+	for _, record := range records {
+		recordsByType[record.ReputationType] = record
+	}
+	return recordsByType, nil
+}
+
+// CountMembershipChanges counts the crews that joined and left a flag in [startDate, endDate)
+func (r *FlagRepository) CountMembershipChanges(flagID uint, startDate, endDate time.Time) (joined, left int64, err error) {
+	err = r.db.Model(&models.CrewFlagHistory{}).
+		Where("flag_id = ? AND joined_at >= ? AND joined_at < ?", flagID, startDate, endDate).
+		Count(&joined).Error
+	if err != nil {
+		return 0, 0, err
+	}
+	err = r.db.Model(&models.CrewFlagHistory{}).
+		Where("flag_id = ? AND left_at >= ? AND left_at < ?", flagID, startDate, endDate).
+		Count(&left).Error
+	if err != nil {
+		return 0, 0, err
+	}
+	return joined, left, nil
+}
+
+const (
+	MembershipJoin  = "join"
+	MembershipLeave = "leave"
+)
+
+// MembershipChange is a crew joining or leaving a flag. OtherFlagID is the flag the crew
+// came from on a join, or went to on a leave, when it switched flags in a single scrape.
+type MembershipChange struct {
+	HistoryID   uint
+	CrewID      uint
+	Event       string
+	OccurredAt  time.Time
+	JoinedAt    time.Time
+	LeftAt      *time.Time
+	OtherFlagID *uint
+}
+
+// membershipJoinsQuery selects the joins of a flag along with the flag each crew left for it
+const membershipJoinsQuery = `
+	SELECT h.id AS history_id, h.crew_id, 'join' AS event, h.joined_at AS occurred_at, h.joined_at, h.left_at,
+		(SELECT p.flag_id FROM crew_flag_history p
+			WHERE p.crew_id = h.crew_id AND p.id < h.id AND p.deleted_at IS NULL AND p.left_at >= h.joined_at
+			ORDER BY p.id DESC LIMIT 1) AS other_flag_id
+	FROM crew_flag_history h
+	WHERE h.flag_id = @flag_id AND h.deleted_at IS NULL AND h.joined_at >= @start AND h.joined_at < @end
+`
+
+// membershipLeavesQuery selects the departures from a flag along with the flag each crew defected to
+const membershipLeavesQuery = `
+	SELECT h.id AS history_id, h.crew_id, 'leave' AS event, h.left_at AS occurred_at, h.joined_at, h.left_at,
+		(SELECT n.flag_id FROM crew_flag_history n
+			WHERE n.crew_id = h.crew_id AND n.id > h.id AND n.deleted_at IS NULL AND n.joined_at <= h.left_at
+			ORDER BY n.id ASC LIMIT 1) AS other_flag_id
+	FROM crew_flag_history h
+	WHERE h.flag_id = @flag_id AND h.deleted_at IS NULL AND h.left_at >= @start AND h.left_at < @end
+`
+
+// GetMembershipChanges returns the joins and departures of a flag in [startDate, endDate), most recent first
+func (r *FlagRepository) GetMembershipChanges(flagID uint, startDate, endDate time.Time, includeJoins, includeLeaves bool, offset, limit int) ([]MembershipChange, int64, error) {
+	var queries []string
+	if includeJoins {
+		queries = append(queries, membershipJoinsQuery)
+	}
+	if includeLeaves {
+		queries = append(queries, membershipLeavesQuery)
+	}
+	if len(queries) == 0 {
+		return []MembershipChange{}, 0, nil
+	}
+
+	changes := r.db.Table("(?) AS changes", r.db.Raw(strings.Join(queries, " UNION ALL "), map[string]interface{}{
+		"flag_id": flagID,
+		"start":   startDate,
+		"end":     endDate,
+	}))
+
+	var total int64
+	if err := changes.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var results []MembershipChange
+	err := changes.Order("occurred_at DESC, history_id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&results).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return results, total, nil
+}
+
+// CountMembersAt counts the crews sailing under a flag at the given time
+func (r *FlagRepository) CountMembersAt(flagID uint, at time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.CrewFlagHistory{}).
+		Where("flag_id = ? AND joined_at <= ? AND (left_at IS NULL OR left_at > ?)", flagID, at, at).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
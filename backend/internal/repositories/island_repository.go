@@ -130,3 +130,117 @@ func (r *IslandRepository) GetCommodities(islandID uint) ([]models.IslandCommodi
 	}
 	return commodities, nil
 }
+
+func (r *IslandRepository) FindByNames(ocean types.Ocean, names []string) ([]models.Island, error) {
+	var islands []models.Island
+	if len(names) == 0 {
+		return islands, nil
+	}
+	err := r.db.Where("ocean = ? AND name IN ?", ocean, names).
+		Find(&islands).Error
+	if err != nil {
+		return nil, err
+	}
+	return islands, nil
+}
+
+// FindByOcean returns every island in an ocean along with its governing flag
+func (r *IslandRepository) FindByOcean(ocean types.Ocean) ([]models.Island, error) {
+	var islands []models.Island
+	err := r.db.Where("ocean = ?", ocean).
+		Preload("GovernorFlag").
+		Order("name ASC").
+		Find(&islands).Error
+	if err != nil {
+		return nil, err
+	}
+	return islands, nil
+}
+
+func (r *IslandRepository) FindByIDs(ids []uint) ([]models.Island, error) {
+	var islands []models.Island
+	if len(ids) == 0 {
+		return islands, nil
+	}
+	err := r.db.Where("id IN ?", ids).
+		Find(&islands).Error
+	if err != nil {
+		return nil, err
+	}
+	return islands, nil
+}
+
+// FindByIDsWithCommodities returns islands along with their archipelago and the commodities that spawn on them
+func (r *IslandRepository) FindByIDsWithCommodities(ids []uint) ([]models.Island, error) {
+	var islands []models.Island
+	if len(ids) == 0 {
+		return islands, nil
+	}
+	err := r.db.Where("id IN ?", ids).
+		Preload("Archipelago").
+		Preload("Commodities.Commodity").
+		Find(&islands).Error
+	if err != nil {
+		return nil, err
+	}
+	return islands, nil
+}
+
+// GetLatestPopulations returns the latest recorded population of each island, keyed by island ID
+func (r *IslandRepository) GetLatestPopulations(islandIDs []uint) (map[uint]int, error) {
+	populations := make(map[uint]int, len(islandIDs))
+	if len(islandIDs) == 0 {
+		return populations, nil
+	}
+
+	var records []models.IslandPopulation
+	err := r.db.Select("DISTINCT ON (island_id) *").
+		Where("island_id IN ?", islandIDs).
+		Order("island_id, scraped_at DESC").
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		populations[record.IslandID] = record.Population
+	}
+	return populations, nil
+}
+
+// GetOceanPopulationHistory returns the population records of an ocean's islands scraped since
+// startDate, together with each island's last record before it, ordered by island and time
+func (r *IslandRepository) GetOceanPopulationHistory(ocean types.Ocean, startDate time.Time) ([]models.IslandPopulation, error) {
+	var baseline []models.IslandPopulation
+	err := r.db.Select("DISTINCT ON (island_populations.island_id) island_populations.*").
+		Joins("JOIN islands ON islands.id = island_populations.island_id AND islands.deleted_at IS NULL").
+		Where("islands.ocean = ? AND island_populations.scraped_at < ?", ocean, startDate).
+		Order("island_populations.island_id, island_populations.scraped_at DESC").
+		Find(&baseline).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var recent []models.IslandPopulation
+	err = r.db.Joins("JOIN islands ON islands.id = island_populations.island_id AND islands.deleted_at IS NULL").
+		Where("islands.ocean = ? AND island_populations.scraped_at >= ?", ocean, startDate).
+		Order("island_populations.island_id, island_populations.scraped_at ASC").
+		Find(&recent).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Both lists are ordered by island, so merging them keeps each island's records in time order
+	populations := make([]models.IslandPopulation, 0, len(baseline)+len(recent))
+	i, j := 0, 0
+	for i < len(baseline) || j < len(recent) {
+		if j == len(recent) || (i < len(baseline) && baseline[i].IslandID <= recent[j].IslandID) {
+			populations = append(populations, baseline[i])
+			i++
+		} else {
+			populations = append(populations, recent[j])
+			j++
+		}
+	}
+	return populations, nil
+}
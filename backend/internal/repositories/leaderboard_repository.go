@@ -0,0 +1,491 @@
+package repositories
+
+import (
+	"cutlass_analytics/internal/models"
+	"cutlass_analytics/internal/types"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LeaderboardRepository struct {
+	db *gorm.DB
+}
+
+func NewLeaderboardRepository(db *gorm.DB) *LeaderboardRepository {
+	return &LeaderboardRepository{db: db}
+}
+
+// CrewStanding is a crew's battle record with its position on a leaderboard.
+// PreviousRank is the crew's position on the same leaderboard one scrape earlier,
+// or nil if it was not ranked then.
+type CrewStanding struct {
+	CrewID         uint
+	ScrapedAt      time.Time
+	CrewRank       types.CrewRank
+	TotalPVPWins   int
+	TotalPVPLosses int
+	DailyPVPWins   int
+	DailyPVPLosses int
+	Rank           int
+	PreviousRank   *int
+}
+
+// RankChange returns how many places the crew climbed since the previous scrape
+func (s *CrewStanding) RankChange() *int {
+	if s.PreviousRank == nil {
+		return nil
+	}
+	change := *s.PreviousRank - s.Rank
+	return &change
+}
+
+// crewStandingsQuery ranks the current (recency 1) and previous (recency 2) battle records
+// returned by the inner query and pairs each current standing with its previous rank.
+// The score expression and eligibility filter are filled in by the caller.
+const crewStandingsQuery = `
+	WITH scored AS (
+		SELECT records.*, %s AS score
+		FROM (%s) records
+		WHERE %s
+	),
+	ranked AS (
+		SELECT scored.*, RANK() OVER (PARTITION BY recency ORDER BY score DESC) AS standing
+		FROM scored
+	)
+	SELECT
+		cur.crew_id, cur.scraped_at, cur.crew_rank,
+		cur.total_pvp_wins, cur.total_pvp_losses, cur.daily_pvp_wins, cur.daily_pvp_losses,
+		cur.score, cur.standing AS rank, prev.standing AS previous_rank
+	FROM ranked cur
+	LEFT JOIN ranked prev ON prev.crew_id = cur.crew_id AND prev.recency = 2
+	WHERE cur.recency = 1
+`
+
+// latestBattleRecordsQuery selects the two most recent battle records of every crew in an ocean
+const latestBattleRecordsQuery = `
+	SELECT * FROM (
+		SELECT r.crew_id, r.scraped_at, r.crew_rank,
+			r.total_pvp_wins, r.total_pvp_losses, r.daily_pvp_wins, r.daily_pvp_losses,
+			ROW_NUMBER() OVER (PARTITION BY r.crew_id ORDER BY r.scraped_at DESC) AS recency
+		FROM crew_battle_records r
+		JOIN crews c ON c.id = r.crew_id AND c.deleted_at IS NULL
+		WHERE r.deleted_at IS NULL AND c.ocean = @ocean
+	) numbered
+	WHERE recency <= 2
+`
+
+// dailyBattleRecordsQuery selects each crew's last battle record on a day (recency 1)
+// and on the day before it (recency 2). Records hold the battles since the previous scrape,
+// so the daily battles are summed over all of the crew's records that day; rank and totals
+// are those of the last one.
+const dailyBattleRecordsQuery = `
+	SELECT DISTINCT ON (r.crew_id, r.recency)
+		r.crew_id, r.scraped_at, r.crew_rank,
+		r.total_pvp_wins, r.total_pvp_losses,
+		SUM(r.daily_pvp_wins) OVER crew_day AS daily_pvp_wins,
+		SUM(r.daily_pvp_losses) OVER crew_day AS daily_pvp_losses,
+		r.recency
+	FROM (
+		SELECT r.*, CASE WHEN r.scraped_at >= @day_start THEN 1 ELSE 2 END AS recency
+		FROM crew_battle_records r
+		JOIN crews c ON c.id = r.crew_id AND c.deleted_at IS NULL
+		WHERE r.deleted_at IS NULL AND c.ocean = @ocean
+			AND r.scraped_at >= @previous_day_start AND r.scraped_at < @day_end
+	) r
+	WINDOW crew_day AS (PARTITION BY r.crew_id, r.recency)
+	ORDER BY r.crew_id, r.recency, r.scraped_at DESC
+`
+
+// GetCrewStandings ranks the crews of an ocean by their latest battle record.
+// leaderboardType is one of wins, win_rate, battles or rank; win_rate only ranks
+// crews with at least minBattles battles.
+func (r *LeaderboardRepository) GetCrewStandings(ocean types.Ocean, leaderboardType string, minBattles int, offset, limit int) ([]CrewStanding, int64, error) {
+	var score, eligible string
+	switch leaderboardType {
+	case "win_rate":
+		score = "total_pvp_wins::float / NULLIF(total_pvp_wins + total_pvp_losses, 0)"
+		eligible = "total_pvp_wins + total_pvp_losses >= @min_battles AND total_pvp_wins + total_pvp_losses > 0"
+	case "battles":
+		score = "total_pvp_wins + total_pvp_losses"
+		eligible = "total_pvp_wins + total_pvp_losses > 0"
+	case "rank":
+		// Wins break ties between crews of the same standing
+		score = "(" + crewRankOrderSQL("crew_rank") + ") * 1000000000::bigint + total_pvp_wins"
+		eligible = "crew_rank <> ''"
+	default:
+		score = "total_pvp_wins"
+		eligible = "total_pvp_wins > 0"
+	}
+
+	query := fmt.Sprintf(crewStandingsQuery, score, latestBattleRecordsQuery, eligible)
+	return r.findCrewStandings(query, map[string]interface{}{
+		"ocean":       ocean,
+		"min_battles": minBattles,
+	}, offset, limit)
+}
+
+// GetDailyCrewStandings ranks the crews of an ocean by their battles on the given day,
+// with previous ranks taken from the day before. Crews without battles that day are not ranked.
+// leaderboardType is one of wins, win_rate or battles.
+func (r *LeaderboardRepository) GetDailyCrewStandings(ocean types.Ocean, day time.Time, leaderboardType string, minBattles int, offset, limit int) ([]CrewStanding, int64, error) {
+	var score string
+	eligible := "daily_pvp_wins + daily_pvp_losses > 0"
+	switch leaderboardType {
+	case "win_rate":
+		score = "daily_pvp_wins::float / NULLIF(daily_pvp_wins + daily_pvp_losses, 0)"
+		eligible += " AND daily_pvp_wins + daily_pvp_losses >= @min_battles"
+	case "battles":
+		score = "daily_pvp_wins + daily_pvp_losses"
+	default:
+		score = "daily_pvp_wins"
+	}
+
+	dayStart := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	query := fmt.Sprintf(crewStandingsQuery, score, dailyBattleRecordsQuery, eligible)
+	return r.findCrewStandings(query, map[string]interface{}{
+		"ocean":              ocean,
+		"min_battles":        minBattles,
+		"day_start":          dayStart,
+		"day_end":            dayStart.AddDate(0, 0, 1),
+		"previous_day_start": dayStart.AddDate(0, 0, -1),
+	}, offset, limit)
+}
+
+// GetLatestBattleDate returns when the most recent battle record in an ocean was scraped
+func (r *LeaderboardRepository) GetLatestBattleDate(ocean types.Ocean) (*time.Time, error) {
+	var latest sql.NullTime
+	err := r.db.Model(&models.CrewBattleRecord{}).
+		Joins("JOIN crews ON crews.id = crew_battle_records.crew_id").
+		Where("crews.ocean = ?", ocean).
+		Select("MAX(crew_battle_records.scraped_at)").
+		Row().Scan(&latest)
+	if err != nil {
+		return nil, err
+	}
+	if !latest.Valid {
+		return nil, nil
+	}
+	return &latest.Time, nil
+}
+
+func (r *LeaderboardRepository) findCrewStandings(query string, args map[string]interface{}, offset, limit int) ([]CrewStanding, int64, error) {
+	standings := r.db.Table("(?) AS standings", r.db.Raw(query, args))
+
+	// Count total before pagination
+	var total int64
+	if err := standings.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var results []CrewStanding
+	err := standings.Order("rank ASC, score DESC, total_pvp_wins DESC, crew_id ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&results).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return results, total, nil
+}
+
+// RankedStanding is an entity's latest fame or reputation record along with its rank
+// on the last scrape before the comparison window. PreviousRank is nil if the entity
+// was not ranked then.
+type RankedStanding struct {
+	EntityID     uint
+	ScrapedAt    time.Time
+	Level        string
+	Rank         *int
+	PreviousRank *int
+}
+
+// RankChange returns how many places the entity climbed over the comparison window
+func (s *RankedStanding) RankChange() *int {
+	if s.Rank == nil || s.PreviousRank == nil {
+		return nil
+	}
+	change := *s.PreviousRank - *s.Rank
+	return &change
+}
+
+// rankedRecordSource describes a table of scraped rank records and the entity they belong to
+type rankedRecordSource struct {
+	table        string
+	entityColumn string
+	entityTable  string
+	levelColumn  string
+	rankColumn   string
+}
+
+var (
+	crewFameSource = rankedRecordSource{
+		table:        "crew_fame_records",
+		entityColumn: "crew_id",
+		entityTable:  "crews",
+		levelColumn:  "fame_level",
+		rankColumn:   "fame_rank",
+	}
+	flagFameSource = rankedRecordSource{
+		table:        "flag_fame_records",
+		entityColumn: "flag_id",
+		entityTable:  "flags",
+		levelColumn:  "fame_level",
+		rankColumn:   "fame_rank",
+	}
+)
+
+// rankedStandingsQuery returns every entity ranked on the latest scrape of a source, paired
+// with its rank on the last scrape made before the day that lies @days days earlier
+const rankedStandingsQuery = `
+	WITH latest AS (
+		SELECT MAX(r.scraped_at) AS scraped_at
+		FROM %[1]s r
+		JOIN %[3]s e ON e.id = r.%[2]s AND e.deleted_at IS NULL
+		WHERE r.deleted_at IS NULL AND e.ocean = @ocean
+	),
+	previous AS (
+		SELECT DISTINCT ON (r.%[2]s) r.%[2]s AS entity_id, r.%[5]s AS rank
+		FROM %[1]s r
+		JOIN %[3]s e ON e.id = r.%[2]s AND e.deleted_at IS NULL
+		WHERE r.deleted_at IS NULL AND e.ocean = @ocean
+			AND r.scraped_at < date_trunc('day', (SELECT scraped_at FROM latest)) - (@days - 1) * INTERVAL '1 day'
+		ORDER BY r.%[2]s, r.scraped_at DESC
+	)
+	SELECT r.%[2]s AS entity_id, r.scraped_at, r.%[4]s AS level, r.%[5]s AS rank, p.rank AS previous_rank
+	FROM %[1]s r
+	JOIN %[3]s e ON e.id = r.%[2]s AND e.deleted_at IS NULL
+	JOIN latest l ON r.scraped_at = l.scraped_at
+	LEFT JOIN previous p ON p.entity_id = r.%[2]s
+	WHERE r.deleted_at IS NULL AND e.ocean = @ocean
+`
+
+func (s rankedRecordSource) standings(db *gorm.DB, args map[string]interface{}) *gorm.DB {
+	query := fmt.Sprintf(rankedStandingsQuery, s.table, s.entityColumn, s.entityTable, s.levelColumn, s.rankColumn)
+	return db.Table("(?) AS standings", db.Raw(query, args))
+}
+
+func fameSource(entityType string) rankedRecordSource {
+	if entityType == "flag" {
+		return flagFameSource
+	}
+	return crewFameSource
+}
+
+// GetFameStandings returns the latest fame ranking of crews or flags in an ocean.
+// entityType is crew or flag; previous ranks are taken from days days earlier.
+func (r *LeaderboardRepository) GetFameStandings(entityType string, ocean types.Ocean, days, offset, limit int) ([]RankedStanding, int64, error) {
+	return r.findRankedStandings(fameSource(entityType), map[string]interface{}{
+		"ocean": ocean,
+		"days":  days,
+	}, offset, limit)
+}
+
+// GetFameMovers returns the crews or flags that climbed and fell the most places
+// on the fame ranking over the last days days
+func (r *LeaderboardRepository) GetFameMovers(entityType string, ocean types.Ocean, days, limit int) ([]RankedStanding, []RankedStanding, error) {
+	return r.findRankedMovers(fameSource(entityType), map[string]interface{}{
+		"ocean": ocean,
+		"days":  days,
+	}, limit)
+}
+
+func (r *LeaderboardRepository) findRankedStandings(source rankedRecordSource, args map[string]interface{}, offset, limit int) ([]RankedStanding, int64, error) {
+	standings := source.standings(r.db, args)
+
+	// Count total before pagination
+	var total int64
+	if err := standings.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var results []RankedStanding
+	err := standings.Order("rank ASC NULLS LAST, entity_id ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&results).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return results, total, nil
+}
+
+func (r *LeaderboardRepository) findRankedMovers(source rankedRecordSource, args map[string]interface{}, limit int) ([]RankedStanding, []RankedStanding, error) {
+	var climbers []RankedStanding
+	err := source.standings(r.db, args).
+		Where("previous_rank - rank > 0").
+		Order("previous_rank - rank DESC, rank ASC").
+		Limit(limit).
+		Find(&climbers).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var fallers []RankedStanding
+	err = source.standings(r.db, args).
+		Where("previous_rank - rank < 0").
+		Order("previous_rank - rank ASC, rank ASC").
+		Limit(limit).
+		Find(&fallers).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return climbers, fallers, nil
+}
+
+// LevelStanding is a flag's reputation level on the latest scrape along with its level on the
+// last scrape before the comparison window. Flag info pages show levels without an in-game
+// rank, so flags are ranked by level and tied flags share a rank. PreviousLevel and LevelChange
+// are nil if the flag had no level then.
+type LevelStanding struct {
+	EntityID      uint
+	ScrapedAt     time.Time
+	Rank          int
+	Level         string
+	PreviousLevel *string
+	LevelChange   *int
+}
+
+// flagReputationStandingsQuery ranks the flags of an ocean by their level of one reputation on
+// the latest scrape and pairs each with its level on the last scrape made before the day that
+// lies @days days earlier. The level ordinal expressions are filled in by the caller.
+const flagReputationStandingsQuery = `
+	WITH latest AS (
+		SELECT MAX(r.scraped_at) AS scraped_at
+		FROM flag_reputation_records r
+		JOIN flags f ON f.id = r.flag_id AND f.deleted_at IS NULL
+		WHERE r.deleted_at IS NULL AND f.ocean = @ocean AND r.reputation_type = @reputation_type
+	),
+	previous AS (
+		SELECT DISTINCT ON (r.flag_id) r.flag_id, r.reputation_level
+		FROM flag_reputation_records r
+		JOIN flags f ON f.id = r.flag_id AND f.deleted_at IS NULL
+		WHERE r.deleted_at IS NULL AND f.ocean = @ocean AND r.reputation_type = @reputation_type
+			AND r.scraped_at < date_trunc('day', (SELECT scraped_at FROM latest)) - (@days - 1) * INTERVAL '1 day'
+		ORDER BY r.flag_id, r.scraped_at DESC
+	)
+	SELECT r.flag_id AS entity_id, r.scraped_at,
+		RANK() OVER (ORDER BY %[1]s DESC) AS rank,
+		r.reputation_level AS level, p.reputation_level AS previous_level,
+		CASE WHEN p.flag_id IS NOT NULL THEN %[1]s - %[2]s END AS level_change
+	FROM flag_reputation_records r
+	JOIN flags f ON f.id = r.flag_id AND f.deleted_at IS NULL
+	JOIN latest l ON r.scraped_at = l.scraped_at
+	LEFT JOIN previous p ON p.flag_id = r.flag_id
+	WHERE r.deleted_at IS NULL AND f.ocean = @ocean AND r.reputation_type = @reputation_type
+`
+
+func (r *LeaderboardRepository) flagReputationStandings(reputationType types.ReputationType, ocean types.Ocean, days int) *gorm.DB {
+	query := fmt.Sprintf(flagReputationStandingsQuery, fameLevelOrderSQL("r.reputation_level"), fameLevelOrderSQL("p.reputation_level"))
+	return r.db.Table("(?) AS standings", r.db.Raw(query, map[string]interface{}{
+		"ocean":           ocean,
+		"days":            days,
+		"reputation_type": reputationType,
+	}))
+}
+
+// GetFlagReputationStandings ranks the flags of an ocean by their latest level of one reputation,
+// with previous levels taken from days days earlier
+func (r *LeaderboardRepository) GetFlagReputationStandings(reputationType types.ReputationType, ocean types.Ocean, days, offset, limit int) ([]LevelStanding, int64, error) {
+	standings := r.flagReputationStandings(reputationType, ocean, days)
+
+	// Count total before pagination
+	var total int64
+	if err := standings.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var results []LevelStanding
+	err := standings.Order("rank ASC, entity_id ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&results).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return results, total, nil
+}
+
+// GetFlagReputationMovers returns the flags that gained and lost the most levels of one
+// reputation over the last days days
+func (r *LeaderboardRepository) GetFlagReputationMovers(reputationType types.ReputationType, ocean types.Ocean, days, limit int) ([]LevelStanding, []LevelStanding, error) {
+	var climbers []LevelStanding
+	err := r.flagReputationStandings(reputationType, ocean, days).
+		Where("level_change > 0").
+		Order("level_change DESC, rank ASC, entity_id ASC").
+		Limit(limit).
+		Find(&climbers).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var fallers []LevelStanding
+	err = r.flagReputationStandings(reputationType, ocean, days).
+		Where("level_change < 0").
+		Order("level_change ASC, rank ASC, entity_id ASC").
+		Limit(limit).
+		Find(&fallers).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return climbers, fallers, nil
+}
+
+// FlagStanding is a flag's combined PvP record across its current member crews
+type FlagStanding struct {
+	FlagID         uint
+	CrewCount      int
+	TotalPVPWins   int
+	TotalPVPLosses int
+	ScrapedAt      time.Time
+	Rank           int
+}
+
+// flagStandingsQuery sums the latest battle record of every crew per flag and ranks the flags
+const flagStandingsQuery = `
+	WITH totals AS (
+		SELECT c.flag_id, COUNT(*) AS crew_count,
+			SUM(records.total_pvp_wins) AS total_pvp_wins,
+			SUM(records.total_pvp_losses) AS total_pvp_losses,
+			MAX(records.scraped_at) AS scraped_at
+		FROM (%[2]s) records
+		JOIN crews c ON c.id = records.crew_id
+		WHERE records.recency = 1 AND c.flag_id IS NOT NULL
+		GROUP BY c.flag_id
+	)
+	SELECT totals.*, %[1]s AS score, RANK() OVER (ORDER BY %[1]s DESC) AS rank
+	FROM totals
+	WHERE %[3]s
+`
+
+// GetFlagStandings ranks the flags of an ocean by the combined battle records of their crews.
+// leaderboardType is wins or win_rate; win_rate only ranks flags with at least minBattles battles.
+func (r *LeaderboardRepository) GetFlagStandings(ocean types.Ocean, leaderboardType string, minBattles int, limit int) ([]FlagStanding, error) {
+	score := "total_pvp_wins"
+	eligible := "total_pvp_wins > 0"
+	if leaderboardType == "win_rate" {
+		score = "total_pvp_wins::float / NULLIF(total_pvp_wins + total_pvp_losses, 0)"
+		eligible = "total_pvp_wins + total_pvp_losses >= @min_battles AND total_pvp_wins + total_pvp_losses > 0"
+	}
+
+	query := fmt.Sprintf(flagStandingsQuery, score, latestBattleRecordsQuery, eligible)
+	var results []FlagStanding
+	err := r.db.Table("(?) AS standings", r.db.Raw(query, map[string]interface{}{
+		"ocean":       ocean,
+		"min_battles": minBattles,
+	})).
+		Order("rank ASC, total_pvp_wins DESC, flag_id ASC").
+		Limit(limit).
+		Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
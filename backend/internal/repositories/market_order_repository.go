@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"cutlass_analytics/internal/dto"
+	"cutlass_analytics/internal/types"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MarketOrderRepository struct {
+	db *gorm.DB
+}
+
+func NewMarketOrderRepository(db *gorm.DB) *MarketOrderRepository {
+	return &MarketOrderRepository{db: db}
+}
+
+// TradeRoute is a buy-low/sell-high pairing of two islands for one commodity.
+// BuyPrice is the cheapest ask on the buy island and SellPrice the best bid on the sell island.
+type TradeRoute struct {
+	CommodityName  string
+	BuyIslandName  string
+	SellIslandName string
+	BuyPrice       int
+	BuyQuantity    int
+	SellPrice      int
+	SellQuantity   int
+	Profit         int
+	MaxQuantity    int
+	MaxProfit      int
+	ScrapedAt      time.Time
+}
+
+// tradeRoutesQuery pairs the best current ask per island with the best current bid on every
+// other island for the same commodity, keeping only pairs where the bid beats the ask.
+const tradeRoutesQuery = `
+	WITH asks AS (
+		SELECT DISTINCT ON (island_name, commodity_name)
+			island_name, commodity_name, sell_price, sell_quantity, imported_at
+		FROM market_orders
+		WHERE deleted_at IS NULL AND retired_at IS NULL AND ocean = @ocean AND sell_price > 0 AND sell_quantity > 0
+		ORDER BY island_name, commodity_name, sell_price ASC, sell_quantity DESC
+	),
+	bids AS (
+		SELECT DISTINCT ON (island_name, commodity_name)
+			island_name, commodity_name, buy_price, buy_quantity, imported_at
+		FROM market_orders
+		WHERE deleted_at IS NULL AND retired_at IS NULL AND ocean = @ocean AND buy_price > 0 AND buy_quantity > 0
+		ORDER BY island_name, commodity_name, buy_price DESC, buy_quantity DESC
+	)
+	SELECT
+		a.commodity_name AS commodity_name,
+		a.island_name AS buy_island_name,
+		b.island_name AS sell_island_name,
+		a.sell_price AS buy_price,
+		a.sell_quantity AS buy_quantity,
+		b.buy_price AS sell_price,
+		b.buy_quantity AS sell_quantity,
+		b.buy_price - a.sell_price AS profit,
+		LEAST(a.sell_quantity, b.buy_quantity) AS max_quantity,
+		(b.buy_price - a.sell_price) * LEAST(a.sell_quantity, b.buy_quantity) AS max_profit,
+		GREATEST(a.imported_at, b.imported_at) AS scraped_at
+	FROM asks a
+	JOIN bids b ON b.commodity_name = a.commodity_name
+		AND b.island_name <> a.island_name
+		AND b.buy_price > a.sell_price
+`
+
+// FindTradeRoutes returns profitable routes for an ocean along with the total number of matches.
+// commodityName narrows the search to a single commodity when non-empty.
+func (r *MarketOrderRepository) FindTradeRoutes(req dto.TradeRouteRequest, commodityName string) ([]TradeRoute, int64, error) {
+	query := r.db.Table("(?) AS routes", r.db.Raw(tradeRoutesQuery, map[string]interface{}{
+		"ocean": types.Ocean(req.Ocean),
+	}))
+
+	// Apply filters
+	if commodityName != "" {
+		query = query.Where("commodity_name = ?", commodityName)
+	}
+	if req.MinProfit != nil {
+		query = query.Where("profit >= ?", *req.MinProfit)
+	}
+	if req.MinQuantity != nil {
+		query = query.Where("max_quantity >= ?", *req.MinQuantity)
+	}
+
+	// Count total before pagination
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Apply sorting
+	switch req.SortBy {
+	case "profit":
+		query = query.Order("profit DESC").Order("max_profit DESC")
+	case "quantity":
+		query = query.Order("max_quantity DESC").Order("max_profit DESC")
+	default:
+		query = query.Order("max_profit DESC").Order("profit DESC")
+	}
+	query = query.Order("commodity_name ASC")
+
+	// Apply pagination
+	query = query.Offset(req.Offset()).Limit(req.Limit())
+
+	var routes []TradeRoute
+	if err := query.Find(&routes).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return routes, total, nil
+}
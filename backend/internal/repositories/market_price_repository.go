@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"cutlass_analytics/internal/models"
+	"cutlass_analytics/internal/types"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type MarketPriceRepository struct {
+	db *gorm.DB
+}
+
+func NewMarketPriceRepository(db *gorm.DB) *MarketPriceRepository {
+	return &MarketPriceRepository{db: db}
+}
+
+// GetLatestSnapshot returns the most recent order import for an ocean, or nil if none exists
+func (r *MarketPriceRepository) GetLatestSnapshot(ocean types.Ocean) (*models.MarketSnapshot, error) {
+	var snapshot models.MarketSnapshot
+	err := r.db.Where("ocean = ?", ocean).
+		Order("imported_at DESC").
+		First(&snapshot).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// GetCurrentPrices summarizes the current order book of an ocean into the best bid and ask
+// per island and commodity. islandName and commodityName narrow the book when non-empty.
+// Island and Commodity are attached where a matching row exists; otherwise only their
+// names are set and the IDs are zero.
+func (r *MarketPriceRepository) GetCurrentPrices(ocean types.Ocean, islandName, commodityName string) ([]models.MarketPrice, error) {
+	snapshot, err := r.GetLatestSnapshot(ocean)
+	if err != nil || snapshot == nil {
+		return nil, err
+	}
+
+	query := r.db.Where("ocean = ? AND retired_at IS NULL", ocean)
+	if islandName != "" {
+		query = query.Where("island_name = ?", islandName)
+	}
+	if commodityName != "" {
+		query = query.Where("commodity_name = ?", commodityName)
+	}
+
+	var orders []models.MarketOrder
+	if err := query.Order("island_name ASC, commodity_name ASC").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	prices := models.SummarizeMarketOrders(orders, snapshot.ImportedAt)
+	if len(prices) == 0 {
+		return prices, nil
+	}
+
+	islandNames := make([]string, 0, len(prices))
+	commodityNames := make([]string, 0, len(prices))
+	for _, price := range prices {
+		islandNames = append(islandNames, price.Island.Name)
+		commodityNames = append(commodityNames, price.Commodity.Name)
+	}
+
+	var islands []models.Island
+	if err := r.db.Where("ocean = ? AND name IN ?", ocean, islandNames).Find(&islands).Error; err != nil {
+		return nil, err
+	}
+	islandsByName := make(map[string]models.Island, len(islands))
+	for _, island := range islands {
+		islandsByName[island.Name] = island
+	}
+
+	var commodities []models.Commodity
+	if err := r.db.Where("name IN ?", commodityNames).Find(&commodities).Error; err != nil {
+		return nil, err
+	}
+	commoditiesByName := make(map[string]models.Commodity, len(commodities))
+	for _, commodity := range commodities {
+		commoditiesByName[commodity.Name] = commodity
+	}
+
+	for i := range prices {
+		if island, ok := islandsByName[prices[i].Island.Name]; ok {
+			prices[i].IslandID = island.ID
+			prices[i].Island = island
+		}
+		if commodity, ok := commoditiesByName[prices[i].Commodity.Name]; ok {
+			prices[i].CommodityID = commodity.ID
+			prices[i].Commodity = commodity
+		}
+	}
+
+	return prices, nil
+}
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"cutlass_analytics/internal/models"
+	"cutlass_analytics/internal/types"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type OceanRepository struct {
+	db *gorm.DB
+}
+
+func NewOceanRepository(db *gorm.DB) *OceanRepository {
+	return &OceanRepository{db: db}
+}
+
+// OceanStats aggregates an ocean's crews, flags, islands, PvP record and tax rates.
+// Population, PvP totals and tax rates come from the latest record of each island, crew and commodity.
+type OceanStats struct {
+	TotalCrews       int
+	ActiveCrews      int
+	TotalFlags       int
+	ActiveFlags      int
+	TotalIslands     int
+	ColonizedIslands int
+	TotalPopulation  int
+	TotalPVPWins     int
+	TotalPVPLosses   int
+	RecentPVPWins    int
+	RecentPVPLosses  int
+	AvgTaxRate       float64
+	TaxedCommodities int
+	LastScrapedAt    *time.Time
+}
+
+func (s *OceanStats) TotalPVPBattles() int {
+	return s.TotalPVPWins + s.TotalPVPLosses
+}
+
+func (s *OceanStats) RecentPVPBattles() int {
+	return s.RecentPVPWins + s.RecentPVPLosses
+}
+
+// oceanStatsQuery computes every OceanStats field in one round trip. Recent PvP activity
+// sums the battle records scraped since @recent.
+const oceanStatsQuery = `
+	SELECT crews.*, flags.*, islands.*, population.*, pvp.*, recent.*, taxes.*, jobs.*
+	FROM (
+		SELECT COUNT(*) AS total_crews, COUNT(*) FILTER (WHERE is_active) AS active_crews
+		FROM crews
+		WHERE deleted_at IS NULL AND ocean = @ocean
+	) crews, (
+		SELECT COUNT(*) AS total_flags, COUNT(*) FILTER (WHERE is_active) AS active_flags
+		FROM flags
+		WHERE deleted_at IS NULL AND ocean = @ocean
+	) flags, (
+		SELECT COUNT(*) AS total_islands, COUNT(*) FILTER (WHERE is_colonized) AS colonized_islands
+		FROM islands
+		WHERE deleted_at IS NULL AND ocean = @ocean
+	) islands, (
+		SELECT COALESCE(SUM(population), 0) AS total_population
+		FROM (
+			SELECT DISTINCT ON (p.island_id) p.population
+			FROM island_populations p
+			JOIN islands i ON i.id = p.island_id AND i.deleted_at IS NULL
+			WHERE p.deleted_at IS NULL AND i.ocean = @ocean
+			ORDER BY p.island_id, p.scraped_at DESC
+		) latest
+	) population, (
+		SELECT COALESCE(SUM(total_pvp_wins), 0) AS total_pvp_wins,
+			COALESCE(SUM(total_pvp_losses), 0) AS total_pvp_losses
+		FROM (
+			SELECT DISTINCT ON (r.crew_id) r.total_pvp_wins, r.total_pvp_losses
+			FROM crew_battle_records r
+			JOIN crews c ON c.id = r.crew_id AND c.deleted_at IS NULL
+			WHERE r.deleted_at IS NULL AND c.ocean = @ocean
+			ORDER BY r.crew_id, r.scraped_at DESC
+		) latest
+	) pvp, (
+		SELECT COALESCE(SUM(r.daily_pvp_wins), 0) AS recent_pvp_wins,
+			COALESCE(SUM(r.daily_pvp_losses), 0) AS recent_pvp_losses
+		FROM crew_battle_records r
+		JOIN crews c ON c.id = r.crew_id AND c.deleted_at IS NULL
+		WHERE r.deleted_at IS NULL AND c.ocean = @ocean AND r.scraped_at >= @recent
+	) recent, (
+		SELECT COALESCE(AVG(tax_value), 0) AS avg_tax_rate, COUNT(*) AS taxed_commodities
+		FROM (
+			SELECT DISTINCT ON (commodity_id) tax_value
+			FROM commodity_tax_rates
+			WHERE deleted_at IS NULL AND ocean = @ocean
+			ORDER BY commodity_id, scraped_at DESC
+		) latest
+	) taxes, (
+		SELECT MAX(ended_at) AS last_scraped_at
+		FROM scrape_jobs
+		WHERE deleted_at IS NULL AND ocean = @ocean AND status = @completed
+	) jobs
+`
+
+// GetStats aggregates an ocean's current state. Recent PvP activity covers the battle
+// records scraped since recentSince.
+func (r *OceanRepository) GetStats(ocean types.Ocean, recentSince time.Time) (*OceanStats, error) {
+	var stats OceanStats
+	err := r.db.Raw(oceanStatsQuery, map[string]interface{}{
+		"ocean":     ocean,
+		"recent":    recentSince,
+		"completed": models.ScrapeJobStatusCompleted,
+	}).Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// GetLastScrapedTimes returns when each ocean's last completed scrape job ended, keyed by ocean.
+// Oceans that were never scraped are left out.
+func (r *OceanRepository) GetLastScrapedTimes() (map[types.Ocean]time.Time, error) {
+	var rows []struct {
+		Ocean         types.Ocean
+		LastScrapedAt time.Time
+	}
+	err := r.db.Model(&models.ScrapeJob{}).
+		Select("ocean, MAX(ended_at) AS last_scraped_at").
+		Where("status = ? AND ended_at IS NOT NULL", models.ScrapeJobStatusCompleted).
+		Group("ocean").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	times := make(map[types.Ocean]time.Time, len(rows))
+	for _, row := range rows {
+		times[row.Ocean] = row.LastScrapedAt
+	}
+	return times, nil
+}
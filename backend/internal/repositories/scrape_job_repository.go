@@ -90,6 +90,22 @@ func (r *ScrapeJobRepository) GetLastCompleted(ocean types.Ocean, jobType models
 	return &job, nil
 }
 
+// GetLatest returns the most recently started job of jobType for the ocean, leaving out jobs that
+// reprocessed archived pages
+func (r *ScrapeJobRepository) GetLatest(ocean types.Ocean, jobType models.ScrapeJobType) (*models.ScrapeJob, error) {
+	var job models.ScrapeJob
+	err := r.db.Where("ocean = ? AND job_type = ? AND reprocessed_job_id IS NULL", ocean, jobType).
+		Order("started_at DESC").
+		First(&job).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
 func (r *ScrapeJobRepository) GetStats(ocean types.Ocean) (map[string]interface{}, error) {
 	stats := make(map[string]interface{})
 
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"cutlass_analytics/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ScrapeScheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewScrapeScheduleRepository(db *gorm.DB) *ScrapeScheduleRepository {
+	return &ScrapeScheduleRepository{db: db}
+}
+
+func (r *ScrapeScheduleRepository) List() ([]models.ScrapeSchedule, error) {
+	var schedules []models.ScrapeSchedule
+	err := r.db.Order("ocean ASC, job_type ASC").Find(&schedules).Error
+	return schedules, err
+}
+
+// Save creates the schedule or replaces the one already stored for its ocean and job type
+func (r *ScrapeScheduleRepository) Save(schedule *models.ScrapeSchedule) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "ocean"}, {Name: "job_type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"frequency", "time", "is_enabled", "updated_at"}),
+	}).Create(schedule).Error
+}
@@ -0,0 +1,155 @@
+package repositories
+
+import (
+	"cutlass_analytics/internal/types"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type SearchRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchRepository(db *gorm.DB) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+// Entity types a search hit can refer to
+const (
+	SearchTypeCrew   = "crew"
+	SearchTypeFlag   = "flag"
+	SearchTypeIsland = "island"
+)
+
+// SearchFilter narrows a search. Zero values search everything.
+type SearchFilter struct {
+	Ocean types.Ocean
+	Types []string
+
+	// IsActive applies to crews and flags, IsColonized to islands
+	IsActive    *bool
+	IsColonized *bool
+}
+
+func (f *SearchFilter) includes(entityType string) bool {
+	if len(f.Types) == 0 {
+		return true
+	}
+	for _, t := range f.Types {
+		if t == entityType {
+			return true
+		}
+	}
+	return false
+}
+
+// SearchHit is an entity whose name resembles the query. MatchedName is the name that
+// matched, which differs from Name when the hit came from one of the entity's former names.
+type SearchHit struct {
+	EntityType  string
+	EntityID    uint
+	Name        string
+	MatchedName string
+	Historical  bool
+	Score       float64
+}
+
+// searchQuery ranks the candidate names against @query, keeping the best matching name of
+// each entity. An exact match scores 1; anything else scores its trigram similarity, or its
+// word similarity when the query matches part of a longer name.
+const searchQuery = `
+	WITH candidates AS (
+		%s
+	)
+	SELECT DISTINCT ON (entity_type, entity_id) entity_type, entity_id, name, matched_name, historical,
+		CASE WHEN lower(matched_name) = @query THEN 1.0
+			ELSE GREATEST(similarity(lower(matched_name), @query), word_similarity(@query, lower(matched_name)))
+		END AS score
+	FROM candidates
+	ORDER BY entity_type, entity_id, score DESC, historical
+`
+
+// searchBranch selects the candidates of one entity type whose matchColumn resembles @query,
+// either by trigram similarity, by word similarity or by containing it. The conditions use the
+// lower(name) trigram indexes created by database.CreateSearchIndexes.
+func searchBranch(entityType, from, idColumn, nameColumn, matchColumn string, historical bool, filters []string) string {
+	conditions := append([]string{
+		fmt.Sprintf("(lower(%[1]s) %% @query OR @query <%% lower(%[1]s) OR lower(%[1]s) LIKE @pattern)", matchColumn),
+	}, filters...)
+	return fmt.Sprintf("SELECT '%s' AS entity_type, %s AS entity_id, %s AS name, %s AS matched_name, %t AS historical FROM %s WHERE %s",
+		entityType, idColumn, nameColumn, matchColumn, historical, from, strings.Join(conditions, " AND "))
+}
+
+// searchBranches builds one branch per name source of the entity types the filter includes:
+// current names, and for crews and flags the former names kept in their name history
+func searchBranches(filter SearchFilter) []string {
+	common := []string{"e.deleted_at IS NULL"}
+	if filter.Ocean != "" {
+		common = append(common, "e.ocean = @ocean")
+	}
+	active := common
+	if filter.IsActive != nil {
+		active = append(append([]string{}, common...), "e.is_active = @is_active")
+	}
+	colonized := common
+	if filter.IsColonized != nil {
+		colonized = append(append([]string{}, common...), "e.is_colonized = @is_colonized")
+	}
+
+	var branches []string
+	if filter.includes(SearchTypeCrew) {
+		branches = append(branches, searchBranch(SearchTypeCrew, "crews e", "e.id", "e.name", "e.name", false, active))
+		branches = append(branches, searchBranch(SearchTypeCrew, "crew_name_history h JOIN crews e ON e.id = h.crew_id",
+			"e.id", "e.name", "h.name", true, append([]string{"h.deleted_at IS NULL", "h.ended_at IS NOT NULL"}, active...)))
+	}
+	if filter.includes(SearchTypeFlag) {
+		branches = append(branches, searchBranch(SearchTypeFlag, "flags e", "e.id", "e.name", "e.name", false, active))
+		branches = append(branches, searchBranch(SearchTypeFlag, "flag_name_history h JOIN flags e ON e.id = h.flag_id",
+			"e.id", "e.name", "h.name", true, append([]string{"h.deleted_at IS NULL", "h.ended_at IS NOT NULL"}, active...)))
+	}
+	if filter.includes(SearchTypeIsland) {
+		branches = append(branches, searchBranch(SearchTypeIsland, "islands e", "e.id", "e.name", "e.name", false, colonized))
+	}
+	return branches
+}
+
+// Search finds crews, flags and islands whose names resemble q, tolerating misspellings.
+// Hits are ranked by score, then name; the total counts every hit before pagination.
+func (r *SearchRepository) Search(q string, filter SearchFilter, offset, limit int) ([]SearchHit, int64, error) {
+	branches := searchBranches(filter)
+	if len(branches) == 0 {
+		return []SearchHit{}, 0, nil
+	}
+
+	query := strings.ToLower(strings.TrimSpace(q))
+	params := map[string]interface{}{
+		"query":   query,
+		"pattern": "%" + escapeLike(query) + "%",
+		"ocean":   filter.Ocean,
+	}
+	if filter.IsActive != nil {
+		params["is_active"] = *filter.IsActive
+	}
+	if filter.IsColonized != nil {
+		params["is_colonized"] = *filter.IsColonized
+	}
+
+	hits := r.db.Table("(?) AS hits", r.db.Raw(fmt.Sprintf(searchQuery, strings.Join(branches, "\n\t\tUNION ALL\n\t\t")), params))
+
+	var total int64
+	if err := hits.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var results []SearchHit
+	err := hits.Order("score DESC, name ASC, entity_type ASC, entity_id ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&results).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return results, total, nil
+}
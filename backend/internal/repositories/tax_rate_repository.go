@@ -52,9 +52,7 @@ func (r *TaxRateRepository) CompareAcrossOceans(commodityID uint) ([]models.Comm
 	// Get the latest tax rate for this commodity across all oceans
 	var rates []models.CommodityTaxRate
 	
-	oceans := []types.Ocean{types.OceanEmerald, types.OceanMeridian, types.OceanCerulean}
-	
-	for _, ocean := range oceans {
+	for _, ocean := range types.AllOceans() {
 		var rate models.CommodityTaxRate
 		err := r.db.Where("commodity_id = ? AND ocean = ?", commodityID, ocean).
 			Preload("Commodity").
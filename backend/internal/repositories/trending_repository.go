@@ -0,0 +1,215 @@
+package repositories
+
+import (
+	"cutlass_analytics/internal/types"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TrendingRepository struct {
+	db *gorm.DB
+}
+
+func NewTrendingRepository(db *gorm.DB) *TrendingRepository {
+	return &TrendingRepository{db: db}
+}
+
+// TrendScore is a crew's or flag's PvP movement over a trending window.
+// WinsGained and LossesGained sum the changes between consecutive battle record snapshots;
+// the totals, win rate and crew count are those of the last snapshot in the window.
+type TrendScore struct {
+	EntityID        uint
+	ScrapedAt       time.Time
+	WinsGained      int
+	LossesGained    int
+	TotalPVPWins    int
+	TotalPVPLosses  int
+	WinRateChange   *float64
+	CrewCount       int
+	CrewCountChange int
+	Score           float64
+	Rank            int
+}
+
+// TrendSnapshot is a crew's or flag's record at one scrape in a trending window,
+// with the wins and losses gained since the previous scrape
+type TrendSnapshot struct {
+	EntityID       uint
+	ScrapedAt      time.Time
+	WinsGained     int
+	LossesGained   int
+	TotalPVPWins   int
+	TotalPVPLosses int
+	CrewCount      int
+}
+
+// WinRate returns the snapshot's overall win rate as a percentage
+func (s *TrendSnapshot) WinRate() float64 {
+	total := s.TotalPVPWins + s.TotalPVPLosses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.TotalPVPWins) / float64(total) * 100
+}
+
+// trendDeltasCTE selects the battle records of an ocean scraped after @start together with
+// each crew's last record before it, and computes the wins and losses gained between
+// consecutive records. A crew's first ever record has no gains.
+const trendDeltasCTE = `
+	snapshots AS (
+		SELECT r.crew_id, r.scraped_at, r.total_pvp_wins, r.total_pvp_losses
+		FROM crew_battle_records r
+		JOIN crews c ON c.id = r.crew_id AND c.deleted_at IS NULL
+		WHERE r.deleted_at IS NULL AND c.ocean = @ocean AND r.scraped_at > @start
+		UNION ALL
+		SELECT * FROM (
+			SELECT DISTINCT ON (r.crew_id) r.crew_id, r.scraped_at, r.total_pvp_wins, r.total_pvp_losses
+			FROM crew_battle_records r
+			JOIN crews c ON c.id = r.crew_id AND c.deleted_at IS NULL
+			WHERE r.deleted_at IS NULL AND c.ocean = @ocean AND r.scraped_at <= @start
+			ORDER BY r.crew_id, r.scraped_at DESC
+		) baseline
+	),
+	deltas AS (
+		SELECT snapshots.*,
+			COALESCE(total_pvp_wins - LAG(total_pvp_wins) OVER w, 0) AS wins_gained,
+			COALESCE(total_pvp_losses - LAG(total_pvp_losses) OVER w, 0) AS losses_gained
+		FROM snapshots
+		WINDOW w AS (PARTITION BY crew_id ORDER BY scraped_at)
+	)
+`
+
+// crewTrendSnapshotsQuery lists every crew's snapshots in the window
+const crewTrendSnapshotsQuery = `
+	WITH ` + trendDeltasCTE + `
+	SELECT crew_id AS entity_id, scraped_at, wins_gained, losses_gained,
+		total_pvp_wins, total_pvp_losses, 1 AS crew_count
+	FROM deltas
+	WHERE scraped_at > @start
+`
+
+// flagTrendSnapshotsQuery sums the snapshots of each flag's member crews per scrape.
+// A crew counts toward a flag only for the scrapes made while it was a member.
+const flagTrendSnapshotsQuery = `
+	WITH ` + trendDeltasCTE + `
+	SELECT h.flag_id AS entity_id, d.scraped_at,
+		SUM(d.wins_gained) AS wins_gained, SUM(d.losses_gained) AS losses_gained,
+		SUM(d.total_pvp_wins) AS total_pvp_wins, SUM(d.total_pvp_losses) AS total_pvp_losses,
+		COUNT(*) AS crew_count
+	FROM deltas d
+	JOIN crew_flag_history h ON h.crew_id = d.crew_id AND h.flag_id IS NOT NULL AND h.deleted_at IS NULL
+		AND h.joined_at <= d.scraped_at AND (h.left_at IS NULL OR d.scraped_at < h.left_at)
+	WHERE d.scraped_at > @start
+	GROUP BY h.flag_id, d.scraped_at
+`
+
+// trendScoresQuery rolls the snapshots returned by the inner query up into one row per entity.
+// The win rate change is measured against the first snapshot's record before its gains.
+// The score expression and eligibility filter are filled in by the caller.
+const trendScoresQuery = `
+	WITH trends AS (
+		SELECT entity_id, MAX(scraped_at) AS scraped_at,
+			SUM(wins_gained) AS wins_gained,
+			SUM(losses_gained) AS losses_gained,
+			(ARRAY_AGG(total_pvp_wins ORDER BY scraped_at DESC))[1] AS total_pvp_wins,
+			(ARRAY_AGG(total_pvp_losses ORDER BY scraped_at DESC))[1] AS total_pvp_losses,
+			(ARRAY_AGG(crew_count ORDER BY scraped_at DESC))[1] AS crew_count,
+			(ARRAY_AGG(crew_count ORDER BY scraped_at DESC))[1]
+				- (ARRAY_AGG(crew_count ORDER BY scraped_at ASC))[1] AS crew_count_change,
+			(ARRAY_AGG(total_pvp_wins - wins_gained ORDER BY scraped_at ASC))[1] AS start_wins,
+			(ARRAY_AGG(total_pvp_losses - losses_gained ORDER BY scraped_at ASC))[1] AS start_losses
+		FROM (%[1]s) snapshots
+		GROUP BY entity_id
+	),
+	changes AS (
+		SELECT trends.*,
+			100.0 * total_pvp_wins / NULLIF(total_pvp_wins + total_pvp_losses, 0)
+				- 100.0 * start_wins / NULLIF(start_wins + start_losses, 0) AS win_rate_change
+		FROM trends
+	)
+	SELECT changes.*, %[2]s AS score, RANK() OVER (ORDER BY %[2]s DESC) AS rank
+	FROM changes
+	WHERE %[3]s
+`
+
+// trendScore returns the score expression and eligibility filter for a trending metric.
+// Only entities that rose over the window are eligible.
+func trendScore(metric string) (string, string) {
+	switch metric {
+	case "battles":
+		return "wins_gained + losses_gained", "wins_gained + losses_gained > 0"
+	case "win_rate":
+		return "win_rate_change", "wins_gained + losses_gained > 0 AND win_rate_change > 0"
+	case "crew_count":
+		return "crew_count_change", "crew_count_change > 0"
+	default:
+		return "wins_gained", "wins_gained > 0"
+	}
+}
+
+// GetTrendingCrews ranks the crews of an ocean by how much a metric rose since start.
+// metric is one of wins, battles or win_rate.
+func (r *TrendingRepository) GetTrendingCrews(ocean types.Ocean, start time.Time, metric string, offset, limit int) ([]TrendScore, int64, error) {
+	return r.findTrendScores(crewTrendSnapshotsQuery, ocean, start, metric, offset, limit)
+}
+
+// GetTrendingFlags ranks the flags of an ocean by how much a metric rose since start.
+// metric is one of wins, battles, win_rate or crew_count.
+func (r *TrendingRepository) GetTrendingFlags(ocean types.Ocean, start time.Time, metric string, offset, limit int) ([]TrendScore, int64, error) {
+	return r.findTrendScores(flagTrendSnapshotsQuery, ocean, start, metric, offset, limit)
+}
+
+// GetCrewSnapshots returns the snapshots of the given crews since start, oldest first
+func (r *TrendingRepository) GetCrewSnapshots(ocean types.Ocean, start time.Time, crewIDs []uint) ([]TrendSnapshot, error) {
+	return r.findTrendSnapshots(crewTrendSnapshotsQuery, ocean, start, crewIDs)
+}
+
+// GetFlagSnapshots returns the snapshots of the given flags since start, oldest first
+func (r *TrendingRepository) GetFlagSnapshots(ocean types.Ocean, start time.Time, flagIDs []uint) ([]TrendSnapshot, error) {
+	return r.findTrendSnapshots(flagTrendSnapshotsQuery, ocean, start, flagIDs)
+}
+
+func (r *TrendingRepository) findTrendScores(snapshotsQuery string, ocean types.Ocean, start time.Time, metric string, offset, limit int) ([]TrendScore, int64, error) {
+	score, eligible := trendScore(metric)
+	query := fmt.Sprintf(trendScoresQuery, snapshotsQuery, score, eligible)
+	trends := r.db.Table("(?) AS trends", r.db.Raw(query, map[string]interface{}{
+		"ocean": ocean,
+		"start": start,
+	}))
+
+	// Count total before pagination
+	var total int64
+	if err := trends.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var results []TrendScore
+	err := trends.Order("rank ASC, wins_gained DESC, entity_id ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&results).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return results, total, nil
+}
+
+func (r *TrendingRepository) findTrendSnapshots(snapshotsQuery string, ocean types.Ocean, start time.Time, ids []uint) ([]TrendSnapshot, error) {
+	var snapshots []TrendSnapshot
+	if len(ids) == 0 {
+		return snapshots, nil
+	}
+	err := r.db.Table("(?) AS snapshots", r.db.Raw(snapshotsQuery, map[string]interface{}{
+		"ocean": ocean,
+		"start": start,
+	})).
+		Where("entity_id IN ?", ids).
+		Order("entity_id ASC, scraped_at ASC").
+		Find(&snapshots).Error
+	if err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
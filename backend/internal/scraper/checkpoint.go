@@ -0,0 +1,115 @@
+package scraper
+
+import (
+	"cutlass_analytics/internal/fetcher"
+	"cutlass_analytics/internal/models"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrInterrupted is the cancellation cause that stops a job to be resumed later, such as a server
+// shutdown. Run marks such a job interrupted and keeps its checkpoints instead of cancelling it.
+var ErrInterrupted = errors.New("scrape job interrupted")
+
+// Stages of a scrape job. A daily full scrape goes through them in this order; the other job
+// types have a single stage.
+const (
+	stageIslands    = "islands"
+	stageTaxRates   = "tax_rates"
+	stageCrews      = "crews"
+	stageFlags      = "flags"
+	stageBattleInfo = "battle_info"
+)
+
+// ResumeScraper creates a scraper that continues an interrupted job from the stage it was in,
+// skipping the items it had checkpointed there and stamping the rest with the stage's scrape time
+func ResumeScraper(db *gorm.DB, f fetcher.Fetcher, job *models.ScrapeJob) (*Scraper, error) {
+	if job.IsReprocessing() {
+		return nil, fmt.Errorf("scrape job %d reprocesses archived pages and can't be resumed", job.ID)
+	}
+
+	var checkpoints []models.ScrapeJobCheckpoint
+	if err := db.Where("scrape_job_id = ?", job.ID).Find(&checkpoints).Error; err != nil {
+		return nil, fmt.Errorf("failed to load checkpoints: %w", err)
+	}
+	done := make(map[string]map[uint64]models.CheckpointOutcome)
+	for _, checkpoint := range checkpoints {
+		if done[checkpoint.Stage] == nil {
+			done[checkpoint.Stage] = make(map[uint64]models.CheckpointOutcome)
+		}
+		done[checkpoint.Stage][checkpoint.ItemID] = checkpoint.Outcome
+	}
+
+	job.Status = models.ScrapeJobStatusRunning
+	job.EndedAt = nil
+	job.ResumeCount++
+	if err := db.Save(job).Error; err != nil {
+		return nil, fmt.Errorf("failed to resume scrape job: %w", err)
+	}
+
+	return &Scraper{
+		db:      db,
+		fetcher: f,
+		job:     job,
+		ocean:   job.Ocean,
+		done:    done,
+	}, nil
+}
+
+// enterStage records that the job is working on stage, so it resumes there if interrupted
+func (s *Scraper) enterStage(stage string) {
+	if s.job.Stage == stage {
+		return
+	}
+	s.job.Stage = stage
+	s.job.StageScrapedAt = nil
+	s.db.Save(s.job)
+}
+
+// stageScrapeTime returns the time the records of the current stage are stamped with. It's saved
+// on the job, so the records a resumed job scrapes in the stage still share it with the others
+// and the stage remains a single scrape.
+func (s *Scraper) stageScrapeTime() time.Time {
+	if s.job.StageScrapedAt != nil {
+		return *s.job.StageScrapedAt
+	}
+	scrapedAt := s.scrapeTime()
+	s.job.StageScrapedAt = &scrapedAt
+	s.db.Save(s.job)
+	return scrapedAt
+}
+
+// finished returns the outcome of an item the job had finished with before it was resumed
+func (s *Scraper) finished(stage string, itemID uint64) (models.CheckpointOutcome, bool) {
+	outcome, ok := s.done[stage][itemID]
+	return outcome, ok
+}
+
+// checkpoint records that the job has finished with an item. A failure to record it only means
+// the item is scraped again if the job is resumed.
+func (s *Scraper) checkpoint(stage string, itemID uint64, outcome models.CheckpointOutcome) {
+	// A job reprocessing archived pages can't be resumed
+	if s.reprocessing() {
+		return
+	}
+	checkpoint := models.ScrapeJobCheckpoint{
+		ScrapeJobID: s.job.ID,
+		Stage:       stage,
+		ItemID:      itemID,
+		Outcome:     outcome,
+	}
+	if err := s.db.Create(&checkpoint).Error; err != nil {
+		log.Printf("Failed to checkpoint %s item %d of job %d: %v", stage, itemID, s.job.ID, err)
+	}
+}
+
+// clearCheckpoints deletes the job's checkpoints once it can no longer be resumed
+func (s *Scraper) clearCheckpoints() {
+	if err := s.db.Unscoped().Where("scrape_job_id = ?", s.job.ID).Delete(&models.ScrapeJobCheckpoint{}).Error; err != nil {
+		log.Printf("Failed to clear checkpoints of job %d: %v", s.job.ID, err)
+	}
+}
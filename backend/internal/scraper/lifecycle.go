@@ -0,0 +1,145 @@
+package scraper
+
+import (
+	"cutlass_analytics/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// lifecycleMissThreshold is how many scrapes in a row have to miss a crew or flag before it is
+// marked inactive. A single miss is usually the fame list shifting or a page failing to load.
+const lifecycleMissThreshold = 3
+
+// reconcileCrews updates the lifecycle of every crew of the ocean after a crew scrape. Crews in
+// seen were found and reappear if they had been disbanded; active crews that weren't found count a
+// miss. Crews in skipped failed to load for reasons unrelated to the crew and are left alone.
+func (s *Scraper) reconcileCrews(seen, skipped map[uint64]bool, scrapedAt time.Time) error {
+	var crews []models.Crew
+	if err := s.db.Where("ocean = ?", s.ocean).Find(&crews).Error; err != nil {
+		return err
+	}
+
+	for i := range crews {
+		crew := &crews[i]
+		if skipped[crew.GameCrewID] {
+			continue
+		}
+
+		var err error
+		if seen[crew.GameCrewID] {
+			if crew.IsActive && crew.MissedScrapes == 0 {
+				continue
+			}
+			err = s.recordCrewSighting(crew, scrapedAt)
+		} else {
+			if !crew.IsActive {
+				continue
+			}
+			err = s.recordCrewMiss(crew, scrapedAt)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileFlags updates the lifecycle of every flag of the ocean after a flag scrape, the same
+// way reconcileCrews does for crews
+func (s *Scraper) reconcileFlags(seen, skipped map[uint64]bool, scrapedAt time.Time) error {
+	var flags []models.Flag
+	if err := s.db.Where("ocean = ?", s.ocean).Find(&flags).Error; err != nil {
+		return err
+	}
+
+	for i := range flags {
+		flag := &flags[i]
+		if skipped[flag.GameFlagID] {
+			continue
+		}
+
+		var err error
+		if seen[flag.GameFlagID] {
+			if flag.IsActive && flag.MissedScrapes == 0 {
+				continue
+			}
+			err = s.recordFlagSighting(flag, scrapedAt)
+		} else {
+			if !flag.IsActive {
+				continue
+			}
+			err = s.recordFlagMiss(flag, scrapedAt)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordCrewMiss counts a scrape that missed the crew, recording a disbanded event once it has
+// missed lifecycleMissThreshold in a row
+func (s *Scraper) recordCrewMiss(crew *models.Crew, scrapedAt time.Time) error {
+	disbanded := crew.RecordMiss(lifecycleMissThreshold)
+	return s.saveCrewLifecycle(crew, disbanded, models.LifecycleEventDisbanded, crew.MissedScrapes, scrapedAt)
+}
+
+// recordCrewSighting resets the misses of a crew a scrape found, recording a reappeared event if it
+// had been disbanded
+func (s *Scraper) recordCrewSighting(crew *models.Crew, scrapedAt time.Time) error {
+	missed := crew.MissedScrapes
+	reappeared := crew.RecordSighting()
+	return s.saveCrewLifecycle(crew, reappeared, models.LifecycleEventReappeared, missed, scrapedAt)
+}
+
+func (s *Scraper) saveCrewLifecycle(crew *models.Crew, changed bool, eventType models.LifecycleEventType, missed int, scrapedAt time.Time) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(crew).Select("missed_scrapes", "is_active").Updates(crew).Error; err != nil {
+			return err
+		}
+		if !changed {
+			return nil
+		}
+		return tx.Create(&models.CrewLifecycleEvent{
+			CrewID:        crew.ID,
+			EventType:     eventType,
+			OccurredAt:    scrapedAt,
+			MissedScrapes: missed,
+			ScrapeJobID:   &s.job.ID,
+		}).Error
+	})
+}
+
+// recordFlagMiss counts a scrape that missed the flag, recording a dissolved event once it has
+// missed lifecycleMissThreshold in a row
+func (s *Scraper) recordFlagMiss(flag *models.Flag, scrapedAt time.Time) error {
+	dissolved := flag.RecordMiss(lifecycleMissThreshold)
+	return s.saveFlagLifecycle(flag, dissolved, models.LifecycleEventDissolved, flag.MissedScrapes, scrapedAt)
+}
+
+// recordFlagSighting resets the misses of a flag a scrape found, recording a reappeared event if it
+// had been dissolved
+func (s *Scraper) recordFlagSighting(flag *models.Flag, scrapedAt time.Time) error {
+	missed := flag.MissedScrapes
+	reappeared := flag.RecordSighting()
+	return s.saveFlagLifecycle(flag, reappeared, models.LifecycleEventReappeared, missed, scrapedAt)
+}
+
+func (s *Scraper) saveFlagLifecycle(flag *models.Flag, changed bool, eventType models.LifecycleEventType, missed int, scrapedAt time.Time) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(flag).Select("missed_scrapes", "is_active").Updates(flag).Error; err != nil {
+			return err
+		}
+		if !changed {
+			return nil
+		}
+		return tx.Create(&models.FlagLifecycleEvent{
+			FlagID:        flag.ID,
+			EventType:     eventType,
+			OccurredAt:    scrapedAt,
+			MissedScrapes: missed,
+			ScrapeJobID:   &s.job.ID,
+		}).Error
+	})
+}
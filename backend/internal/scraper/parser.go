@@ -3,6 +3,7 @@ package scraper
 import (
 	"cutlass_analytics/internal/types"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"regexp"
@@ -14,6 +15,13 @@ import (
 	"github.com/PuerkitoBio/goquery"
 )
 
+// ErrCrewNotFound and ErrFlagNotFound are returned for info pages of crews and flags the game
+// no longer knows, which say "no such crew" or "no such flag" instead
+var (
+	ErrCrewNotFound = errors.New("no such crew")
+	ErrFlagNotFound = errors.New("no such flag")
+)
+
 // IslandData represents parsed island information
 type IslandData struct {
 	GameIslandID  uint64
@@ -75,6 +83,22 @@ type FlagData struct {
 	MagnateReputation   *types.FameLevel
 }
 
+// Reputations returns the reputation levels found on the flag info page, keyed by reputation type
+func (f *FlagData) Reputations() map[types.ReputationType]types.FameLevel {
+	reputations := make(map[types.ReputationType]types.FameLevel)
+	for reputationType, level := range map[types.ReputationType]*types.FameLevel{
+		types.ReputationConqueror: f.ConquerorReputation,
+		types.ReputationExplorer:  f.ExplorerReputation,
+		types.ReputationPatron:    f.PatronReputation,
+		types.ReputationMagnate:   f.MagnateReputation,
+	} {
+		if level != nil {
+			reputations[reputationType] = *level
+		}
+	}
+	return reputations
+}
+
 
 // ParseIslandList parses the island list page (showAll=true)
 func ParseIslandList(html string, ocean types.Ocean) ([]IslandData, error) {
@@ -490,6 +514,9 @@ func ParseCrewInfo(html string, crewID uint64, ocean types.Ocean) (*CrewData, er
 	if err != nil {
 		return crew, fmt.Errorf("ParseCrewInfo: failed to parse HTML: %w", err)
 	}
+	if pageSays(doc, "no such crew") {
+		return crew, ErrCrewNotFound
+	}
 
 	// Crew info on this page lives in the first td with width ~246.
 	// - Name: first <font> contains <b>Name</b>
@@ -689,6 +716,9 @@ func ParseFlagInfo(html string, flagID uint64, ocean types.Ocean) (*FlagData, er
 	if err != nil {
 		return flag, fmt.Errorf("ParseFlagInfo: failed to parse HTML: %w", err)
 	}
+	if pageSays(doc, "no such flag") {
+		return flag, ErrFlagNotFound
+	}
 
 	// Find td[width="246"] elements (handle both single and double quotes)
 	infoCells := doc.Find(`td[width="246"], td[width='246']`)
@@ -740,6 +770,11 @@ func ParseFlagInfo(html string, flagID uint64, ocean types.Ocean) (*FlagData, er
 	return flag, nil
 }
 
+// pageSays reports whether the text of a page contains message, ignoring case
+func pageSays(doc *goquery.Document, message string) bool {
+	return strings.Contains(strings.ToLower(doc.Text()), message)
+}
+
 // parseFameLevelFromText matches text against FameLevel enum values
 func parseFameLevelFromText(text string) types.FameLevel {
 	textLower := strings.ToLower(text)
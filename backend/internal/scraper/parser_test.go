@@ -349,6 +349,18 @@ func TestParseCrewInfo(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "no such crew",
+			html: `<html><body>
+				<p>No such crew.</p>
+			</body></html>`,
+			crewID: 424242,
+			ocean:  types.OceanEmerald,
+			want: &CrewData{
+				GameCrewID: 424242,
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -638,6 +650,18 @@ func TestParseFlagInfo(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "no such flag",
+			html: `<html><body>
+				<p>No such flag.</p>
+			</body></html>`,
+			flagID: 424242,
+			ocean:  types.OceanEmerald,
+			want: &FlagData{
+				GameFlagID: 424242,
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -669,6 +693,27 @@ func TestParseFlagInfo(t *testing.T) {
 	}
 }
 
+func TestFlagDataReputations(t *testing.T) {
+	flag := FlagData{
+		ConquerorReputation: fameLevelPtr(types.FameLevelRenowned),
+		PatronReputation:    fameLevelPtr(types.FameLevelNoted),
+	}
+
+	got := flag.Reputations()
+	want := map[types.ReputationType]types.FameLevel{
+		types.ReputationConqueror: types.FameLevelRenowned,
+		types.ReputationPatron:    types.FameLevelNoted,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Reputations() = %v, want %v", got, want)
+	}
+	for reputationType, level := range want {
+		if got[reputationType] != level {
+			t.Errorf("Reputations()[%s] = %v, want %v", reputationType, got[reputationType], level)
+		}
+	}
+}
+
 func TestParseIslandList(t *testing.T) {
 	tests := []struct {
 		name    string
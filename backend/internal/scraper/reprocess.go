@@ -0,0 +1,103 @@
+package scraper
+
+import (
+	"context"
+	"cutlass_analytics/internal/archive"
+	"cutlass_analytics/internal/models"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReprocessHeartbeat is how often a reprocess job, which runs outside the server, touches its
+// updated_at so the server can tell a job still running from one whose process died
+const ReprocessHeartbeat = time.Minute
+
+// NewReprocessor creates a scraper that runs the scrape job jobID again over the pages it
+// archived, without any network access. It is recorded as a new scrape job of the same type and
+// ocean. Records parsed from the pages replace the ones the job wrote, while the current state of
+// islands, crews and flags is left as the latest scrape left it.
+func NewReprocessor(db *gorm.DB, jobID uint) (*Scraper, error) {
+	var source models.ScrapeJob
+	if err := db.First(&source, jobID).Error; err != nil {
+		return nil, fmt.Errorf("failed to load scrape job %d: %w", jobID, err)
+	}
+
+	// A reprocessing job archives nothing, so reprocessing it again means reprocessing its source
+	if source.IsReprocessing() {
+		if err := db.First(&source, *source.ReprocessedJobID).Error; err != nil {
+			return nil, fmt.Errorf("failed to load scrape job %d: %w", *source.ReprocessedJobID, err)
+		}
+	}
+	if source.IsRunning() {
+		return nil, fmt.Errorf("scrape job %d is still running", source.ID)
+	}
+
+	hasPages, err := archive.HasPages(db, source.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check archived pages: %w", err)
+	}
+	if !hasPages {
+		return nil, fmt.Errorf("scrape job %d has no archived pages", source.ID)
+	}
+
+	job := &models.ScrapeJob{
+		Ocean:            source.Ocean,
+		JobType:          source.JobType,
+		StartedAt:        now(),
+		Status:           models.ScrapeJobStatusRunning,
+		ReprocessedJobID: &source.ID,
+	}
+	if err := db.Create(job).Error; err != nil {
+		return nil, fmt.Errorf("failed to create scrape job: %w", err)
+	}
+
+	return &Scraper{
+		db:     db,
+		job:    job,
+		ocean:  source.Ocean,
+		source: &source,
+	}, nil
+}
+
+// reprocessing reports whether the scraper parses archived pages instead of fetching them
+func (s *Scraper) reprocessing() bool {
+	return s.source != nil
+}
+
+// fetchArchived returns the page the reprocessed job archived for url
+func (s *Scraper) fetchArchived(url string) (string, error) {
+	page, err := archive.Load(s.db, s.source.ID, url)
+	if err != nil {
+		return "", err
+	}
+
+	body, err := page.Content()
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress archived page: %w", err)
+	}
+	s.fetchedAt = page.FetchedAt
+	return string(body), nil
+}
+
+// heartbeat touches the job's updated_at every ReprocessHeartbeat until ctx is done
+func (s *Scraper) heartbeat(ctx context.Context) {
+	jobID := s.job.ID
+	ticker := time.NewTicker(ReprocessHeartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := s.db.Model(&models.ScrapeJob{}).Where("id = ?", jobID).
+				UpdateColumn("updated_at", now()).Error
+			if err != nil {
+				log.Printf("Failed to record heartbeat of scrape job %d: %v", jobID, err)
+			}
+		}
+	}
+}
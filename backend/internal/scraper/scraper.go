@@ -1,95 +1,166 @@
 package scraper
 
 import (
+	"context"
+	"cutlass_analytics/internal/archive"
+	"cutlass_analytics/internal/fetcher"
 	"cutlass_analytics/internal/models"
 	"cutlass_analytics/internal/types"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
 	"time"
 
-	"github.com/gocolly/colly/v2"
-	"github.com/gocolly/colly/v2/debug"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
+// RequestDelay is how long a scraper's live fetcher should wait between requests
+const RequestDelay = 1 * time.Second
+
 // Scraper handles web scraping operations
 type Scraper struct {
-	db        *gorm.DB
-	collector *colly.Collector
-	job       *models.ScrapeJob
-	ocean     types.Ocean
+	db      *gorm.DB
+	fetcher fetcher.Fetcher
+	job     *models.ScrapeJob
+	ocean   types.Ocean
+
+	// source is the job whose archived pages are being reprocessed, nil when fetching
+	source *models.ScrapeJob
+	// fetchedAt is when the last page was fetched
+	fetchedAt time.Time
+	// done holds the outcomes of the items a resumed job had finished with, by stage
+	done map[string]map[uint64]models.CheckpointOutcome
 }
 
-// fetchHTML fetches HTML content from a URL
-func (s *Scraper) fetchHTML(url string) (string, error) {
-	var htmlContent string
-	var fetchErr error
+// now returns the current time truncated to the microsecond postgres stores, so fetch times read
+// back from the page archive equal the times records were stamped with
+func now() time.Time {
+	return time.Now().Truncate(time.Microsecond)
+}
 
-	// Create a temporary collector for this request to avoid handler conflicts
-	tempCollector := s.collector.Clone()
-	tempCollector.OnResponse(func(r *colly.Response) {
-		htmlContent = string(r.Body)
-	})
-	tempCollector.OnError(func(r *colly.Response, err error) {
-		fetchErr = err
-	})
+// fetchHTML fetches HTML content from a URL and archives it under the scrape job. When
+// reprocessing, the page comes from the archive of the reprocessed job instead.
+func (s *Scraper) fetchHTML(url string) (string, error) {
+	if s.reprocessing() {
+		return s.fetchArchived(url)
+	}
 
-	if err := tempCollector.Visit(url); err != nil {
+	body, err := s.fetcher.Fetch(url)
+	if err != nil {
 		return "", err
 	}
+	s.fetchedAt = now()
 
-	if fetchErr != nil {
-		return "", fetchErr
+	if _, err := archive.Store(s.db, &s.job.ID, url, body, s.fetchedAt); err != nil {
+		log.Printf("Failed to archive %s: %v", url, err)
 	}
+	return string(body), nil
+}
 
-	return htmlContent, nil
+// scrapeTime is the time to stamp the records parsed from the pages fetched so far with: when the
+// last page was fetched, or when the job started if no page was fetched yet. Reprocessing replays
+// the fetch times of the archived pages, so its records land on the same timestamps the
+// reprocessed job used and replace them.
+func (s *Scraper) scrapeTime() time.Time {
+	if !s.fetchedAt.IsZero() {
+		return s.fetchedAt
+	}
+	if s.reprocessing() {
+		return s.source.StartedAt
+	}
+	return s.job.StartedAt
 }
 
-// NewScraper creates a new scraper instance with rate limiting
-func NewScraper(db *gorm.DB, ocean types.Ocean, jobType models.ScrapeJobType) (*Scraper, error) {
-	// Create scrape job
-	job := &models.ScrapeJob{
-		Ocean:   ocean,
-		JobType: jobType,
-		Status:  models.ScrapeJobStatusRunning,
+// battleRecordColumns are the crew battle record columns a scrape fills in
+var battleRecordColumns = []string{"crew_rank", "total_pvp_wins", "total_pvp_losses", "daily_pvp_wins", "daily_pvp_losses"}
+
+// upsertRecord creates a time-series record, overwriting the columns in values when a record
+// already exists for the key columns, so reprocessing replaces what an older parser wrote
+func upsertRecord(tx *gorm.DB, record interface{}, key []string, values []string) error {
+	columns := make([]clause.Column, len(key))
+	for i, name := range key {
+		columns[i] = clause.Column{Name: name}
 	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   columns,
+		DoUpdates: clause.AssignmentColumns(append(values, "updated_at")),
+	}).Create(record).Error
+}
 
-	if err := db.Create(job).Error; err != nil {
-		return nil, fmt.Errorf("failed to create scrape job: %w", err)
+// saveBattleRecord stores a crew battle record with its deltas from the crew's previous record.
+// A reprocessed record can have later records after it, so the deltas of the crew's next record
+// are recomputed from the corrected totals.
+func (s *Scraper) saveBattleRecord(tx *gorm.DB, record *models.CrewBattleRecord) error {
+	var prevRecord models.CrewBattleRecord
+	err := tx.Where("crew_id = ? AND scraped_at < ?", record.CrewID, record.ScrapedAt).
+		Order("scraped_at DESC").First(&prevRecord).Error
+	if err == nil {
+		record.CalculateDeltas(&prevRecord)
+	} else {
+		record.CalculateDeltas(nil)
 	}
 
-	// Create collector with rate limiting
-	collector := colly.NewCollector(
-		colly.Debugger(&debug.LogDebugger{}),
-	)
+	if err := upsertRecord(tx, record, []string{"crew_id", "scraped_at"}, battleRecordColumns); err != nil {
+		return err
+	}
+	if !s.reprocessing() {
+		return nil
+	}
 
-	// Set rate limiting: 1 request per second
-	collector.Limit(&colly.LimitRule{
-		DomainGlob:  "*.puzzlepirates.com",
-		Parallelism: 1,
-		Delay:       1 * time.Second,
-	})
+	var nextRecord models.CrewBattleRecord
+	err = tx.Where("crew_id = ? AND scraped_at > ?", record.CrewID, record.ScrapedAt).
+		Order("scraped_at ASC").First(&nextRecord).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load next battle record: %w", err)
+	}
+	nextRecord.CalculateDeltas(record)
+	return tx.Model(&nextRecord).Updates(map[string]interface{}{
+		"daily_pvp_wins":   nextRecord.DailyPVPWins,
+		"daily_pvp_losses": nextRecord.DailyPVPLosses,
+	}).Error
+}
 
-	// Set user agent
-	collector.UserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36"
+// NewScraper creates a new scraper instance fetching pages through f
+func NewScraper(db *gorm.DB, f fetcher.Fetcher, ocean types.Ocean, jobType models.ScrapeJobType) (*Scraper, error) {
+	// Create scrape job
+	job := &models.ScrapeJob{
+		Ocean:     ocean,
+		JobType:   jobType,
+		StartedAt: now(),
+		Status:    models.ScrapeJobStatusRunning,
+	}
 
-	// Handle errors
-	collector.OnError(func(r *colly.Response, err error) {
-		log.Printf("Request URL: %s failed with error: %v\n", r.Request.URL, err)
-	})
+	if err := db.Create(job).Error; err != nil {
+		return nil, fmt.Errorf("failed to create scrape job: %w", err)
+	}
 
 	return &Scraper{
-		db:        db,
-		collector: collector,
-		job:       job,
-		ocean:     ocean,
+		db:      db,
+		fetcher: f,
+		job:     job,
+		ocean:   ocean,
 	}, nil
 }
 
-// Run executes the scraper based on job type
-func (s *Scraper) Run() error {
+// Job returns the scrape job the scraper records its work in
+func (s *Scraper) Job() *models.ScrapeJob {
+	return s.job
+}
+
+// Run executes the scraper based on job type. Cancelling ctx stops the job before its next page
+// and marks it cancelled, or interrupted when the cause is ErrInterrupted.
+func (s *Scraper) Run(ctx context.Context) error {
+	if s.reprocessing() {
+		beatCtx, stopBeating := context.WithCancel(ctx)
+		defer stopBeating()
+		go s.heartbeat(beatCtx)
+	}
+
 	defer func() {
 		// Reload job to get latest counters
 		s.db.First(s.job, s.job.ID)
@@ -98,101 +169,132 @@ func (s *Scraper) Run() error {
 				log.Printf("Failed to mark job as completed: %v", err)
 			}
 		}
+		if s.job.Status != models.ScrapeJobStatusInterrupted {
+			s.clearCheckpoints()
+		}
 	}()
 
+	var err error
 	switch s.job.JobType {
 	case models.ScrapeJobTypeDailyFull:
-		// Run all scrapers
-		if err := s.ScrapeIslands(); err != nil {
-			log.Printf("Error scraping islands: %v", err)
-			s.job.IncrementFailed()
-		}
-		if err := s.ScrapeTaxRates(); err != nil {
-			log.Printf("Error scraping tax rates: %v", err)
-			s.job.IncrementFailed()
-		}
-		if err := s.ScrapeCrews(); err != nil {
-			log.Printf("Error scraping crews: %v", err)
-			s.job.IncrementFailed()
+		// Run all scrapers, starting at the stage a resumed job was interrupted in
+		steps := []struct {
+			stage  string
+			name   string
+			scrape func(context.Context) error
+		}{
+			{stageIslands, "islands", s.ScrapeIslands},
+			{stageTaxRates, "tax rates", s.ScrapeTaxRates},
+			{stageCrews, "crews", s.ScrapeCrews},
+			{stageFlags, "flags", s.ScrapeFlags},
 		}
-		if err := s.ScrapeFlags(); err != nil {
-			log.Printf("Error scraping flags: %v", err)
-			s.job.IncrementFailed()
+		resumeAt := s.job.Stage
+		for _, step := range steps {
+			if ctx.Err() != nil {
+				break
+			}
+			if resumeAt != "" && step.stage != resumeAt {
+				continue
+			}
+			resumeAt = ""
+
+			if err := step.scrape(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Error scraping %s: %v", step.name, err)
+				s.job.IncrementFailed()
+			}
 		}
 	case models.ScrapeJobTypeCrewInfo:
-		if err := s.ScrapeCrews(); err != nil {
-			return s.job.MarkFailed(s.db, err)
-		}
+		err = s.ScrapeCrews(ctx)
 	case models.ScrapeJobTypeCrewFame:
-		if err := s.ScrapeCrewFame(); err != nil {
-			return s.job.MarkFailed(s.db, err)
-		}
+		err = s.ScrapeCrewFame(ctx)
 	case models.ScrapeJobTypeFlagFame:
-		if err := s.ScrapeFlagFame(); err != nil {
-			return s.job.MarkFailed(s.db, err)
-		}
+		err = s.ScrapeFlagFame(ctx)
 	case models.ScrapeJobTypeBattleInfo:
-		if err := s.ScrapeBattleInfo(); err != nil {
-			return s.job.MarkFailed(s.db, err)
-		}
+		err = s.ScrapeBattleInfo(ctx)
 	}
 
+	if ctx.Err() != nil {
+		if errors.Is(context.Cause(ctx), ErrInterrupted) {
+			return s.job.MarkInterrupted(s.db)
+		}
+		return s.job.MarkCancelled(s.db)
+	}
+	if err != nil {
+		return s.job.MarkFailed(s.db, err)
+	}
 	return nil
 }
 
 // ScrapeIslands scrapes all island data by looping through island IDs 0-120
-func (s *Scraper) ScrapeIslands() error {
-	scrapedAt := time.Now()
+func (s *Scraper) ScrapeIslands(ctx context.Context) error {
+	s.enterStage(stageIslands)
+	scrapedAt := s.stageScrapeTime()
 	processedCount := 0
 
 	// Loop through island IDs from 0 to 120
 	for islandID := uint64(0); islandID <= 120; islandID++ {
-		url := GetIslandInfoURL(s.ocean, islandID)
-		htmlContent, err := s.fetchHTML(url)
-		if err != nil {
-			log.Printf("Failed to fetch island %d: %v", islandID, err)
-			s.job.IncrementFailed()
-			continue
+		if err := ctx.Err(); err != nil {
+			return err
 		}
-
-		// Check if island is uncolonized
-		if strings.Contains(htmlContent, "Shiver me timbers: The island is uncolonized.") {
-			// Skip uncolonized islands
+		if _, ok := s.finished(stageIslands, islandID); ok {
 			continue
 		}
 
-		// Parse island info
-		islandData, err := ParseIslandInfo(htmlContent, islandID, s.ocean)
-		if err != nil {
-			log.Printf("Failed to parse island %d: %v", islandID, err)
-			s.job.IncrementFailed()
-			continue
+		outcome := s.scrapeIsland(islandID, scrapedAt)
+		s.checkpoint(stageIslands, islandID, outcome)
+		if outcome == models.CheckpointProcessed {
+			processedCount++
 		}
+	}
 
-		// Skip empty islands (no name or zero population)
-		if islandData.Name == "" {
-			log.Printf("Skipping island %d: empty name", islandID)
-			continue
-		}
-		if islandData.Population <= 0 {
-			log.Printf("Skipping island %d (%s): no population", islandID, islandData.Name)
-			continue
-		}
+	log.Printf("Successfully processed %d islands for ocean %s", processedCount, s.ocean)
+	return nil
+}
 
-		// Process and save island
-		if err := s.processIsland(*islandData, scrapedAt); err != nil {
-			log.Printf("Error processing island %d: %v", islandID, err)
-			s.job.IncrementFailed()
-			continue
-		}
+// scrapeIsland fetches and saves a single island
+func (s *Scraper) scrapeIsland(islandID uint64, scrapedAt time.Time) models.CheckpointOutcome {
+	url := GetIslandInfoURL(s.ocean, islandID)
+	htmlContent, err := s.fetchHTML(url)
+	if err != nil {
+		log.Printf("Failed to fetch island %d: %v", islandID, err)
+		s.job.IncrementFailed()
+		return models.CheckpointFailed
+	}
 
-		processedCount++
-		s.job.IncrementProcessed()
-		s.db.Save(s.job)
+	// Check if island is uncolonized
+	if strings.Contains(htmlContent, "Shiver me timbers: The island is uncolonized.") {
+		// Skip uncolonized islands
+		return models.CheckpointSkipped
 	}
 
-	log.Printf("Successfully processed %d islands for ocean %s", processedCount, s.ocean)
-	return nil
+	// Parse island info
+	islandData, err := ParseIslandInfo(htmlContent, islandID, s.ocean)
+	if err != nil {
+		log.Printf("Failed to parse island %d: %v", islandID, err)
+		s.job.IncrementFailed()
+		return models.CheckpointFailed
+	}
+
+	// Skip empty islands (no name or zero population)
+	if islandData.Name == "" {
+		log.Printf("Skipping island %d: empty name", islandID)
+		return models.CheckpointSkipped
+	}
+	if islandData.Population <= 0 {
+		log.Printf("Skipping island %d (%s): no population", islandID, islandData.Name)
+		return models.CheckpointSkipped
+	}
+
+	// Process and save island
+	if err := s.processIsland(*islandData, scrapedAt); err != nil {
+		log.Printf("Error processing island %d: %v", islandID, err)
+		s.job.IncrementFailed()
+		return models.CheckpointFailed
+	}
+
+	s.job.IncrementProcessed()
+	s.db.Save(s.job)
+	return models.CheckpointProcessed
 }
 
 // processIsland processes a single island and saves all related data
@@ -225,83 +327,24 @@ func (s *Scraper) processIsland(data IslandData, scrapedAt time.Time) error {
 			return fmt.Errorf("failed to get/create island: %w", err)
 		}
 
-		// Update island fields
-		island.Name = data.Name
-		island.Size = data.Size
-		island.IsColonized = data.IsColonized
-		if archipelago.ID > 0 {
-			island.ArchipelagoID = &archipelago.ID
-		}
-		island.LastSeenAt = scrapedAt
-
-		// Handle governor flag
-		if data.GovernorFlag != "" {
-			var flagID uint64
-			if _, err := fmt.Sscanf(data.GovernorFlag, "%d", &flagID); err == nil {
-				var flag models.Flag
-				if err := tx.Where("game_flag_id = ? AND ocean = ?", flagID, s.ocean).First(&flag).Error; err == nil {
-					island.GovernorFlagID = &flag.ID
-				}
-			}
-		}
-		if data.GovernorName != "" {
-			island.GovernorName = data.GovernorName
-		}
-
-		if err := tx.Save(&island).Error; err != nil {
-			return fmt.Errorf("failed to save island: %w", err)
-		}
-
 		// Create population record
 		if data.Population > 0 {
 			pop := models.IslandPopulation{
-				IslandID:  island.ID,
-				ScrapedAt: scrapedAt,
+				IslandID:   island.ID,
+				ScrapedAt:  scrapedAt,
 				Population: data.Population,
 			}
-			// Use FirstOrCreate to avoid duplicates
-			if err := tx.Where("island_id = ? AND scraped_at = ?", island.ID, scrapedAt).
-				FirstOrCreate(&pop).Error; err != nil {
+			if err := upsertRecord(tx, &pop, []string{"island_id", "scraped_at"},
+				[]string{"population"}); err != nil {
 				return fmt.Errorf("failed to create population record: %w", err)
 			}
 		}
 
-		// Update governance history if governor changed
-		var lastGov models.IslandGovernanceHistory
-		err = tx.Where("island_id = ? AND ended_at IS NULL", island.ID).
-			Order("started_at DESC").First(&lastGov).Error
-
-		governorChanged := false
-		if err == gorm.ErrRecordNotFound {
-			governorChanged = true
-		} else if err == nil {
-			currentFlagID := island.GovernorFlagID
-			if (lastGov.FlagID == nil && currentFlagID != nil) ||
-				(lastGov.FlagID != nil && currentFlagID == nil) ||
-				(lastGov.FlagID != nil && currentFlagID != nil && *lastGov.FlagID != *currentFlagID) ||
-				lastGov.GovernorName != island.GovernorName {
-				governorChanged = true
-			}
-		}
-
-		if governorChanged {
-			// End previous governance
-			if err == nil {
-				now := time.Now()
-				lastGov.EndedAt = &now
-				tx.Save(&lastGov)
-			}
-
-			// Create new governance record
-			gov := models.IslandGovernanceHistory{
-				IslandID:     island.ID,
-				FlagID:        island.GovernorFlagID,
-				GovernorName:  island.GovernorName,
-				StartedAt:     scrapedAt,
-				ChangeType:    "scrape",
-			}
-			if err := tx.Create(&gov).Error; err != nil {
-				return fmt.Errorf("failed to create governance history: %w", err)
+		// Reprocessing an old job only rewrites the island's records; its current state and
+		// governance stay as the latest scrape left them
+		if !s.reprocessing() {
+			if err := s.updateIsland(tx, &island, data, archipelago, scrapedAt); err != nil {
+				return err
 			}
 		}
 
@@ -314,7 +357,7 @@ func (s *Scraper) processIsland(data IslandData, scrapedAt time.Time) error {
 					commodity = models.Commodity{
 						Name:        commName,
 						DisplayName: commName,
-						Category:    types.CommodityCategoryBasic, // Default category
+						Category:    types.ClassifyCommodity(commName),
 					}
 					if err := tx.Create(&commodity).Error; err != nil {
 						return fmt.Errorf("failed to create commodity: %w", err)
@@ -339,8 +382,80 @@ func (s *Scraper) processIsland(data IslandData, scrapedAt time.Time) error {
 	})
 }
 
+// updateIsland updates the island's current state from a scrape and records a governance change
+func (s *Scraper) updateIsland(tx *gorm.DB, island *models.Island, data IslandData, archipelago models.Archipelago, scrapedAt time.Time) error {
+	// Update island fields
+	island.Name = data.Name
+	island.Size = data.Size
+	island.IsColonized = data.IsColonized
+	if archipelago.ID > 0 {
+		island.ArchipelagoID = &archipelago.ID
+	}
+	island.LastSeenAt = scrapedAt
+
+	// Handle governor flag
+	if data.GovernorFlag != "" {
+		var flagID uint64
+		if _, err := fmt.Sscanf(data.GovernorFlag, "%d", &flagID); err == nil {
+			var flag models.Flag
+			if err := tx.Where("game_flag_id = ? AND ocean = ?", flagID, s.ocean).First(&flag).Error; err == nil {
+				island.GovernorFlagID = &flag.ID
+			}
+		}
+	}
+	if data.GovernorName != "" {
+		island.GovernorName = data.GovernorName
+	}
+
+	if err := tx.Save(island).Error; err != nil {
+		return fmt.Errorf("failed to save island: %w", err)
+	}
+
+	// Update governance history if governor changed
+	var lastGov models.IslandGovernanceHistory
+	err := tx.Where("island_id = ? AND ended_at IS NULL", island.ID).
+		Order("started_at DESC").First(&lastGov).Error
+
+	governorChanged := false
+	if err == gorm.ErrRecordNotFound {
+		governorChanged = true
+	} else if err == nil {
+		currentFlagID := island.GovernorFlagID
+		if (lastGov.FlagID == nil && currentFlagID != nil) ||
+			(lastGov.FlagID != nil && currentFlagID == nil) ||
+			(lastGov.FlagID != nil && currentFlagID != nil && *lastGov.FlagID != *currentFlagID) ||
+			lastGov.GovernorName != island.GovernorName {
+			governorChanged = true
+		}
+	}
+
+	if governorChanged {
+		// End previous governance
+		if err == nil {
+			lastGov.EndedAt = &scrapedAt
+			tx.Save(&lastGov)
+		}
+
+		// Create new governance record
+		gov := models.IslandGovernanceHistory{
+			IslandID:     island.ID,
+			FlagID:        island.GovernorFlagID,
+			GovernorName:  island.GovernorName,
+			StartedAt:     scrapedAt,
+			ChangeType:    "scrape",
+		}
+		if err := tx.Create(&gov).Error; err != nil {
+			return fmt.Errorf("failed to create governance history: %w", err)
+		}
+	}
+
+	return nil
+}
+
 // ScrapeTaxRates scrapes tax rates for all commodities
-func (s *Scraper) ScrapeTaxRates() error {
+func (s *Scraper) ScrapeTaxRates(ctx context.Context) error {
+	// Tax rates come from a single page, so a resumed job scrapes them again rather than per rate
+	s.enterStage(stageTaxRates)
 	url := GetTaxRatesURL(s.ocean)
 	htmlContent, err := s.fetchHTML(url)
 	if err != nil {
@@ -360,9 +475,12 @@ func (s *Scraper) ScrapeTaxRates() error {
 
 	log.Printf("Successfully parsed %d tax rates for ocean %s", len(rates), s.ocean)
 
-	scrapedAt := time.Now()
+	scrapedAt := s.stageScrapeTime()
 
 	for _, rateData := range rates {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if err := s.processTaxRate(rateData, scrapedAt); err != nil {
 			log.Printf("Error processing tax rate for %s: %v", rateData.CommodityName, err)
 			s.job.IncrementFailed()
@@ -385,7 +503,7 @@ func (s *Scraper) processTaxRate(data TaxRateData, scrapedAt time.Time) error {
 			commodity = models.Commodity{
 				Name:        data.CommodityName,
 				DisplayName: data.CommodityName,
-				Category:    types.CommodityCategoryBasic, // Default category
+				Category:    types.ClassifyCommodity(data.CommodityName),
 			}
 			if err := s.db.Create(&commodity).Error; err != nil {
 				return fmt.Errorf("failed to create commodity: %w", err)
@@ -403,9 +521,8 @@ func (s *Scraper) processTaxRate(data TaxRateData, scrapedAt time.Time) error {
 		TaxValue:    data.TaxValue,
 	}
 
-	// Use FirstOrCreate to avoid duplicates
-	if err := s.db.Where("commodity_id = ? AND ocean = ? AND scraped_at = ?",
-		commodity.ID, s.ocean, scrapedAt).FirstOrCreate(&taxRate).Error; err != nil {
+	if err := upsertRecord(s.db, &taxRate, []string{"commodity_id", "ocean", "scraped_at"},
+		[]string{"tax_value"}); err != nil {
 		return fmt.Errorf("failed to create tax rate: %w", err)
 	}
 
@@ -413,7 +530,9 @@ func (s *Scraper) processTaxRate(data TaxRateData, scrapedAt time.Time) error {
 }
 
 // ScrapeCrews scrapes all crew data including fame and battle info
-func (s *Scraper) ScrapeCrews() error {
+func (s *Scraper) ScrapeCrews(ctx context.Context) error {
+	s.enterStage(stageCrews)
+
 	// First, get crew list from fame list
 	url := GetCrewFameListURL(s.ocean)
 	htmlContent, err := s.fetchHTML(url)
@@ -434,16 +553,53 @@ func (s *Scraper) ScrapeCrews() error {
 
 	log.Printf("Successfully parsed %d crews for ocean %s", len(crews), s.ocean)
 
-	scrapedAt := time.Now()
+	scrapedAt := s.stageScrapeTime()
 
+	// Track which crews were found and which failed to load, so the crews missing from this
+	// scrape can be told apart from those that merely errored
+	seen := make(map[uint64]bool, len(crews))
+	skipped := make(map[uint64]bool)
 	for _, crewData := range crews {
+		// A cancelled scrape hasn't seen every crew, so it must not reconcile them
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// The crews a resumed job already finished with still count when reconciling
+		if outcome, ok := s.finished(stageCrews, crewData.CrewID); ok {
+			switch outcome {
+			case models.CheckpointProcessed:
+				seen[crewData.CrewID] = true
+			case models.CheckpointFailed:
+				skipped[crewData.CrewID] = true
+			}
+			continue
+		}
 		if err := s.processCrew(crewData, scrapedAt); err != nil {
+			if errors.Is(err, ErrCrewNotFound) {
+				log.Printf("Crew %d no longer exists", crewData.CrewID)
+				s.job.IncrementProcessed()
+				s.db.Save(s.job)
+				s.checkpoint(stageCrews, crewData.CrewID, models.CheckpointMissing)
+				continue
+			}
 			log.Printf("Error processing crew %d: %v", crewData.CrewID, err)
 			s.job.IncrementFailed()
+			skipped[crewData.CrewID] = true
+			s.checkpoint(stageCrews, crewData.CrewID, models.CheckpointFailed)
 			continue
 		}
+		seen[crewData.CrewID] = true
 		s.job.IncrementProcessed()
 		s.db.Save(s.job)
+		s.checkpoint(stageCrews, crewData.CrewID, models.CheckpointProcessed)
+	}
+
+	// The lifecycle follows the latest scrape, which an old job being reprocessed isn't
+	if s.reprocessing() {
+		return nil
+	}
+	if err := s.reconcileCrews(seen, skipped, scrapedAt); err != nil {
+		return fmt.Errorf("failed to reconcile crews: %w", err)
 	}
 
 	return nil
@@ -464,92 +620,21 @@ func (s *Scraper) processCrew(fameData CrewFameData, scrapedAt time.Time) error
 	}
 
 	return s.db.Transaction(func(tx *gorm.DB) error {
-		// Check if crew exists first (to get oldFlagID for history tracking)
-		var existingCrew models.Crew
-		crewExists := tx.Where("game_crew_id = ? AND ocean = ?", fameData.CrewID, s.ocean).
-			First(&existingCrew).Error == nil
-
-		// Upsert crew using ON CONFLICT to handle race conditions
-		crew := models.Crew{
-			GameCrewID: fameData.CrewID,
-			Ocean:      s.ocean,
-			Name:       crewData.Name,
-			LastSeenAt: scrapedAt,
-		}
-		if crewExists {
-			crew.FlagID = existingCrew.FlagID
-		}
-		err := tx.Clauses(clause.OnConflict{
-			Columns:   []clause.Column{{Name: "game_crew_id"}, {Name: "ocean"}},
-			DoUpdates: clause.AssignmentColumns([]string{"name", "last_seen_at", "updated_at"}),
-		}).Create(&crew).Error
-		if err != nil {
-			return fmt.Errorf("failed to upsert crew: %w", err)
-		}
-
-		// Fetch the crew to get its ID and current state
-		if crew.ID == 0 || crewExists {
+		var crew models.Crew
+		if s.reprocessing() {
+			// Reprocessing an old job only rewrites the crew's records; its current state and
+			// history stay as the latest scrape left them
 			if err := tx.Where("game_crew_id = ? AND ocean = ?", fameData.CrewID, s.ocean).
 				First(&crew).Error; err != nil {
-				return fmt.Errorf("failed to fetch crew after upsert: %w", err)
-			}
-		}
-
-		// Track oldFlagID for history
-		var oldFlagID *uint
-		if crewExists {
-			oldFlagID = existingCrew.FlagID
-		}
-
-		// Handle flag
-		if crewData.FlagID != nil {
-			var flag models.Flag
-			if err := tx.Where("game_flag_id = ? AND ocean = ?", *crewData.FlagID, s.ocean).
-				First(&flag).Error; err == nil {
-				crew.FlagID = &flag.ID
-
-				// Update flag history if flag changed
-				if oldFlagID == nil || *oldFlagID != flag.ID {
-					// End previous flag history
-					var lastFlagHist models.CrewFlagHistory
-					if err := tx.Where("crew_id = ? AND left_at IS NULL", crew.ID).
-						First(&lastFlagHist).Error; err == nil {
-						now := time.Now()
-						lastFlagHist.LeftAt = &now
-						tx.Save(&lastFlagHist)
-					}
-
-					// Create new flag history
-					flagHist := models.CrewFlagHistory{
-						CrewID:   crew.ID,
-						FlagID:   &flag.ID,
-						JoinedAt: scrapedAt,
-					}
-					if err := tx.Create(&flagHist).Error; err != nil {
-						return fmt.Errorf("failed to create flag history: %w", err)
-					}
-				}
+				return fmt.Errorf("failed to find crew: %w", err)
 			}
 		} else {
-			// Crew is independent
-			crew.FlagID = nil
-
-			if oldFlagID != nil {
-				// End previous flag history
-				var lastFlagHist models.CrewFlagHistory
-				if err := tx.Where("crew_id = ? AND left_at IS NULL", crew.ID).
-					First(&lastFlagHist).Error; err == nil {
-					now := time.Now()
-					lastFlagHist.LeftAt = &now
-					tx.Save(&lastFlagHist)
-				}
+			var err error
+			if crew, err = s.updateCrew(tx, fameData.CrewID, crewData, scrapedAt); err != nil {
+				return err
 			}
 		}
 
-		if err := tx.Save(&crew).Error; err != nil {
-			return fmt.Errorf("failed to save crew: %w", err)
-		}
-
 		// Create fame record
 		fameRecord := models.CrewFameRecord{
 			CrewID:    crew.ID,
@@ -557,8 +642,8 @@ func (s *Scraper) processCrew(fameData CrewFameData, scrapedAt time.Time) error
 			FameLevel: fameData.FameLevel,
 			FameRank:  fameData.Rank,
 		}
-		if err := tx.Where("crew_id = ? AND scraped_at = ?", crew.ID, scrapedAt).
-			FirstOrCreate(&fameRecord).Error; err != nil {
+		if err := upsertRecord(tx, &fameRecord, []string{"crew_id", "scraped_at"},
+			[]string{"fame_level", "fame_rank"}); err != nil {
 			return fmt.Errorf("failed to create fame record: %w", err)
 		}
 
@@ -568,11 +653,6 @@ func (s *Scraper) processCrew(fameData CrewFameData, scrapedAt time.Time) error
 		if err == nil {
 			battleData, err := ParseCrewBattleInfo(battleHTML, fameData.CrewID)
 			if err == nil {
-				// Get previous battle record for delta calculation
-				var prevRecord models.CrewBattleRecord
-				err := tx.Where("crew_id = ?", crew.ID).
-					Order("scraped_at DESC").First(&prevRecord).Error
-
 				battleRecord := models.CrewBattleRecord{
 					CrewID:         crew.ID,
 					ScrapedAt:      scrapedAt,
@@ -580,15 +660,7 @@ func (s *Scraper) processCrew(fameData CrewFameData, scrapedAt time.Time) error
 					TotalPVPWins:   battleData.TotalPVPWins,
 					TotalPVPLosses: battleData.TotalPVPLosses,
 				}
-
-				if err == nil {
-					battleRecord.CalculateDeltas(&prevRecord)
-				} else {
-					battleRecord.CalculateDeltas(nil)
-				}
-
-				if err := tx.Where("crew_id = ? AND scraped_at = ?", crew.ID, scrapedAt).
-					FirstOrCreate(&battleRecord).Error; err != nil {
+				if err := s.saveBattleRecord(tx, &battleRecord); err != nil {
 					return fmt.Errorf("failed to create battle record: %w", err)
 				}
 			}
@@ -598,81 +670,280 @@ func (s *Scraper) processCrew(fameData CrewFameData, scrapedAt time.Time) error
 	})
 }
 
+// updateCrew creates or updates the crew from a scrape of its info page, tracking its flag and
+// name history, and returns it
+func (s *Scraper) updateCrew(tx *gorm.DB, gameCrewID uint64, crewData *CrewData, scrapedAt time.Time) (models.Crew, error) {
+	// Check if crew exists first (to get oldFlagID for history tracking)
+	var existingCrew models.Crew
+	crewExists := tx.Where("game_crew_id = ? AND ocean = ?", gameCrewID, s.ocean).
+		First(&existingCrew).Error == nil
+
+	// Upsert crew using ON CONFLICT to handle race conditions
+	crew := models.Crew{
+		GameCrewID: gameCrewID,
+		Ocean:      s.ocean,
+		Name:       crewData.Name,
+		LastSeenAt: scrapedAt,
+	}
+	if crewExists {
+		crew.FlagID = existingCrew.FlagID
+	}
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "game_crew_id"}, {Name: "ocean"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "last_seen_at", "updated_at"}),
+	}).Create(&crew).Error
+	if err != nil {
+		return models.Crew{}, fmt.Errorf("failed to upsert crew: %w", err)
+	}
+
+	// Fetch the crew to get its ID and current state
+	if crew.ID == 0 || crewExists {
+		if err := tx.Where("game_crew_id = ? AND ocean = ?", gameCrewID, s.ocean).
+			First(&crew).Error; err != nil {
+			return models.Crew{}, fmt.Errorf("failed to fetch crew after upsert: %w", err)
+		}
+	}
+
+	// Track oldFlagID for history
+	var oldFlagID *uint
+	if crewExists {
+		oldFlagID = existingCrew.FlagID
+	}
+
+	// Handle flag
+	if crewData.FlagID != nil {
+		var flag models.Flag
+		if err := tx.Where("game_flag_id = ? AND ocean = ?", *crewData.FlagID, s.ocean).
+			First(&flag).Error; err == nil {
+			crew.FlagID = &flag.ID
+
+			// Update flag history if flag changed
+			if oldFlagID == nil || *oldFlagID != flag.ID {
+				// End previous flag history
+				var lastFlagHist models.CrewFlagHistory
+				if err := tx.Where("crew_id = ? AND left_at IS NULL", crew.ID).
+					First(&lastFlagHist).Error; err == nil {
+					lastFlagHist.LeftAt = &scrapedAt
+					tx.Save(&lastFlagHist)
+				}
+
+				// Create new flag history
+				flagHist := models.CrewFlagHistory{
+					CrewID:   crew.ID,
+					FlagID:   &flag.ID,
+					JoinedAt: scrapedAt,
+				}
+				if err := tx.Create(&flagHist).Error; err != nil {
+					return models.Crew{}, fmt.Errorf("failed to create flag history: %w", err)
+				}
+			}
+		}
+	} else {
+		// Crew is independent
+		crew.FlagID = nil
+
+		if oldFlagID != nil {
+			// End previous flag history
+			var lastFlagHist models.CrewFlagHistory
+			if err := tx.Where("crew_id = ? AND left_at IS NULL", crew.ID).
+				First(&lastFlagHist).Error; err == nil {
+				lastFlagHist.LeftAt = &scrapedAt
+				tx.Save(&lastFlagHist)
+			}
+		}
+	}
+
+	if err := tx.Save(&crew).Error; err != nil {
+		return models.Crew{}, fmt.Errorf("failed to save crew: %w", err)
+	}
+
+	var previous *models.Crew
+	if crewExists {
+		previous = &existingCrew
+	}
+	if err := trackCrewName(tx, crew.ID, crew.Name, previous, scrapedAt); err != nil {
+		return models.Crew{}, fmt.Errorf("failed to track crew name: %w", err)
+	}
+
+	if !crewExists {
+		event := models.CrewLifecycleEvent{
+			CrewID:      crew.ID,
+			EventType:   models.LifecycleEventFirstSeen,
+			OccurredAt:  scrapedAt,
+			ScrapeJobID: &s.job.ID,
+		}
+		if err := tx.Create(&event).Error; err != nil {
+			return models.Crew{}, fmt.Errorf("failed to create lifecycle event: %w", err)
+		}
+	}
+
+	return crew, nil
+}
+
+// trackCrewName tracks the crew's name in crew_name_history, as trackName does. previous is the
+// crew as stored before this scrape, nil for new crews.
+func trackCrewName(tx *gorm.DB, crewID uint, name string, previous *models.Crew, scrapedAt time.Time) error {
+	var former *formerName
+	if previous != nil {
+		former = &formerName{name: previous.Name, firstSeenAt: previous.FirstSeenAt}
+	}
+	return trackName(tx, "crew_id", crewID, name, former, scrapedAt,
+		func(name string, startedAt time.Time, endedAt *time.Time) *models.CrewNameHistory {
+			return &models.CrewNameHistory{CrewID: crewID, Name: name, StartedAt: startedAt, EndedAt: endedAt}
+		})
+}
+
+// formerName is the name an entity was stored with before a scrape, and when it was first seen
+type formerName struct {
+	name        string
+	firstSeenAt time.Time
+}
+
+// trackName ends an entity's current name record in the name history model T when it was
+// renamed and starts one for the new name. idColumn references the entity and newRecord builds
+// one of its records. former is nil for new entities; entities stored before names were tracked
+// get their first record backdated to when they were first seen.
+func trackName[T any](tx *gorm.DB, idColumn string, entityID uint, name string, former *formerName,
+	scrapedAt time.Time, newRecord func(name string, startedAt time.Time, endedAt *time.Time) *T) error {
+	var current struct {
+		ID   uint
+		Name string
+	}
+	result := tx.Model(new(T)).Select("id", "name").
+		Where(idColumn+" = ? AND ended_at IS NULL", entityID).Limit(1).Scan(&current)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	startedAt := scrapedAt
+	if result.RowsAffected > 0 {
+		if current.Name == name {
+			return nil
+		}
+		if err := tx.Model(new(T)).Where("id = ?", current.ID).Update("ended_at", scrapedAt).Error; err != nil {
+			return err
+		}
+	} else if former != nil {
+		if former.name == name {
+			startedAt = former.firstSeenAt
+		} else if err := tx.Create(newRecord(former.name, former.firstSeenAt, &scrapedAt)).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Create(newRecord(name, startedAt, nil)).Error
+}
+
 // ScrapeCrewFame scrapes only crew fame data
-func (s *Scraper) ScrapeCrewFame() error {
-	return s.ScrapeCrews() // Reuse ScrapeCrews which includes fame
+func (s *Scraper) ScrapeCrewFame(ctx context.Context) error {
+	return s.ScrapeCrews(ctx) // Reuse ScrapeCrews which includes fame
 }
 
 // ScrapeBattleInfo scrapes only battle info for existing crews
-func (s *Scraper) ScrapeBattleInfo() error {
+func (s *Scraper) ScrapeBattleInfo(ctx context.Context) error {
+	s.enterStage(stageBattleInfo)
+
+	// Crews that were active when the reprocessed job ran may have been disbanded since
+	query := s.db.Where("ocean = ?", s.ocean)
+	if !s.reprocessing() {
+		query = query.Where("is_active = ?", true)
+	}
+
 	var crews []models.Crew
-	if err := s.db.Where("ocean = ? AND is_active = ?", s.ocean, true).Find(&crews).Error; err != nil {
+	if err := query.Find(&crews).Error; err != nil {
 		return fmt.Errorf("failed to fetch crews: %w", err)
 	}
 
-	scrapedAt := time.Now()
+	scrapedAt := s.stageScrapeTime()
 
 	for _, crew := range crews {
-		// Fetch crew info page to get CrewRank (it's on crew info, not battle info page)
-		crewInfoURL := GetCrewInfoURL(s.ocean, crew.GameCrewID)
-		crewInfoHTML, err := s.fetchHTML(crewInfoURL)
-		var crewRank types.CrewRank
-		if err == nil {
-			if crewData, err := ParseCrewInfo(crewInfoHTML, crew.GameCrewID, s.ocean); err == nil {
-				crewRank = crewData.CrewRank
-			}
+		if err := ctx.Err(); err != nil {
+			return err
 		}
-
-		battleURL := GetCrewBattleInfoURL(s.ocean, crew.GameCrewID)
-		battleHTML, err := s.fetchHTML(battleURL)
-		if err != nil {
-			log.Printf("Failed to fetch battle info for crew %d: %v", crew.GameCrewID, err)
-			s.job.IncrementFailed()
+		if _, ok := s.finished(stageBattleInfo, crew.GameCrewID); ok {
 			continue
 		}
 
-		battleData, err := ParseCrewBattleInfo(battleHTML, crew.GameCrewID)
-		if err != nil {
-			log.Printf("Failed to parse battle info for crew %d: %v", crew.GameCrewID, err)
-			s.job.IncrementFailed()
-			continue
-		}
+		outcome := s.scrapeCrewBattleInfo(&crew, scrapedAt)
+		s.checkpoint(stageBattleInfo, crew.GameCrewID, outcome)
+	}
 
-		// Get previous battle record for delta calculation
-		var prevRecord models.CrewBattleRecord
-		err = s.db.Where("crew_id = ?", crew.ID).
-			Order("scraped_at DESC").First(&prevRecord).Error
+	return nil
+}
 
-		battleRecord := models.CrewBattleRecord{
-			CrewID:         crew.ID,
-			ScrapedAt:      scrapedAt,
-			CrewRank:       crewRank,
-			TotalPVPWins:   battleData.TotalPVPWins,
-			TotalPVPLosses: battleData.TotalPVPLosses,
+// scrapeCrewBattleInfo fetches a crew's rank and battle totals and records them
+func (s *Scraper) scrapeCrewBattleInfo(crew *models.Crew, scrapedAt time.Time) models.CheckpointOutcome {
+	// Fetch crew info page to get CrewRank (it's on crew info, not battle info page)
+	crewInfoURL := GetCrewInfoURL(s.ocean, crew.GameCrewID)
+	crewInfoHTML, err := s.fetchHTML(crewInfoURL)
+	var crewRank types.CrewRank
+	if err == nil {
+		crewData, err := ParseCrewInfo(crewInfoHTML, crew.GameCrewID, s.ocean)
+		if errors.Is(err, ErrCrewNotFound) {
+			// Count the miss so crews that are gone stop being scraped. Sightings are left to
+			// ScrapeCrews, since a crew can keep its info page after dropping off the fame list.
+			log.Printf("Crew %d no longer exists", crew.GameCrewID)
+			if s.reprocessing() {
+				return models.CheckpointSkipped
+			}
+			if err := s.recordCrewMiss(crew, scrapedAt); err != nil {
+				log.Printf("Failed to record miss for crew %d: %v", crew.GameCrewID, err)
+				s.job.IncrementFailed()
+				return models.CheckpointFailed
+			}
+			s.job.IncrementProcessed()
+			s.db.Save(s.job)
+			return models.CheckpointMissing
 		}
-
 		if err == nil {
-			battleRecord.CalculateDeltas(&prevRecord)
-		} else {
-			battleRecord.CalculateDeltas(nil)
+			crewRank = crewData.CrewRank
 		}
+	}
 
-		if err := s.db.Where("crew_id = ? AND scraped_at = ?", crew.ID, scrapedAt).
-			FirstOrCreate(&battleRecord).Error; err != nil {
-			log.Printf("Failed to create battle record for crew %d: %v", crew.GameCrewID, err)
-			s.job.IncrementFailed()
-			continue
-		}
+	battleURL := GetCrewBattleInfoURL(s.ocean, crew.GameCrewID)
+	battleHTML, err := s.fetchHTML(battleURL)
+	if errors.Is(err, archive.ErrNotArchived) {
+		// The reprocessed job didn't scrape this crew
+		return models.CheckpointSkipped
+	}
+	if err != nil {
+		log.Printf("Failed to fetch battle info for crew %d: %v", crew.GameCrewID, err)
+		s.job.IncrementFailed()
+		return models.CheckpointFailed
+	}
 
-		s.job.IncrementProcessed()
-		s.db.Save(s.job)
+	battleData, err := ParseCrewBattleInfo(battleHTML, crew.GameCrewID)
+	if err != nil {
+		log.Printf("Failed to parse battle info for crew %d: %v", crew.GameCrewID, err)
+		s.job.IncrementFailed()
+		return models.CheckpointFailed
 	}
 
-	return nil
+	battleRecord := models.CrewBattleRecord{
+		CrewID:         crew.ID,
+		ScrapedAt:      scrapedAt,
+		CrewRank:       crewRank,
+		TotalPVPWins:   battleData.TotalPVPWins,
+		TotalPVPLosses: battleData.TotalPVPLosses,
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		return s.saveBattleRecord(tx, &battleRecord)
+	})
+	if err != nil {
+		log.Printf("Failed to create battle record for crew %d: %v", crew.GameCrewID, err)
+		s.job.IncrementFailed()
+		return models.CheckpointFailed
+	}
+
+	s.job.IncrementProcessed()
+	s.db.Save(s.job)
+	return models.CheckpointProcessed
 }
 
 // ScrapeFlags scrapes all flag data
-func (s *Scraper) ScrapeFlags() error {
+func (s *Scraper) ScrapeFlags(ctx context.Context) error {
+	s.enterStage(stageFlags)
+
 	// First, get flag list from fame list
 	url := GetFlagFameListURL(s.ocean)
 	htmlContent, err := s.fetchHTML(url)
@@ -693,16 +964,53 @@ func (s *Scraper) ScrapeFlags() error {
 
 	log.Printf("Successfully parsed %d flags for ocean %s", len(flags), s.ocean)
 
-	scrapedAt := time.Now()
+	scrapedAt := s.stageScrapeTime()
 
+	// Track which flags were found and which failed to load, so the flags missing from this
+	// scrape can be told apart from those that merely errored
+	seen := make(map[uint64]bool, len(flags))
+	skipped := make(map[uint64]bool)
 	for _, flagData := range flags {
+		// A cancelled scrape hasn't seen every flag, so it must not reconcile them
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// The flags a resumed job already finished with still count when reconciling
+		if outcome, ok := s.finished(stageFlags, flagData.FlagID); ok {
+			switch outcome {
+			case models.CheckpointProcessed:
+				seen[flagData.FlagID] = true
+			case models.CheckpointFailed:
+				skipped[flagData.FlagID] = true
+			}
+			continue
+		}
 		if err := s.processFlag(flagData, scrapedAt); err != nil {
+			if errors.Is(err, ErrFlagNotFound) {
+				log.Printf("Flag %d no longer exists", flagData.FlagID)
+				s.job.IncrementProcessed()
+				s.db.Save(s.job)
+				s.checkpoint(stageFlags, flagData.FlagID, models.CheckpointMissing)
+				continue
+			}
 			log.Printf("Error processing flag %d: %v", flagData.FlagID, err)
 			s.job.IncrementFailed()
+			skipped[flagData.FlagID] = true
+			s.checkpoint(stageFlags, flagData.FlagID, models.CheckpointFailed)
 			continue
 		}
+		seen[flagData.FlagID] = true
 		s.job.IncrementProcessed()
 		s.db.Save(s.job)
+		s.checkpoint(stageFlags, flagData.FlagID, models.CheckpointProcessed)
+	}
+
+	// The lifecycle follows the latest scrape, which an old job being reprocessed isn't
+	if s.reprocessing() {
+		return nil
+	}
+	if err := s.reconcileFlags(seen, skipped, scrapedAt); err != nil {
+		return fmt.Errorf("failed to reconcile flags: %w", err)
 	}
 
 	return nil
@@ -723,26 +1031,18 @@ func (s *Scraper) processFlag(fameData FlagFameData, scrapedAt time.Time) error
 	}
 
 	return s.db.Transaction(func(tx *gorm.DB) error {
-		// Upsert flag using ON CONFLICT to handle race conditions
-		flag := models.Flag{
-			GameFlagID: fameData.FlagID,
-			Ocean:      s.ocean,
-			Name:       flagData.Name,
-			LastSeenAt: scrapedAt,
-		}
-		err := tx.Clauses(clause.OnConflict{
-			Columns:   []clause.Column{{Name: "game_flag_id"}, {Name: "ocean"}},
-			DoUpdates: clause.AssignmentColumns([]string{"name", "last_seen_at", "updated_at"}),
-		}).Create(&flag).Error
-		if err != nil {
-			return fmt.Errorf("failed to upsert flag: %w", err)
-		}
-
-		// Fetch the flag to get its ID (needed for fame record)
-		if flag.ID == 0 {
+		var flag models.Flag
+		if s.reprocessing() {
+			// Reprocessing an old job only rewrites the flag's records; its current state and
+			// history stay as the latest scrape left them
 			if err := tx.Where("game_flag_id = ? AND ocean = ?", fameData.FlagID, s.ocean).
 				First(&flag).Error; err != nil {
-				return fmt.Errorf("failed to fetch flag after upsert: %w", err)
+				return fmt.Errorf("failed to find flag: %w", err)
+			}
+		} else {
+			var err error
+			if flag, err = s.updateFlag(tx, fameData.FlagID, flagData, scrapedAt); err != nil {
+				return err
 			}
 		}
 
@@ -753,16 +1053,97 @@ func (s *Scraper) processFlag(fameData FlagFameData, scrapedAt time.Time) error
 			FameLevel: fameData.FameLevel,
 			FameRank:  fameData.Rank,
 		}
-		if err := tx.Where("flag_id = ? AND scraped_at = ?", flag.ID, scrapedAt).
-			FirstOrCreate(&fameRecord).Error; err != nil {
+		if err := upsertRecord(tx, &fameRecord, []string{"flag_id", "scraped_at"},
+			[]string{"fame_level", "fame_rank"}); err != nil {
 			return fmt.Errorf("failed to create fame record: %w", err)
 		}
 
+		// Create reputation records
+		for reputationType, level := range flagData.Reputations() {
+			reputationRecord := models.FlagReputationRecord{
+				FlagID:          flag.ID,
+				ScrapedAt:       scrapedAt,
+				ReputationType:  reputationType,
+				ReputationLevel: level,
+			}
+			if err := upsertRecord(tx, &reputationRecord, []string{"flag_id", "scraped_at", "reputation_type"},
+				[]string{"reputation_level"}); err != nil {
+				return fmt.Errorf("failed to create reputation record: %w", err)
+			}
+		}
+
 		return nil
 	})
 }
 
+// updateFlag creates or updates the flag from a scrape of its info page, tracking its name
+// history, and returns it
+func (s *Scraper) updateFlag(tx *gorm.DB, gameFlagID uint64, flagData *FlagData, scrapedAt time.Time) (models.Flag, error) {
+	// Check if flag exists first (to get its previous name for history tracking)
+	var existingFlag models.Flag
+	flagExists := tx.Where("game_flag_id = ? AND ocean = ?", gameFlagID, s.ocean).
+		First(&existingFlag).Error == nil
+
+	// Upsert flag using ON CONFLICT to handle race conditions
+	flag := models.Flag{
+		GameFlagID: gameFlagID,
+		Ocean:      s.ocean,
+		Name:       flagData.Name,
+		LastSeenAt: scrapedAt,
+	}
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "game_flag_id"}, {Name: "ocean"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "last_seen_at", "updated_at"}),
+	}).Create(&flag).Error
+	if err != nil {
+		return models.Flag{}, fmt.Errorf("failed to upsert flag: %w", err)
+	}
+
+	// Fetch the flag to get its ID (needed for fame record)
+	if flag.ID == 0 {
+		if err := tx.Where("game_flag_id = ? AND ocean = ?", gameFlagID, s.ocean).
+			First(&flag).Error; err != nil {
+			return models.Flag{}, fmt.Errorf("failed to fetch flag after upsert: %w", err)
+		}
+	}
+
+	var previous *models.Flag
+	if flagExists {
+		previous = &existingFlag
+	}
+	if err := trackFlagName(tx, flag.ID, flag.Name, previous, scrapedAt); err != nil {
+		return models.Flag{}, fmt.Errorf("failed to track flag name: %w", err)
+	}
+
+	if !flagExists {
+		event := models.FlagLifecycleEvent{
+			FlagID:      flag.ID,
+			EventType:   models.LifecycleEventFirstSeen,
+			OccurredAt:  scrapedAt,
+			ScrapeJobID: &s.job.ID,
+		}
+		if err := tx.Create(&event).Error; err != nil {
+			return models.Flag{}, fmt.Errorf("failed to create lifecycle event: %w", err)
+		}
+	}
+
+	return flag, nil
+}
+
+// trackFlagName tracks the flag's name in flag_name_history, as trackName does. previous is the
+// flag as stored before this scrape, nil for new flags.
+func trackFlagName(tx *gorm.DB, flagID uint, name string, previous *models.Flag, scrapedAt time.Time) error {
+	var former *formerName
+	if previous != nil {
+		former = &formerName{name: previous.Name, firstSeenAt: previous.FirstSeenAt}
+	}
+	return trackName(tx, "flag_id", flagID, name, former, scrapedAt,
+		func(name string, startedAt time.Time, endedAt *time.Time) *models.FlagNameHistory {
+			return &models.FlagNameHistory{FlagID: flagID, Name: name, StartedAt: startedAt, EndedAt: endedAt}
+		})
+}
+
 // ScrapeFlagFame scrapes only flag fame data
-func (s *Scraper) ScrapeFlagFame() error {
-	return s.ScrapeFlags() // Reuse ScrapeFlags which includes fame
+func (s *Scraper) ScrapeFlagFame(ctx context.Context) error {
+	return s.ScrapeFlags(ctx) // Reuse ScrapeFlags which includes fame
 }
@@ -0,0 +1,318 @@
+package scraper
+
+import (
+	"context"
+	"cutlass_analytics/internal/fetcher"
+	"cutlass_analytics/internal/models"
+	"cutlass_analytics/internal/testutil"
+	"cutlass_analytics/internal/types"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TestScraperRunReplay runs flag and crew scrapes against the pages recorded in testdata/pages:
+// one flag, a crew in it, a crew whose page says it no longer exists and a crew with no page
+func TestScraperRunReplay(t *testing.T) {
+	db := testutil.DB(t)
+	pages := fetcher.NewReplayFetcher("testdata/pages")
+
+	for _, jobType := range []models.ScrapeJobType{models.ScrapeJobTypeFlagFame, models.ScrapeJobTypeCrewInfo} {
+		s, err := NewScraper(db, pages, types.OceanEmerald, jobType)
+		if err != nil {
+			t.Fatalf("NewScraper(%s) error = %v", jobType, err)
+		}
+		if err := s.Run(context.Background()); err != nil {
+			t.Fatalf("Run(%s) error = %v", jobType, err)
+		}
+
+		var job models.ScrapeJob
+		if err := db.First(&job, s.job.ID).Error; err != nil {
+			t.Fatalf("failed to load %s job: %v", jobType, err)
+		}
+		if job.Status != models.ScrapeJobStatusCompleted {
+			t.Errorf("%s job status = %s, want %s", jobType, job.Status, models.ScrapeJobStatusCompleted)
+		}
+	}
+
+	var flag models.Flag
+	if err := db.Where("game_flag_id = ? AND ocean = ?", 11111, types.OceanEmerald).First(&flag).Error; err != nil {
+		t.Fatalf("flag 11111 was not stored: %v", err)
+	}
+	if flag.Name != "Jolly Roger Alliance" {
+		t.Errorf("flag name = %q, want Jolly Roger Alliance", flag.Name)
+	}
+	var reputations []models.FlagReputationRecord
+	db.Where("flag_id = ?", flag.ID).Find(&reputations)
+	if len(reputations) != 4 {
+		t.Errorf("stored %d flag reputation records, want 4", len(reputations))
+	}
+
+	var crew models.Crew
+	if err := db.Where("game_crew_id = ? AND ocean = ?", 12345, types.OceanEmerald).First(&crew).Error; err != nil {
+		t.Fatalf("crew 12345 was not stored: %v", err)
+	}
+	if crew.Name != "Pirates of the Caribbean" {
+		t.Errorf("crew name = %q, want Pirates of the Caribbean", crew.Name)
+	}
+	if crew.FlagID == nil || *crew.FlagID != flag.ID {
+		t.Errorf("crew flag = %v, want %d", crew.FlagID, flag.ID)
+	}
+
+	var battle models.CrewBattleRecord
+	if err := db.Where("crew_id = ?", crew.ID).First(&battle).Error; err != nil {
+		t.Fatalf("crew battle record was not stored: %v", err)
+	}
+	if battle.CrewRank != types.CrewRankSeaLords || battle.TotalPVPWins != 5 || battle.TotalPVPLosses != 3 {
+		t.Errorf("battle record = %s %d-%d, want Sea Lords 5-3", battle.CrewRank, battle.TotalPVPWins, battle.TotalPVPLosses)
+	}
+
+	var events []models.CrewLifecycleEvent
+	db.Where("crew_id = ?", crew.ID).Find(&events)
+	if len(events) != 1 || events[0].EventType != models.LifecycleEventFirstSeen {
+		t.Errorf("crew lifecycle events = %+v, want a single first_seen", events)
+	}
+
+	// Neither the crew that no longer exists nor the one without a page is stored
+	var others int64
+	db.Model(&models.Crew{}).Where("game_crew_id IN ?", []uint64{67890, 24680}).Count(&others)
+	if others != 0 {
+		t.Errorf("stored %d crews without info pages, want 0", others)
+	}
+}
+
+// TestScraperReprocess reprocesses a crew scrape from its archived pages and checks the records
+// it wrote are replaced rather than duplicated
+func TestScraperReprocess(t *testing.T) {
+	db := testutil.DB(t)
+	pages := fetcher.NewReplayFetcher("testdata/pages")
+
+	for _, jobType := range []models.ScrapeJobType{models.ScrapeJobTypeFlagFame, models.ScrapeJobTypeCrewInfo} {
+		s, err := NewScraper(db, pages, types.OceanEmerald, jobType)
+		if err != nil {
+			t.Fatalf("NewScraper(%s) error = %v", jobType, err)
+		}
+		if err := s.Run(context.Background()); err != nil {
+			t.Fatalf("Run(%s) error = %v", jobType, err)
+		}
+	}
+
+	var original models.ScrapeJob
+	db.Where("job_type = ?", models.ScrapeJobTypeCrewInfo).Order("id DESC").First(&original)
+
+	var crew models.Crew
+	if err := db.Where("game_crew_id = ? AND ocean = ?", 12345, types.OceanEmerald).First(&crew).Error; err != nil {
+		t.Fatalf("crew 12345 was not stored: %v", err)
+	}
+	var before models.CrewFameRecord
+	db.Where("crew_id = ?", crew.ID).First(&before)
+
+	// Simulate an older parser having stored a wrong battle record, which the deltas of the
+	// crew's next record were then computed from
+	var stored models.CrewBattleRecord
+	db.Where("crew_id = ?", crew.ID).First(&stored)
+	db.Model(&stored).Update("total_pvp_wins", 0)
+	db.Create(&models.CrewBattleRecord{
+		CrewID:         crew.ID,
+		ScrapedAt:      stored.ScrapedAt.Add(time.Hour),
+		CrewRank:       stored.CrewRank,
+		TotalPVPWins:   8,
+		TotalPVPLosses: stored.TotalPVPLosses,
+		DailyPVPWins:   8,
+	})
+
+	r, err := NewReprocessor(db, original.ID)
+	if err != nil {
+		t.Fatalf("NewReprocessor() error = %v", err)
+	}
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	job := r.Job()
+	if job.ReprocessedJobID == nil || *job.ReprocessedJobID != original.ID || job.JobType != original.JobType {
+		t.Errorf("reprocessing job = %+v, want a %s job reprocessing %d", job, original.JobType, original.ID)
+	}
+
+	var fame []models.CrewFameRecord
+	db.Where("crew_id = ?", crew.ID).Find(&fame)
+	if len(fame) != 1 || !fame[0].ScrapedAt.Equal(before.ScrapedAt) {
+		t.Errorf("crew fame records = %+v, want the one at %s", fame, before.ScrapedAt)
+	}
+
+	var battles []models.CrewBattleRecord
+	db.Where("crew_id = ?", crew.ID).Order("scraped_at ASC").Find(&battles)
+	if len(battles) != 2 || battles[0].TotalPVPWins != 5 {
+		t.Fatalf("crew battle records = %+v, want the reprocessed one with 5 wins and a later one", battles)
+	}
+	// The next record's deltas follow the corrected total
+	if battles[1].DailyPVPWins != 3 || battles[1].DailyPVPLosses != 0 {
+		t.Errorf("next battle record gained %d wins and %d losses, want 3 and 0",
+			battles[1].DailyPVPWins, battles[1].DailyPVPLosses)
+	}
+
+	var events int64
+	db.Model(&models.CrewLifecycleEvent{}).Where("crew_id = ?", crew.ID).Count(&events)
+	if events != 1 {
+		t.Errorf("stored %d crew lifecycle events, want 1", events)
+	}
+}
+
+func TestScraperResume(t *testing.T) {
+	db := testutil.DB(t)
+	pages := fetcher.NewReplayFetcher("testdata/pages")
+
+	s, err := NewScraper(db, pages, types.OceanEmerald, models.ScrapeJobTypeCrewInfo)
+	if err != nil {
+		t.Fatalf("NewScraper() error = %v", err)
+	}
+	if err := s.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	var crew models.Crew
+	if err := db.Where("game_crew_id = ? AND ocean = ?", 12345, types.OceanEmerald).First(&crew).Error; err != nil {
+		t.Fatalf("crew 12345 was not stored: %v", err)
+	}
+
+	// Simulate a job interrupted in the crews stage after it found crew 67890 gone
+	s, err = NewScraper(db, pages, types.OceanEmerald, models.ScrapeJobTypeCrewInfo)
+	if err != nil {
+		t.Fatalf("NewScraper() error = %v", err)
+	}
+	stageScrapedAt := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	interrupted := s.Job()
+	interrupted.Stage = stageCrews
+	interrupted.StageScrapedAt = &stageScrapedAt
+	interrupted.ItemsProcessed = 1
+	if err := interrupted.MarkInterrupted(db); err != nil {
+		t.Fatalf("MarkInterrupted() error = %v", err)
+	}
+	db.Create(&models.ScrapeJobCheckpoint{
+		ScrapeJobID: interrupted.ID,
+		Stage:       stageCrews,
+		ItemID:      67890,
+		Outcome:     models.CheckpointMissing,
+	})
+
+	r, err := ResumeScraper(db, pages, interrupted)
+	if err != nil {
+		t.Fatalf("ResumeScraper() error = %v", err)
+	}
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	job := r.Job()
+	if job.Status != models.ScrapeJobStatusCompleted || job.ResumeCount != 1 {
+		t.Errorf("resumed job = %+v, want it completed after 1 resume", job)
+	}
+	if job.ItemsProcessed != 2 {
+		t.Errorf("resumed job processed %d items, want 2 since crew 67890 is skipped", job.ItemsProcessed)
+	}
+
+	// The crews scraped after the resume belong to the same scrape as those before it
+	var fame []models.CrewFameRecord
+	db.Where("crew_id = ?", crew.ID).Order("scraped_at DESC").Find(&fame)
+	if len(fame) != 2 || !fame[0].ScrapedAt.Equal(stageScrapedAt) {
+		t.Errorf("crew fame records = %+v, want the resumed one at %s", fame, stageScrapedAt)
+	}
+	var battle models.CrewBattleRecord
+	db.Where("crew_id = ?", crew.ID).Order("scraped_at DESC").First(&battle)
+	if !battle.ScrapedAt.Equal(stageScrapedAt) {
+		t.Errorf("crew battle record scraped at %s, want %s", battle.ScrapedAt, stageScrapedAt)
+	}
+
+	db.First(&crew, crew.ID)
+	if !crew.IsActive || crew.MissedScrapes != 0 {
+		t.Errorf("crew = %+v, want it still active without misses", crew)
+	}
+
+	var checkpoints int64
+	db.Model(&models.ScrapeJobCheckpoint{}).Where("scrape_job_id = ?", job.ID).Count(&checkpoints)
+	if checkpoints != 0 {
+		t.Errorf("kept %d checkpoints, want them deleted once the job completed", checkpoints)
+	}
+}
+
+func TestTrackCrewName(t *testing.T) {
+	firstSeenAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	scrapedAt := firstSeenAt.Add(48 * time.Hour)
+
+	names := func(db *gorm.DB, crewID uint) []models.CrewNameHistory {
+		var history []models.CrewNameHistory
+		db.Where("crew_id = ?", crewID).Order("started_at ASC, id ASC").Find(&history)
+		return history
+	}
+	newCrew := func(t *testing.T, db *gorm.DB, name string) *models.Crew {
+		crew := &models.Crew{GameCrewID: 1, Ocean: types.OceanEmerald, Name: name, FirstSeenAt: firstSeenAt, LastSeenAt: firstSeenAt}
+		if err := db.Create(crew).Error; err != nil {
+			t.Fatalf("failed to create crew: %v", err)
+		}
+		return crew
+	}
+
+	t.Run("rename", func(t *testing.T) {
+		db := testutil.DB(t)
+		crew := newCrew(t, db, "Old Salts")
+		if err := trackCrewName(db, crew.ID, "Old Salts", nil, firstSeenAt); err != nil {
+			t.Fatalf("trackCrewName() error = %v", err)
+		}
+		if err := trackCrewName(db, crew.ID, "New Salts", crew, scrapedAt); err != nil {
+			t.Fatalf("trackCrewName() error = %v", err)
+		}
+
+		history := names(db, crew.ID)
+		if len(history) != 2 ||
+			history[0].Name != "Old Salts" || history[0].EndedAt == nil || !history[0].EndedAt.Equal(scrapedAt) ||
+			history[1].Name != "New Salts" || !history[1].StartedAt.Equal(scrapedAt) || history[1].EndedAt != nil {
+			t.Errorf("name history = %+v, want Old Salts ended and New Salts started at %s", history, scrapedAt)
+		}
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		db := testutil.DB(t)
+		crew := newCrew(t, db, "Old Salts")
+		if err := trackCrewName(db, crew.ID, "Old Salts", nil, firstSeenAt); err != nil {
+			t.Fatalf("trackCrewName() error = %v", err)
+		}
+		if err := trackCrewName(db, crew.ID, "Old Salts", crew, scrapedAt); err != nil {
+			t.Fatalf("trackCrewName() error = %v", err)
+		}
+
+		history := names(db, crew.ID)
+		if len(history) != 1 || !history[0].StartedAt.Equal(firstSeenAt) || history[0].EndedAt != nil {
+			t.Errorf("name history = %+v, want Old Salts alone since %s", history, firstSeenAt)
+		}
+	})
+
+	// Crews stored before names were tracked have no history yet
+	t.Run("untracked crew", func(t *testing.T) {
+		db := testutil.DB(t)
+		crew := newCrew(t, db, "Old Salts")
+		if err := trackCrewName(db, crew.ID, "Old Salts", crew, scrapedAt); err != nil {
+			t.Fatalf("trackCrewName() error = %v", err)
+		}
+
+		history := names(db, crew.ID)
+		if len(history) != 1 || !history[0].StartedAt.Equal(firstSeenAt) {
+			t.Errorf("name history = %+v, want Old Salts backdated to %s", history, firstSeenAt)
+		}
+	})
+
+	t.Run("untracked renamed crew", func(t *testing.T) {
+		db := testutil.DB(t)
+		crew := newCrew(t, db, "Old Salts")
+		if err := trackCrewName(db, crew.ID, "New Salts", crew, scrapedAt); err != nil {
+			t.Fatalf("trackCrewName() error = %v", err)
+		}
+
+		history := names(db, crew.ID)
+		if len(history) != 2 ||
+			history[0].Name != "Old Salts" || !history[0].StartedAt.Equal(firstSeenAt) || history[0].EndedAt == nil ||
+			history[1].Name != "New Salts" || !history[1].StartedAt.Equal(scrapedAt) {
+			t.Errorf("name history = %+v, want Old Salts from %s then New Salts from %s", history, firstSeenAt, scrapedAt)
+		}
+	})
+}
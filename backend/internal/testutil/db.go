@@ -0,0 +1,49 @@
+// Package testutil holds helpers for tests that run against a real database
+package testutil
+
+import (
+	"cutlass_analytics/internal/database"
+	"os"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// DSNEnv names the environment variable holding the DSN of a scratch Postgres database for
+// end-to-end tests. Tests that need a database are skipped when it is unset.
+const DSNEnv = "TEST_DATABASE_DSN"
+
+var (
+	connectOnce sync.Once
+	testDB      *gorm.DB
+	connectErr  error
+)
+
+// DB connects to and migrates the test database, returning a transaction that is rolled back
+// when the test ends so tests neither see nor leave behind each other's rows
+func DB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv(DSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", DSNEnv)
+	}
+
+	connectOnce.Do(func() {
+		testDB, connectErr = database.Connect(dsn)
+		if connectErr == nil {
+			connectErr = database.AutoMigrate(testDB)
+		}
+	})
+	if connectErr != nil {
+		t.Fatalf("failed to set up test database: %v", connectErr)
+	}
+
+	tx := testDB.Begin()
+	if tx.Error != nil {
+		t.Fatalf("failed to begin transaction: %v", tx.Error)
+	}
+	t.Cleanup(func() { tx.Rollback() })
+	return tx
+}
@@ -1,5 +1,7 @@
 package types
 
+import "strings"
+
 type CommodityCategory string
 
 const (
@@ -9,4 +11,96 @@ const (
 	CommodityCategoryForaged  CommodityCategory = "foraged"  // Forageable items
 	CommodityCategoryRefined  CommodityCategory = "refined"  // Processed goods (cloth, dye, etc.)
 	CommodityCategoryShipSupply CommodityCategory = "ship_supply" // Rum, Cannonballs
-)
\ No newline at end of file
+)
+
+func (c CommodityCategory) String() string {
+	return string(c)
+}
+
+// commodityCategories classifies the commodities traded in the game. Names are matched
+// case-insensitively; anything not listed here is treated as a basic commodity.
+var commodityCategories = map[string]CommodityCategory{
+	// Basic commodities
+	"wood":       CommodityCategoryBasic,
+	"iron":       CommodityCategoryBasic,
+	"stone":      CommodityCategoryBasic,
+	"hemp":       CommodityCategoryBasic,
+	"sugar cane": CommodityCategoryBasic,
+	"grapes":     CommodityCategoryBasic,
+
+	// Herbs
+	"cowslip":          CommodityCategoryHerb,
+	"elderberries":     CommodityCategoryHerb,
+	"indigo":           CommodityCategoryHerb,
+	"iris root":        CommodityCategoryHerb,
+	"madder":           CommodityCategoryHerb,
+	"nettle":           CommodityCategoryHerb,
+	"old man's beard":  CommodityCategoryHerb,
+	"pokeweed berries": CommodityCategoryHerb,
+	"sassafras":        CommodityCategoryHerb,
+	"weld":             CommodityCategoryHerb,
+	"yarrow":           CommodityCategoryHerb,
+	"lily":             CommodityCategoryHerb,
+	"lobelia":          CommodityCategoryHerb,
+	"broom flower":     CommodityCategoryHerb,
+	"butterfly weed":   CommodityCategoryHerb,
+	"bluebell":         CommodityCategoryHerb,
+
+	// Minerals
+	"bornite":      CommodityCategoryMineral,
+	"chalcocite":   CommodityCategoryMineral,
+	"cubanite":     CommodityCategoryMineral,
+	"gold nuggets": CommodityCategoryMineral,
+	"leushite":     CommodityCategoryMineral,
+	"loellingite":  CommodityCategoryMineral,
+	"lorandite":    CommodityCategoryMineral,
+	"papagoite":    CommodityCategoryMineral,
+	"serandite":    CommodityCategoryMineral,
+	"sincosite":    CommodityCategoryMineral,
+	"tellurium":    CommodityCategoryMineral,
+	"thorianite":   CommodityCategoryMineral,
+
+	// Foraged
+	"kraken's blood": CommodityCategoryForaged,
+	"kraken's ink":   CommodityCategoryForaged,
+	"bananas":        CommodityCategoryForaged,
+	"carambolas":     CommodityCategoryForaged,
+	"coconuts":       CommodityCategoryForaged,
+	"durians":        CommodityCategoryForaged,
+	"limes":          CommodityCategoryForaged,
+	"mangos":         CommodityCategoryForaged,
+	"passion fruit":  CommodityCategoryForaged,
+	"pineapples":     CommodityCategoryForaged,
+	"pomegranates":   CommodityCategoryForaged,
+	"rambutan":       CommodityCategoryForaged,
+
+	// Refined goods
+	"sugar":  CommodityCategoryRefined,
+	"lumber": CommodityCategoryRefined,
+
+	// Ship supplies
+	"rum":                 CommodityCategoryShipSupply,
+	"fine rum":            CommodityCategoryShipSupply,
+	"swill":               CommodityCategoryShipSupply,
+	"grog":                CommodityCategoryShipSupply,
+	"small cannon balls":  CommodityCategoryShipSupply,
+	"medium cannon balls": CommodityCategoryShipSupply,
+	"large cannon balls":  CommodityCategoryShipSupply,
+}
+
+// refinedSuffixes catches the many colour variants of processed goods
+var refinedSuffixes = []string{" cloth", " dye", " enamel", " paint"}
+
+// ClassifyCommodity returns the category of a commodity by name
+func ClassifyCommodity(name string) CommodityCategory {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if category, ok := commodityCategories[key]; ok {
+		return category
+	}
+	for _, suffix := range refinedSuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return CommodityCategoryRefined
+		}
+	}
+	return CommodityCategoryBasic
+}
@@ -0,0 +1,34 @@
+package types
+
+import "testing"
+
+func TestClassifyCommodity(t *testing.T) {
+	tests := []struct {
+		name      string
+		commodity string
+		want      CommodityCategory
+	}{
+		{name: "basic", commodity: "Wood", want: CommodityCategoryBasic},
+		{name: "herb", commodity: "Old man's beard", want: CommodityCategoryHerb},
+		{name: "mineral", commodity: "Gold nuggets", want: CommodityCategoryMineral},
+		{name: "foraged", commodity: "Kraken's ink", want: CommodityCategoryForaged},
+		{name: "refined", commodity: "Lumber", want: CommodityCategoryRefined},
+		{name: "ship supply", commodity: "Medium cannon balls", want: CommodityCategoryShipSupply},
+		{name: "case and surrounding spaces are ignored", commodity: "  FINE RUM ", want: CommodityCategoryShipSupply},
+		{name: "cloth suffix", commodity: "Persimmon cloth", want: CommodityCategoryRefined},
+		{name: "dye suffix", commodity: "Navy dye", want: CommodityCategoryRefined},
+		{name: "enamel suffix", commodity: "Lime enamel", want: CommodityCategoryRefined},
+		{name: "paint suffix", commodity: "Tan paint", want: CommodityCategoryRefined},
+		{name: "suffix needs a word before it", commodity: "Paint", want: CommodityCategoryBasic},
+		{name: "unknown commodity", commodity: "Bilge water", want: CommodityCategoryBasic},
+		{name: "empty name", commodity: "", want: CommodityCategoryBasic},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyCommodity(tt.commodity); got != tt.want {
+				t.Errorf("ClassifyCommodity(%q) = %q, want %q", tt.commodity, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,5 +1,10 @@
 package types
 
+import (
+	"fmt"
+	"strings"
+)
+
 type Ocean string
 
 const (
@@ -8,6 +13,11 @@ const (
 	OceanCerulean Ocean = "cerulean"
 )
 
+// AllOceans returns the oceans that are scraped, in display order
+func AllOceans() []Ocean {
+	return []Ocean{OceanEmerald, OceanMeridian, OceanCerulean}
+}
+
 func (o Ocean) String() string {
 	return string(o)
 }
@@ -18,4 +28,15 @@ func (o Ocean) IsValid() bool {
 		return true
 	}
 	return false
-}
\ No newline at end of file
+}
+
+func (o Ocean) DisplayName() string {
+	if o == "" {
+		return ""
+	}
+	return strings.ToUpper(string(o[:1])) + string(o[1:])
+}
+
+func (o Ocean) BaseURL() string {
+	return fmt.Sprintf("https://%s.puzzlepirates.com", o)
+}